@@ -35,10 +35,22 @@ func New(config Config) (*Collector, error) {
 }
 
 func (c *Collector) Collect(ch chan<- prometheus.Metric) {
+	ch <- prometheus.MustNewConstMetric(c.metric.Engine.Completed.Cal.Des() /***/, prometheus.CounterValue /***/, c.metric.Engine.Completed.Cal.Get())
+	ch <- prometheus.MustNewConstMetric(c.metric.Engine.Completed.Dur.Des() /***/, prometheus.GaugeValue /*****/, c.metric.Engine.Completed.Dur.Get())
+	ch <- prometheus.MustNewConstMetric(c.metric.Engine.Completed.Err.Des() /***/, prometheus.CounterValue /***/, c.metric.Engine.Completed.Err.Get())
+
 	ch <- prometheus.MustNewConstMetric(c.metric.Engine.Create.Cal.Des() /***/, prometheus.CounterValue /***/, c.metric.Engine.Create.Cal.Get())
 	ch <- prometheus.MustNewConstMetric(c.metric.Engine.Create.Dur.Des() /***/, prometheus.GaugeValue /*****/, c.metric.Engine.Create.Dur.Get())
 	ch <- prometheus.MustNewConstMetric(c.metric.Engine.Create.Err.Des() /***/, prometheus.CounterValue /***/, c.metric.Engine.Create.Err.Get())
 
+	ch <- prometheus.MustNewConstMetric(c.metric.Engine.CreateMany.Cal.Des() /***/, prometheus.CounterValue /***/, c.metric.Engine.CreateMany.Cal.Get())
+	ch <- prometheus.MustNewConstMetric(c.metric.Engine.CreateMany.Dur.Des() /***/, prometheus.GaugeValue /*****/, c.metric.Engine.CreateMany.Dur.Get())
+	ch <- prometheus.MustNewConstMetric(c.metric.Engine.CreateMany.Err.Des() /***/, prometheus.CounterValue /***/, c.metric.Engine.CreateMany.Err.Get())
+
+	ch <- prometheus.MustNewConstMetric(c.metric.Engine.Deadletter.Cal.Des() /***/, prometheus.CounterValue /***/, c.metric.Engine.Deadletter.Cal.Get())
+	ch <- prometheus.MustNewConstMetric(c.metric.Engine.Deadletter.Dur.Des() /***/, prometheus.GaugeValue /*****/, c.metric.Engine.Deadletter.Dur.Get())
+	ch <- prometheus.MustNewConstMetric(c.metric.Engine.Deadletter.Err.Des() /***/, prometheus.CounterValue /***/, c.metric.Engine.Deadletter.Err.Get())
+
 	ch <- prometheus.MustNewConstMetric(c.metric.Engine.Delete.Cal.Des() /***/, prometheus.CounterValue /***/, c.metric.Engine.Delete.Cal.Get())
 	ch <- prometheus.MustNewConstMetric(c.metric.Engine.Delete.Dur.Des() /***/, prometheus.GaugeValue /*****/, c.metric.Engine.Delete.Dur.Get())
 	ch <- prometheus.MustNewConstMetric(c.metric.Engine.Delete.Err.Des() /***/, prometheus.CounterValue /***/, c.metric.Engine.Delete.Err.Get())
@@ -55,14 +67,50 @@ func (c *Collector) Collect(ch chan<- prometheus.Metric) {
 	ch <- prometheus.MustNewConstMetric(c.metric.Engine.Extend.Dur.Des() /***/, prometheus.GaugeValue /*****/, c.metric.Engine.Extend.Dur.Get())
 	ch <- prometheus.MustNewConstMetric(c.metric.Engine.Extend.Err.Des() /***/, prometheus.CounterValue /***/, c.metric.Engine.Extend.Err.Get())
 
+	ch <- prometheus.MustNewConstMetric(c.metric.Engine.Heartbeat.Cal.Des() /***/, prometheus.CounterValue /***/, c.metric.Engine.Heartbeat.Cal.Get())
+	ch <- prometheus.MustNewConstMetric(c.metric.Engine.Heartbeat.Dur.Des() /***/, prometheus.GaugeValue /*****/, c.metric.Engine.Heartbeat.Dur.Get())
+	ch <- prometheus.MustNewConstMetric(c.metric.Engine.Heartbeat.Err.Des() /***/, prometheus.CounterValue /***/, c.metric.Engine.Heartbeat.Err.Get())
+
 	ch <- prometheus.MustNewConstMetric(c.metric.Engine.Lister.Cal.Des() /***/, prometheus.CounterValue /***/, c.metric.Engine.Lister.Cal.Get())
 	ch <- prometheus.MustNewConstMetric(c.metric.Engine.Lister.Dur.Des() /***/, prometheus.GaugeValue /*****/, c.metric.Engine.Lister.Dur.Get())
 	ch <- prometheus.MustNewConstMetric(c.metric.Engine.Lister.Err.Des() /***/, prometheus.CounterValue /***/, c.metric.Engine.Lister.Err.Get())
 
+	ch <- prometheus.MustNewConstMetric(c.metric.Engine.PurgeDeadletter.Cal.Des() /***/, prometheus.CounterValue /***/, c.metric.Engine.PurgeDeadletter.Cal.Get())
+	ch <- prometheus.MustNewConstMetric(c.metric.Engine.PurgeDeadletter.Dur.Des() /***/, prometheus.GaugeValue /*****/, c.metric.Engine.PurgeDeadletter.Dur.Get())
+	ch <- prometheus.MustNewConstMetric(c.metric.Engine.PurgeDeadletter.Err.Des() /***/, prometheus.CounterValue /***/, c.metric.Engine.PurgeDeadletter.Err.Get())
+
+	ch <- prometheus.MustNewConstMetric(c.metric.Engine.Redrive.Cal.Des() /***/, prometheus.CounterValue /***/, c.metric.Engine.Redrive.Cal.Get())
+	ch <- prometheus.MustNewConstMetric(c.metric.Engine.Redrive.Dur.Des() /***/, prometheus.GaugeValue /*****/, c.metric.Engine.Redrive.Dur.Get())
+	ch <- prometheus.MustNewConstMetric(c.metric.Engine.Redrive.Err.Des() /***/, prometheus.CounterValue /***/, c.metric.Engine.Redrive.Err.Get())
+
+	ch <- prometheus.MustNewConstMetric(c.metric.Engine.Registry.Cal.Des() /***/, prometheus.CounterValue /***/, c.metric.Engine.Registry.Cal.Get())
+	ch <- prometheus.MustNewConstMetric(c.metric.Engine.Registry.Dur.Des() /***/, prometheus.GaugeValue /*****/, c.metric.Engine.Registry.Dur.Get())
+	ch <- prometheus.MustNewConstMetric(c.metric.Engine.Registry.Err.Des() /***/, prometheus.CounterValue /***/, c.metric.Engine.Registry.Err.Get())
+
+	ch <- prometheus.MustNewConstMetric(c.metric.Engine.Release.Cal.Des() /***/, prometheus.CounterValue /***/, c.metric.Engine.Release.Cal.Get())
+	ch <- prometheus.MustNewConstMetric(c.metric.Engine.Release.Dur.Des() /***/, prometheus.GaugeValue /*****/, c.metric.Engine.Release.Dur.Get())
+	ch <- prometheus.MustNewConstMetric(c.metric.Engine.Release.Err.Des() /***/, prometheus.CounterValue /***/, c.metric.Engine.Release.Err.Get())
+
 	ch <- prometheus.MustNewConstMetric(c.metric.Engine.Search.Cal.Des() /***/, prometheus.CounterValue /***/, c.metric.Engine.Search.Cal.Get())
 	ch <- prometheus.MustNewConstMetric(c.metric.Engine.Search.Dur.Des() /***/, prometheus.GaugeValue /*****/, c.metric.Engine.Search.Dur.Get())
 	ch <- prometheus.MustNewConstMetric(c.metric.Engine.Search.Err.Des() /***/, prometheus.CounterValue /***/, c.metric.Engine.Search.Err.Get())
 
+	ch <- prometheus.MustNewConstMetric(c.metric.Engine.SearchN.Cal.Des() /***/, prometheus.CounterValue /***/, c.metric.Engine.SearchN.Cal.Get())
+	ch <- prometheus.MustNewConstMetric(c.metric.Engine.SearchN.Dur.Des() /***/, prometheus.GaugeValue /*****/, c.metric.Engine.SearchN.Dur.Get())
+	ch <- prometheus.MustNewConstMetric(c.metric.Engine.SearchN.Err.Des() /***/, prometheus.CounterValue /***/, c.metric.Engine.SearchN.Err.Get())
+
+	ch <- prometheus.MustNewConstMetric(c.metric.Engine.SearchWait.Cal.Des() /***/, prometheus.CounterValue /***/, c.metric.Engine.SearchWait.Cal.Get())
+	ch <- prometheus.MustNewConstMetric(c.metric.Engine.SearchWait.Dur.Des() /***/, prometheus.GaugeValue /*****/, c.metric.Engine.SearchWait.Dur.Get())
+	ch <- prometheus.MustNewConstMetric(c.metric.Engine.SearchWait.Err.Des() /***/, prometheus.CounterValue /***/, c.metric.Engine.SearchWait.Err.Get())
+
+	ch <- prometheus.MustNewConstMetric(c.metric.Engine.Template.Cal.Des() /***/, prometheus.CounterValue /***/, c.metric.Engine.Template.Cal.Get())
+	ch <- prometheus.MustNewConstMetric(c.metric.Engine.Template.Dur.Des() /***/, prometheus.GaugeValue /*****/, c.metric.Engine.Template.Dur.Get())
+	ch <- prometheus.MustNewConstMetric(c.metric.Engine.Template.Err.Des() /***/, prometheus.CounterValue /***/, c.metric.Engine.Template.Err.Get())
+
+	ch <- prometheus.MustNewConstMetric(c.metric.Task.Coalesce.Des() /*******/, prometheus.CounterValue /***/, c.metric.Task.Coalesce.Get())
+	ch <- prometheus.MustNewConstMetric(c.metric.Task.Deadletter.Des() /*****/, prometheus.GaugeValue /*****/, c.metric.Task.Deadletter.Get())
+	ch <- prometheus.MustNewConstMetric(c.metric.Task.Deadline.Des() /*******/, prometheus.CounterValue /***/, c.metric.Task.Deadline.Get())
+	ch <- prometheus.MustNewConstMetric(c.metric.Task.Dedupe.Des() /*********/, prometheus.CounterValue /***/, c.metric.Task.Dedupe.Get())
 	ch <- prometheus.MustNewConstMetric(c.metric.Task.Expired.Des() /********/, prometheus.CounterValue /***/, c.metric.Task.Expired.Get())
 	ch <- prometheus.MustNewConstMetric(c.metric.Task.Extended.Des() /*******/, prometheus.CounterValue /***/, c.metric.Task.Extended.Get())
 	ch <- prometheus.MustNewConstMetric(c.metric.Task.Inactive.Des() /*******/, prometheus.GaugeValue /*****/, c.metric.Task.Inactive.Get())
@@ -70,6 +118,11 @@ func (c *Collector) Collect(ch chan<- prometheus.Metric) {
 	ch <- prometheus.MustNewConstMetric(c.metric.Task.Obsolete.Des() /*******/, prometheus.CounterValue /***/, c.metric.Task.Obsolete.Get())
 	ch <- prometheus.MustNewConstMetric(c.metric.Task.Outdated.Des() /*******/, prometheus.CounterValue /***/, c.metric.Task.Outdated.Get())
 	ch <- prometheus.MustNewConstMetric(c.metric.Task.Parallel.Des() /*******/, prometheus.GaugeValue /*****/, c.metric.Task.Parallel.Get())
+	ch <- prometheus.MustNewConstMetric(c.metric.Task.Throttled.Des() /******/, prometheus.CounterValue /***/, c.metric.Task.Throttled.Get())
+
+	for k, v := range c.metric.Task.Saturation.Get() {
+		ch <- prometheus.MustNewConstMetric(c.metric.Task.Saturation.Des(), prometheus.GaugeValue, v, k)
+	}
 
 	c.metric.Reset()
 }
@@ -0,0 +1,30 @@
+package conformance
+
+import (
+	"time"
+
+	"github.com/xh3b4sd/rescue/store"
+	"github.com/xh3b4sd/tracer"
+)
+
+func musTim(str string) time.Time {
+	tim, err := time.Parse("2006-01-02T15:04:05.999999Z", str)
+	if err != nil {
+		panic(err)
+	}
+
+	return tim
+}
+
+// prgAll is a convenience function for purging all data of the underlying
+// storage system. The provided storage interface is returned as is.
+func prgAll(sto store.Interface) store.Interface {
+	{
+		err := sto.Purge()
+		if err != nil {
+			tracer.Panic(tracer.Mask(err))
+		}
+	}
+
+	return sto
+}
@@ -1,5 +1,3 @@
-//go:build redis
-
 package conformance
 
 import (
@@ -7,22 +5,22 @@ import (
 	"time"
 
 	"github.com/xh3b4sd/logger"
-	"github.com/xh3b4sd/redigo"
 	"github.com/xh3b4sd/rescue"
 	"github.com/xh3b4sd/rescue/engine"
+	"github.com/xh3b4sd/rescue/store"
 	"github.com/xh3b4sd/rescue/task"
 )
 
 func Test_Engine_Delete_Core(t *testing.T) {
 	var err error
 
-	var red redigo.Interface
+	var sto store.Interface
 	{
-		red = redigo.Default()
+		sto = defSto()
 	}
 
 	{
-		err = red.Purge()
+		err = sto.Purge()
 		if err != nil {
 			t.Fatal(err)
 		}
@@ -33,7 +31,7 @@ func Test_Engine_Delete_Core(t *testing.T) {
 		eon = engine.New(engine.Config{
 			Expiry: 1 * time.Millisecond,
 			Logger: logger.Fake(),
-			Redigo: red,
+			Store:  sto,
 			Worker: "eon",
 		})
 	}
@@ -43,7 +41,7 @@ func Test_Engine_Delete_Core(t *testing.T) {
 		etw = engine.New(engine.Config{
 			Expiry: 1 * time.Millisecond,
 			Logger: logger.Fake(),
-			Redigo: red,
+			Store:  sto,
 			Worker: "etw",
 		})
 	}
@@ -1,12 +1,9 @@
-//go:build redis
-
 package conformance
 
 import (
 	"testing"
 
 	"github.com/xh3b4sd/logger"
-	"github.com/xh3b4sd/redigo"
 	"github.com/xh3b4sd/rescue"
 	"github.com/xh3b4sd/rescue/engine"
 	"github.com/xh3b4sd/rescue/task"
@@ -19,7 +16,7 @@ func Test_Engine_Delete_Cron_Aevery(t *testing.T) {
 	{
 		eon = engine.New(engine.Config{
 			Logger: logger.Fake(),
-			Redigo: prgAll(redigo.Default()),
+			Store:  prgAll(defSto()),
 		})
 	}
 
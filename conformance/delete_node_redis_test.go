@@ -1,5 +1,3 @@
-//go:build redis
-
 package conformance
 
 import (
@@ -9,9 +7,9 @@ import (
 
 	"github.com/google/go-cmp/cmp"
 	"github.com/xh3b4sd/logger"
-	"github.com/xh3b4sd/redigo"
 	"github.com/xh3b4sd/rescue"
 	"github.com/xh3b4sd/rescue/engine"
+	"github.com/xh3b4sd/rescue/store"
 	"github.com/xh3b4sd/rescue/task"
 	"github.com/xh3b4sd/rescue/timer"
 )
@@ -23,7 +21,7 @@ func Test_Engine_Delete_Cron_Node_All(t *testing.T) {
 	{
 		eon = engine.New(engine.Config{
 			Logger: logger.Fake(),
-			Redigo: prgAll(redigo.Default()),
+			Store:  prgAll(defSto()),
 		})
 	}
 
@@ -118,13 +116,13 @@ func Test_Engine_Delete_Cron_Node_All(t *testing.T) {
 func Test_Engine_Delete_Gate_Node_All(t *testing.T) {
 	var err error
 
-	var red redigo.Interface
+	var sto store.Interface
 	{
-		red = redigo.Default()
+		sto = defSto()
 	}
 
 	{
-		err = red.Purge()
+		err = sto.Purge()
 		if err != nil {
 			t.Fatal(err)
 		}
@@ -134,7 +132,7 @@ func Test_Engine_Delete_Gate_Node_All(t *testing.T) {
 	{
 		eon = engine.New(engine.Config{
 			Logger: logger.Fake(),
-			Redigo: red,
+			Store:  sto,
 		})
 	}
 
@@ -248,7 +246,7 @@ func Test_Engine_Delete_Node_All_Purge(t *testing.T) {
 	{
 		eon = engine.New(engine.Config{
 			Logger: logger.Fake(),
-			Redigo: prgAll(redigo.Default()),
+			Store:  prgAll(defSto()),
 			Timer:  tim,
 			Worker: "eon",
 		})
@@ -258,7 +256,7 @@ func Test_Engine_Delete_Node_All_Purge(t *testing.T) {
 	{
 		etw = engine.New(engine.Config{
 			Logger: logger.Fake(),
-			Redigo: prgAll(redigo.Default()),
+			Store:  prgAll(defSto()),
 			Timer:  tim,
 			Worker: "etw",
 		})
@@ -1,5 +1,3 @@
-//go:build redis
-
 package conformance
 
 import (
@@ -9,7 +7,6 @@ import (
 
 	"github.com/google/go-cmp/cmp"
 	"github.com/xh3b4sd/logger"
-	"github.com/xh3b4sd/redigo"
 	"github.com/xh3b4sd/rescue"
 	"github.com/xh3b4sd/rescue/engine"
 	"github.com/xh3b4sd/rescue/task"
@@ -34,7 +31,7 @@ func Test_Engine_Delete_Sync(t *testing.T) {
 	{
 		eon = engine.New(engine.Config{
 			Logger: logger.Fake(),
-			Redigo: prgAll(redigo.Default()),
+			Store:  prgAll(defSto()),
 			Timer:  tim,
 		})
 	}
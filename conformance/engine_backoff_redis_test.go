@@ -0,0 +1,235 @@
+package conformance
+
+import (
+	"testing"
+	"time"
+
+	"github.com/xh3b4sd/logger"
+	"github.com/xh3b4sd/rescue"
+	"github.com/xh3b4sd/rescue/engine"
+	"github.com/xh3b4sd/rescue/store"
+	"github.com/xh3b4sd/rescue/task"
+	"github.com/xh3b4sd/rescue/timer"
+)
+
+// Test_Engine_Backoff ensures that expired and released tasks are only claimed
+// again once their retry delay passed, and that the retry delay grows with the
+// amount of cycles that a task went through.
+func Test_Engine_Backoff(t *testing.T) {
+	var err error
+
+	var tim *timer.Timer
+	{
+		tim = timer.New()
+	}
+
+	{
+		tim.Setter(func() time.Time {
+			return musTim("2023-10-20T00:00:00Z")
+		})
+	}
+
+	var sto store.Interface
+	{
+		sto = prgAll(defSto())
+	}
+
+	var bck engine.Backoff
+	{
+		bck = engine.Backoff{
+			Base: time.Minute,
+		}
+	}
+
+	var eon rescue.Interface
+	{
+		eon = engine.New(engine.Config{
+			Backoff: bck,
+			Logger:  logger.Fake(),
+			Store:   sto,
+			Timer:   tim,
+			Worker:  "eon",
+		})
+	}
+
+	var etw rescue.Interface
+	{
+		etw = engine.New(engine.Config{
+			Backoff: bck,
+			Logger:  logger.Fake(),
+			Store:   sto,
+			Timer:   tim,
+			Worker:  "etw",
+		})
+	}
+
+	{
+		err = eon.Create(&task.Task{Meta: &task.Meta{"test.api.io/key": "foo"}})
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	var tas *task.Task
+	{
+		tas, err = eon.Search()
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	// Let the task expire, so that it is retried after 1 minute.
+	{
+		tim.Setter(func() time.Time {
+			return musTim("2023-10-20T00:00:31Z")
+		})
+	}
+
+	{
+		err = etw.Expire()
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	{
+		lis, err := etw.Lister(engine.All())
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if !lis[0].Cron.Get().TickP1().Equal(musTim("2023-10-20T00:01:31Z")) {
+			t.Fatal("expected", musTim("2023-10-20T00:01:31Z"), "got", lis[0].Cron.Get().TickP1())
+		}
+	}
+
+	{
+		_, err = etw.Search()
+		if !engine.IsTaskNotFound(err) {
+			t.Fatal("expected", "taskNotFoundError", "got", err)
+		}
+	}
+
+	{
+		tim.Setter(func() time.Time {
+			return musTim("2023-10-20T00:01:31Z")
+		})
+	}
+
+	{
+		tas, err = etw.Search()
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		// Retried tasks do not turn into deferred tasks, and their retry delay
+		// is removed once they got claimed again.
+		if tas.Cron != nil {
+			t.Fatal("expected", nil, "got", tas.Cron)
+		}
+	}
+
+	// Release the task, so that it is retried after 2 minutes.
+	{
+		err = etw.Release(tas, engine.ReleaseOptions{})
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	{
+		tim.Setter(func() time.Time {
+			return musTim("2023-10-20T00:03:30Z")
+		})
+	}
+
+	{
+		_, err = eon.Search()
+		if !engine.IsTaskNotFound(err) {
+			t.Fatal("expected", "taskNotFoundError", "got", err)
+		}
+	}
+
+	{
+		tim.Setter(func() time.Time {
+			return musTim("2023-10-20T00:03:31Z")
+		})
+	}
+
+	{
+		tas, err = eon.Search()
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if tas.Core.Get().Cycles() != 2 {
+			t.Fatal("expected", 2, "got", tas.Core.Get().Cycles())
+		}
+	}
+
+	// Retried tasks are completed for good once they got deleted.
+	{
+		err = eon.Delete(tas)
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	{
+		lis, err := eon.Lister(engine.All())
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if len(lis) != 0 {
+			t.Fatal("expected", 0, "got", len(lis))
+		}
+	}
+
+	// Retried tasks are removed like any other task once the retention period
+	// passed.
+	{
+		err = eon.Create(&task.Task{Meta: &task.Meta{"test.api.io/key": "bar"}})
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	{
+		tas, err = eon.Search()
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	{
+		err = eon.Release(tas, engine.ReleaseOptions{})
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	{
+		tim.Setter(func() time.Time {
+			return musTim("2023-10-28T00:00:00Z")
+		})
+	}
+
+	{
+		err = eon.Expire()
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	{
+		lis, err := eon.Lister(engine.All())
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if len(lis) != 0 {
+			t.Fatal("expected", 0, "got", len(lis))
+		}
+	}
+}
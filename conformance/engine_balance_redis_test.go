@@ -1,5 +1,3 @@
-//go:build redis
-
 package conformance
 
 import (
@@ -7,7 +5,6 @@ import (
 	"testing"
 
 	"github.com/xh3b4sd/logger"
-	"github.com/xh3b4sd/redigo"
 	"github.com/xh3b4sd/rescue"
 	"github.com/xh3b4sd/rescue/engine"
 	"github.com/xh3b4sd/rescue/task"
@@ -20,7 +17,7 @@ func Test_Engine_Balance(t *testing.T) {
 	{
 		eon = engine.New(engine.Config{
 			Logger: logger.Fake(),
-			Redigo: prgAll(redigo.Default()),
+			Store:  prgAll(defSto()),
 			Worker: "eon",
 		})
 	}
@@ -29,7 +26,7 @@ func Test_Engine_Balance(t *testing.T) {
 	{
 		etw = engine.New(engine.Config{
 			Logger: logger.Fake(),
-			Redigo: prgAll(redigo.Default()),
+			Store:  prgAll(defSto()),
 			Worker: "etw",
 		})
 	}
@@ -38,7 +35,7 @@ func Test_Engine_Balance(t *testing.T) {
 	{
 		eth = engine.New(engine.Config{
 			Logger: logger.Fake(),
-			Redigo: prgAll(redigo.Default()),
+			Store:  prgAll(defSto()),
 			Worker: "eth",
 		})
 	}
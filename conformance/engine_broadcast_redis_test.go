@@ -0,0 +1,285 @@
+package conformance
+
+import (
+	"fmt"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/xh3b4sd/logger"
+	"github.com/xh3b4sd/rescue"
+	"github.com/xh3b4sd/rescue/engine"
+	"github.com/xh3b4sd/rescue/store"
+	"github.com/xh3b4sd/rescue/task"
+	"github.com/xh3b4sd/rescue/timer"
+)
+
+// Test_Engine_Broadcast ensures that every worker completing a task defining
+// the delivery method "all" is recorded within the stored task, and that
+// workers restarting with the same identifier resume processing broadcasted
+// tasks without processing completed tasks again.
+func Test_Engine_Broadcast(t *testing.T) {
+	var err error
+
+	var now time.Time
+	{
+		now = musTim("2023-10-20T00:00:00Z")
+	}
+
+	var tim *timer.Timer
+	{
+		tim = timer.New()
+	}
+
+	{
+		tim.Setter(func() time.Time {
+			return now
+		})
+	}
+
+	var sto store.Interface
+	{
+		sto = prgAll(defSto())
+	}
+
+	var eon rescue.Interface
+	{
+		eon = engine.New(engine.Config{
+			Logger: logger.Fake(),
+			Store:  sto,
+			Timer:  tim,
+			Worker: "eon",
+		})
+	}
+
+	var etw rescue.Interface
+	{
+		etw = engine.New(engine.Config{
+			Logger: logger.Fake(),
+			Store:  sto,
+			Timer:  tim,
+			Worker: "etw",
+		})
+	}
+
+	{
+		err = eon.Heartbeat()
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	{
+		now = now.Add(time.Second)
+	}
+
+	for i := 0; i < 2; i++ {
+		err = eon.Create(&task.Task{
+			Meta: &task.Meta{"test.api.io/key": "val"},
+			Node: &task.Node{task.Method: task.MthdAll},
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	var tas *task.Task
+	{
+		tas, err = eon.Search()
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	{
+		err = eon.Delete(tas)
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	{
+		wrk, err := eon.Completed(tas)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if dif := cmp.Diff([]string{"eon"}, wrk); dif != "" {
+			t.Fatalf("-expected +actual:\n%s", dif)
+		}
+	}
+
+	// eon restarts using the same worker identifier, without processing the
+	// second broadcasted task before.
+	{
+		now = now.Add(time.Second)
+	}
+
+	{
+		eon = engine.New(engine.Config{
+			Logger: logger.Fake(),
+			Store:  sto,
+			Timer:  tim,
+			Worker: "eon",
+		})
+	}
+
+	// eon must only process the broadcasted task that it did not complete before
+	// restarting, even without sending its first heartbeat after restarting.
+	{
+		lis, err := eon.SearchN(10)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if len(lis) != 1 {
+			t.Fatal("expected", 1, "got", len(lis))
+		}
+		if lis[0].Core.Get().Object() == tas.Core.Get().Object() {
+			t.Fatal("expected", "other task", "got", "completed task")
+		}
+	}
+
+	{
+		_, err = etw.SearchN(10)
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	{
+		err = etw.Delete(tas)
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	{
+		wrk, err := etw.Completed(tas)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if dif := cmp.Diff([]string{"eon", "etw"}, wrk); dif != "" {
+			t.Fatalf("-expected +actual:\n%s", dif)
+		}
+	}
+}
+
+// Test_Engine_Broadcast_Concurrent ensures that many workers can complete the
+// same broadcasted task concurrently, and that their recorded completions are
+// removed together with the task.
+func Test_Engine_Broadcast_Concurrent(t *testing.T) {
+	var err error
+
+	var now time.Time
+	{
+		now = musTim("2023-10-20T00:00:00Z")
+	}
+
+	var tim *timer.Timer
+	{
+		tim = timer.New()
+	}
+
+	{
+		tim.Setter(func() time.Time {
+			return now
+		})
+	}
+
+	var sto store.Interface
+	{
+		sto = prgAll(defSto())
+	}
+
+	var eng []rescue.Interface
+	for i := 0; i < 10; i++ {
+		eng = append(eng, engine.New(engine.Config{
+			Logger: logger.Fake(),
+			Store:  sto,
+			Timer:  tim,
+			Worker: fmt.Sprintf("w%d", i),
+		}))
+	}
+
+	{
+		now = now.Add(time.Second)
+	}
+
+	var tas *task.Task
+	{
+		tas = &task.Task{
+			Meta: &task.Meta{"test.api.io/key": "val"},
+			Node: &task.Node{task.Method: task.MthdAll},
+		}
+
+		err = eng[0].Create(tas)
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	var lis []*task.Task
+	for _, x := range eng {
+		tas, err := x.Search()
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		lis = append(lis, tas)
+	}
+
+	var wai sync.WaitGroup
+	for i, x := range eng {
+		wai.Add(1)
+		go func(x rescue.Interface, tas *task.Task) {
+			defer wai.Done()
+
+			err := x.Delete(tas)
+			if err != nil {
+				t.Error(err)
+			}
+		}(x, lis[i])
+	}
+
+	{
+		wai.Wait()
+	}
+
+	{
+		com, err := eng[0].Completed(tas)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if len(com) != len(eng) {
+			t.Fatal("expected", len(eng), "got", len(com))
+		}
+	}
+
+	// The broadcasted task and its recorded completions are removed once the
+	// retention period passed.
+	{
+		now = now.Add(engine.Week + time.Second)
+	}
+
+	{
+		err = eng[0].Expire()
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	{
+		mem, err := sto.Member(eng[0].Keyfmt() + ":complete:" + tas.Core.Map().Object())
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if len(mem) != 0 {
+			t.Fatal("expected", 0, "got", len(mem))
+		}
+	}
+}
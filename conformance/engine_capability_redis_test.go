@@ -0,0 +1,186 @@
+package conformance
+
+import (
+	"testing"
+
+	"github.com/xh3b4sd/logger"
+	"github.com/xh3b4sd/rescue"
+	"github.com/xh3b4sd/rescue/engine"
+	"github.com/xh3b4sd/rescue/store"
+	"github.com/xh3b4sd/rescue/task"
+)
+
+// Test_Engine_Capability ensures that workers only claim tasks that they are
+// capable of processing.
+func Test_Engine_Capability(t *testing.T) {
+	var err error
+
+	var sto store.Interface
+	{
+		sto = prgAll(defSto())
+	}
+
+	var eon rescue.Interface
+	{
+		eon = engine.New(engine.Config{
+			Capability: []*task.Meta{
+				{"test.api.io/kind": "foo"},
+			},
+			Logger: logger.Fake(),
+			Store:  sto,
+			Worker: "eon",
+		})
+	}
+
+	var etw rescue.Interface
+	{
+		etw = engine.New(engine.Config{
+			Capability: []*task.Meta{
+				{"test.api.io/kind": "bar"},
+			},
+			Logger: logger.Fake(),
+			Store:  sto,
+			Worker: "etw",
+		})
+	}
+
+	for _, x := range []string{"bar", "foo", "bar", "foo"} {
+		err = eon.Create(&task.Task{Meta: &task.Meta{"test.api.io/kind": x}})
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	{
+		err = eon.Create(&task.Task{Meta: &task.Meta{"test.api.io/kind": "bar"}, Node: &task.Node{task.Method: task.MthdAll}})
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	{
+		lis, err := eon.SearchN(10)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if len(lis) != 2 {
+			t.Fatal("expected", 2, "got", len(lis))
+		}
+
+		for _, x := range lis {
+			if x.Meta.Get("test.api.io/kind") != "foo" {
+				t.Fatal("expected", "foo", "got", x.Meta.Get("test.api.io/kind"))
+			}
+		}
+	}
+
+	{
+		_, err = eon.Search()
+		if !engine.IsTaskNotFound(err) {
+			t.Fatal("expected", "taskNotFoundError", "got", err)
+		}
+	}
+
+	// The balancer must only account for the tasks that a worker is capable of
+	// processing. Otherwise etw would not be allowed to claim more tasks than
+	// eon owns already.
+	for i := 0; i < 3; i++ {
+		lis, err := etw.SearchN(10)
+		if engine.IsTaskNotFound(err) {
+			break
+		} else if err != nil {
+			t.Fatal(err)
+		}
+
+		for _, x := range lis {
+			if x.Meta.Get("test.api.io/kind") != "bar" {
+				t.Fatal("expected", "bar", "got", x.Meta.Get("test.api.io/kind"))
+			}
+		}
+	}
+
+	{
+		lis, err := eon.Lister(engine.All())
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		for _, x := range lis {
+			if x.Node.Get(task.Method) == task.MthdAll {
+				continue
+			}
+
+			if x.Core.Get().Worker() == "" {
+				t.Fatal("expected", "owned task", "got", "unowned task")
+			}
+		}
+	}
+}
+
+// Test_Engine_Capability_Registry ensures that live workers are only balanced
+// against for tasks that they are capable of processing.
+func Test_Engine_Capability_Registry(t *testing.T) {
+	var err error
+
+	var sto store.Interface
+	{
+		sto = prgAll(defSto())
+	}
+
+	var eon rescue.Interface
+	{
+		eon = engine.New(engine.Config{
+			Capability: []*task.Meta{
+				{"test.api.io/kind": "cpu"},
+			},
+			Logger: logger.Fake(),
+			Store:  sto,
+			Worker: "a",
+		})
+	}
+
+	var etw rescue.Interface
+	{
+		etw = engine.New(engine.Config{
+			Capability: []*task.Meta{
+				{"test.api.io/kind": "gpu"},
+			},
+			Logger: logger.Fake(),
+			Store:  sto,
+			Worker: "b",
+		})
+	}
+
+	for _, x := range []rescue.Interface{eon, etw} {
+		err = x.Heartbeat()
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	{
+		err = eon.Create(&task.Task{Meta: &task.Meta{"test.api.io/kind": "gpu"}})
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	{
+		_, err = eon.Search()
+		if !engine.IsTaskNotFound(err) {
+			t.Fatal("expected", "taskNotFoundError", "got", err)
+		}
+	}
+
+	{
+		tas, err := etw.Search()
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if tas.Core.Get().Worker() != "b" {
+			t.Fatal("expected", "b", "got", tas.Core.Get().Worker())
+		}
+	}
+}
@@ -0,0 +1,420 @@
+package conformance
+
+import (
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/xh3b4sd/logger"
+	"github.com/xh3b4sd/objectid"
+	"github.com/xh3b4sd/rescue"
+	"github.com/xh3b4sd/rescue/engine"
+	"github.com/xh3b4sd/rescue/task"
+	"github.com/xh3b4sd/rescue/timer"
+)
+
+// Test_Engine_Coalesce ensures that newer tasks supersede older tasks defining
+// the same coalescing selector and Task.Meta values, as long as the older tasks
+// are not owned by any worker.
+func Test_Engine_Coalesce(t *testing.T) {
+	var err error
+
+	var now time.Time
+	{
+		now = musTim("2023-10-20T00:00:00Z")
+	}
+
+	var tim *timer.Timer
+	{
+		tim = timer.New()
+	}
+
+	{
+		tim.Setter(func() time.Time {
+			return now
+		})
+	}
+
+	var eon rescue.Interface
+	{
+		eon = engine.New(engine.Config{
+			Logger: logger.Fake(),
+			Store:  prgAll(defSto()),
+			Timer:  tim,
+			Worker: "eon",
+		})
+	}
+
+	cre := func(obj string, ver string, coa bool) objectid.ID {
+		tas := &task.Task{
+			Meta: &task.Meta{
+				"test.api.io/action": "recompute",
+				"test.api.io/object": obj,
+			},
+			Sync: &task.Sync{
+				"test.api.io/version": ver,
+			},
+		}
+
+		if coa {
+			tas.Core = &task.Core{
+				task.Coalesce: "test.api.io/object",
+			}
+		}
+
+		err := eon.Create(tas)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		now = now.Add(time.Second)
+
+		return tas.Core.Get().Object()
+	}
+
+	lis := func(exp int) []*task.Task {
+		lis, err := eon.Lister(engine.All())
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if len(lis) != exp {
+			t.Fatal("expected", exp, "got", len(lis))
+		}
+
+		return lis
+	}
+
+	var one objectid.ID
+	{
+		one = cre("1", "1", true)
+	}
+
+	// The newer task must supersede the older task, which keeps its object ID
+	// but adopts the newer Task.Sync.
+	{
+		oid := cre("1", "2", true)
+		if oid != one {
+			t.Fatal("expected", one, "got", oid)
+		}
+	}
+
+	{
+		lis := lis(1)
+
+		if lis[0].Sync.Get("test.api.io/version") != "2" {
+			t.Fatal("expected", "2", "got", lis[0].Sync.Get("test.api.io/version"))
+		}
+	}
+
+	// Tasks selecting different Task.Meta values must not supersede each other.
+	{
+		oid := cre("2", "1", true)
+		if oid == one {
+			t.Fatal("expected", "new object ID", "got", oid)
+		}
+	}
+
+	// Tasks not defining a coalescing selector must not supersede any task.
+	{
+		oid := cre("1", "3", false)
+		if oid == one {
+			t.Fatal("expected", "new object ID", "got", oid)
+		}
+	}
+
+	{
+		lis(3)
+	}
+
+	{
+		tas, err := eon.Search()
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if tas.Core.Get().Object() != one {
+			t.Fatal("expected", one, "got", tas.Core.Get().Object())
+		}
+	}
+
+	// Tasks owned by a worker must not be superseded.
+	{
+		oid := cre("1", "4", true)
+		if oid == one {
+			t.Fatal("expected", "new object ID", "got", oid)
+		}
+	}
+
+	{
+		lis(4)
+	}
+
+	{
+		err = eon.CreateMany([]*task.Task{{Core: &task.Core{task.Coalesce: "test.api.io/object"}, Meta: &task.Meta{"test.api.io/object": "3"}}})
+		if !engine.IsTaskCore(err) {
+			t.Fatal("expected", "taskCoreError", "got", err)
+		}
+	}
+}
+
+// Test_Engine_Coalesce_Runnable ensures that newer tasks do not supersede
+// older tasks that are not runnable, or that are addressed to other workers.
+func Test_Engine_Coalesce_Runnable(t *testing.T) {
+	testCases := []struct {
+		bck engine.Backoff
+		cor task.Core
+		nod *task.Node
+		fun func(rescue.Interface, *time.Time)
+		sup bool
+	}{
+		// Case 000 ensures that halted tasks are not superseded.
+		{
+			cor: task.Core{
+				task.Cancel: "1",
+			},
+			fun: func(eon rescue.Interface, now *time.Time) {
+				tas, err := eon.Search()
+				if err != nil {
+					t.Fatal(err)
+				}
+
+				err = eon.Release(tas, engine.ReleaseOptions{})
+				if err != nil {
+					t.Fatal(err)
+				}
+			},
+			sup: false,
+		},
+		// Case 001 ensures that tasks deferred by the backoff policy are not
+		// superseded.
+		{
+			bck: engine.Backoff{
+				Base: time.Minute,
+			},
+			fun: func(eon rescue.Interface, now *time.Time) {
+				tas, err := eon.Search()
+				if err != nil {
+					t.Fatal(err)
+				}
+
+				err = eon.Release(tas, engine.ReleaseOptions{})
+				if err != nil {
+					t.Fatal(err)
+				}
+			},
+			sup: false,
+		},
+		// Case 002 ensures that tasks missing their deadline are not superseded.
+		{
+			cor: task.Core{
+				task.Deadline: "2023-10-20T00:00:01Z",
+			},
+			fun: func(eon rescue.Interface, now *time.Time) {
+				*now = now.Add(2 * time.Second)
+			},
+			sup: false,
+		},
+		// Case 003 ensures that tasks addressed to a specific worker are not
+		// superseded by tasks addressed to any worker.
+		{
+			nod: &task.Node{
+				task.Method: task.MthdUni,
+				task.Worker: "eon",
+			},
+			sup: false,
+		},
+		// Case 004 ensures that tasks defining another circuit breaker are not
+		// superseded.
+		{
+			cor: task.Core{
+				task.Cancel: "3",
+			},
+			sup: false,
+		},
+		// Case 005 ensures that tasks released without backoff policy are
+		// superseded.
+		{
+			fun: func(eon rescue.Interface, now *time.Time) {
+				tas, err := eon.Search()
+				if err != nil {
+					t.Fatal(err)
+				}
+
+				err = eon.Release(tas, engine.ReleaseOptions{})
+				if err != nil {
+					t.Fatal(err)
+				}
+			},
+			sup: true,
+		},
+	}
+
+	for i, tc := range testCases {
+		t.Run(fmt.Sprintf("%03d", i), func(t *testing.T) {
+			var now time.Time
+			{
+				now = musTim("2023-10-20T00:00:00Z")
+			}
+
+			var tim *timer.Timer
+			{
+				tim = timer.New()
+			}
+
+			{
+				tim.Setter(func() time.Time {
+					return now
+				})
+			}
+
+			var eon rescue.Interface
+			{
+				eon = engine.New(engine.Config{
+					Backoff: tc.bck,
+					Logger:  logger.Fake(),
+					Store:   prgAll(defSto()),
+					Timer:   tim,
+					Worker:  "eon",
+				})
+			}
+
+			var cor task.Core
+			{
+				cor = task.Core{
+					task.Coalesce: "test.api.io/object",
+				}
+			}
+
+			for k, v := range tc.cor {
+				cor[k] = v
+			}
+
+			var one *task.Task
+			{
+				one = &task.Task{
+					Core: &cor,
+					Meta: &task.Meta{
+						"test.api.io/object": "1",
+					},
+					Node: tc.nod,
+				}
+			}
+
+			{
+				err := eon.Create(one)
+				if err != nil {
+					t.Fatal(err)
+				}
+			}
+
+			if tc.fun != nil {
+				tc.fun(eon, &now)
+			}
+
+			var two *task.Task
+			{
+				two = &task.Task{
+					Core: &task.Core{
+						task.Coalesce: "test.api.io/object",
+					},
+					Meta: &task.Meta{
+						"test.api.io/object": "1",
+					},
+				}
+			}
+
+			{
+				err := eon.Create(two)
+				if err != nil {
+					t.Fatal(err)
+				}
+			}
+
+			sup := two.Core.Get().Object() == one.Core.Get().Object()
+			if sup != tc.sup {
+				t.Fatal("expected", tc.sup, "got", sup)
+			}
+		})
+	}
+}
+
+// Test_Engine_Coalesce_Adopt ensures that superseded tasks adopt the deadline,
+// the lease and the priority of the newer task.
+func Test_Engine_Coalesce_Adopt(t *testing.T) {
+	var err error
+
+	var eon rescue.Interface
+	{
+		eon = engine.New(engine.Config{
+			Logger: logger.Fake(),
+			Store:  prgAll(defSto()),
+			Worker: "eon",
+		})
+	}
+
+	var one *task.Task
+	{
+		one = &task.Task{
+			Core: &task.Core{
+				task.Coalesce: "test.api.io/object",
+				task.Deadline: "2999-10-20T00:00:00Z",
+				task.Lease:    "5 minutes",
+			},
+			Meta: &task.Meta{
+				"test.api.io/object": "1",
+			},
+		}
+	}
+
+	{
+		err = eon.Create(one)
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	var two *task.Task
+	{
+		two = &task.Task{
+			Core: &task.Core{
+				task.Coalesce: "test.api.io/object",
+				task.Deadline: "2999-10-21T00:00:00Z",
+				task.Priority: "3",
+			},
+			Meta: &task.Meta{
+				"test.api.io/object": "1",
+			},
+		}
+	}
+
+	{
+		err = eon.Create(two)
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	{
+		if two.Core.Get().Object() != one.Core.Get().Object() {
+			t.Fatal("expected", one.Core.Get().Object(), "got", two.Core.Get().Object())
+		}
+	}
+
+	{
+		tas, err := eon.Search()
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if tas.Core.Map().Deadline() != "2999-10-21T00:00:00Z" {
+			t.Fatal("expected", "2999-10-21T00:00:00Z", "got", tas.Core.Map().Deadline())
+		}
+		if tas.Core.Exi().Lease() {
+			t.Fatal("expected", "no lease", "got", tas.Core.Get().Lease())
+		}
+		if tas.Core.Get().Priority() != 3 {
+			t.Fatal("expected", 3, "got", tas.Core.Get().Priority())
+		}
+	}
+}
@@ -0,0 +1,180 @@
+package conformance
+
+import (
+	"fmt"
+	"sync"
+	"testing"
+
+	"github.com/xh3b4sd/logger"
+	"github.com/xh3b4sd/rescue"
+	"github.com/xh3b4sd/rescue/engine"
+	"github.com/xh3b4sd/rescue/metric"
+	"github.com/xh3b4sd/rescue/store"
+	"github.com/xh3b4sd/rescue/task"
+)
+
+// Test_Engine_Concurrency ensures that workers never own more tasks matching a
+// concurrency rule than the rule's limit allows.
+func Test_Engine_Concurrency(t *testing.T) {
+	var err error
+
+	var met *metric.Collection
+	{
+		met = metric.Default()
+	}
+
+	var eon rescue.Interface
+	{
+		eon = engine.New(engine.Config{
+			Concurrency: []engine.Concurrency{
+				{Limit: 2, Meta: &task.Meta{"test.api.io/customer": "1234"}},
+			},
+			Logger: logger.Fake(),
+			Metric: met,
+			Store:  prgAll(defSto()),
+			Worker: "eon",
+		})
+	}
+
+	for _, x := range []string{"1234", "1234", "1234", "5678", "5678"} {
+		err = eon.Create(&task.Task{Meta: &task.Meta{"test.api.io/customer": x}})
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	var own []*task.Task
+	{
+		own, err = eon.SearchN(10)
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	{
+		cnt := map[string]int{}
+		for _, x := range own {
+			cnt[x.Meta.Get("test.api.io/customer")]++
+		}
+
+		if cnt["1234"] != 2 {
+			t.Fatal("expected", 2, "got", cnt["1234"])
+		}
+		if cnt["5678"] != 2 {
+			t.Fatal("expected", 2, "got", cnt["5678"])
+		}
+	}
+
+	{
+		sat := met.Task.Saturation.Get()["test.api.io/customer=1234"]
+		if sat != 1 {
+			t.Fatal("expected", 1, "got", sat)
+		}
+	}
+
+	{
+		_, err = eon.Search()
+		if !engine.IsTaskNotFound(err) {
+			t.Fatal("expected", "taskNotFoundError", "got", err)
+		}
+	}
+
+	// Completing a limited task allows another matching task to be claimed.
+	for _, x := range own {
+		if x.Meta.Get("test.api.io/customer") != "1234" {
+			continue
+		}
+
+		err = eon.Delete(x)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		break
+	}
+
+	{
+		tas, err := eon.Search()
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if tas.Meta.Get("test.api.io/customer") != "1234" {
+			t.Fatal("expected", "1234", "got", tas.Meta.Get("test.api.io/customer"))
+		}
+	}
+}
+
+// Test_Engine_Concurrency_Race ensures that concurrency rules hold across many
+// workers claiming tasks concurrently.
+func Test_Engine_Concurrency_Race(t *testing.T) {
+	var err error
+
+	var sto store.Interface
+	{
+		sto = prgAll(defSto())
+	}
+
+	var eng []rescue.Interface
+	for i := 0; i < 5; i++ {
+		eng = append(eng, engine.New(engine.Config{
+			Concurrency: []engine.Concurrency{
+				{Limit: 3, Meta: &task.Meta{"test.api.io/customer": "1234"}},
+			},
+			Logger: logger.Fake(),
+			Store:  sto,
+			Worker: fmt.Sprintf("e%02d", i),
+		}))
+	}
+
+	for i := 0; i < 20; i++ {
+		err = eng[0].Create(&task.Task{Meta: &task.Meta{"test.api.io/customer": "1234"}})
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	var wai sync.WaitGroup
+
+	for _, x := range eng {
+		wai.Add(1)
+
+		go func(x rescue.Interface) {
+			defer wai.Done()
+
+			for j := 0; j < 5; j++ {
+				_, err := x.Search()
+				if engine.IsTaskNotFound(err) {
+					continue
+				} else if err != nil {
+					panic(err)
+				}
+			}
+		}(x)
+	}
+
+	{
+		wai.Wait()
+	}
+
+	var lis []*task.Task
+	{
+		lis, err = eng[0].Lister(engine.All())
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	var own int
+	for _, x := range lis {
+		if x.Core.Get().Worker() != "" {
+			own++
+		}
+	}
+
+	{
+		if own != 3 {
+			t.Fatal("expected", 3, "got", own)
+		}
+	}
+}
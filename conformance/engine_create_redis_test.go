@@ -1,18 +1,18 @@
-//go:build redis
-
 package conformance
 
 import (
 	"fmt"
 	"reflect"
+	"strings"
 	"testing"
 	"time"
 
 	"github.com/google/go-cmp/cmp"
 	"github.com/xh3b4sd/logger"
-	"github.com/xh3b4sd/redigo"
+	"github.com/xh3b4sd/objectid"
 	"github.com/xh3b4sd/rescue"
 	"github.com/xh3b4sd/rescue/engine"
+	"github.com/xh3b4sd/rescue/store"
 	"github.com/xh3b4sd/rescue/task"
 	"github.com/xh3b4sd/rescue/timer"
 )
@@ -24,7 +24,7 @@ func Test_Engine_Create_Basic(t *testing.T) {
 	{
 		eon = engine.New(engine.Config{
 			Logger: logger.Fake(),
-			Redigo: prgAll(redigo.Default()),
+			Store:  prgAll(defSto()),
 		})
 	}
 
@@ -430,7 +430,7 @@ func Test_Engine_Create_Cron(t *testing.T) {
 	{
 		eon = engine.New(engine.Config{
 			Logger: logger.Fake(),
-			Redigo: prgAll(redigo.Default()),
+			Store:  prgAll(defSto()),
 			Timer:  tim,
 		})
 	}
@@ -649,6 +649,92 @@ func Test_Engine_Create_Cron(t *testing.T) {
 	}
 }
 
+// Test_Engine_Create_Failure ensures that tasks failing to be created do
+// neither get modified, nor leave any index entry behind, and that tasks are
+// retried using new object IDs if their object ID is taken already.
+func Test_Engine_Create_Failure(t *testing.T) {
+	var err error
+
+	var sto *collideCreate
+	{
+		sto = &collideCreate{Interface: prgAll(defSto())}
+	}
+
+	var eon rescue.Interface
+	{
+		eon = engine.New(engine.Config{
+			Logger: logger.Fake(),
+			Store:  sto,
+		})
+	}
+
+	{
+		sto.key = eon.Keyfmt()
+	}
+
+	var idx string
+	{
+		idx = fmt.Sprintf("%s:index:%q=%q", eon.Keyfmt(), "test.api.io/key", "foo")
+	}
+
+	var tas *task.Task
+	{
+		tas = &task.Task{Meta: &task.Meta{"test.api.io/key": "foo"}}
+	}
+
+	// Every attempt to create the task collides with an existing object ID.
+	{
+		sto.cou = engine.Retry
+	}
+
+	{
+		err = eon.Create(tas)
+		if err == nil {
+			t.Fatal("expected", "error", "got", nil)
+		}
+	}
+
+	{
+		if tas.Core != nil || tas.Node != nil {
+			t.Fatal("expected", "unmodified task", "got", tas)
+		}
+	}
+
+	{
+		mem, err := sto.Member(idx)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if len(mem) != 0 {
+			t.Fatal("expected", 0, "got", len(mem))
+		}
+	}
+
+	// The first attempt to create the task collides with an existing object ID.
+	{
+		sto.cou = 1
+	}
+
+	{
+		err = eon.Create(tas)
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	{
+		mem, err := sto.Member(idx)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if dif := cmp.Diff([]string{tas.Core.Map().Object()}, mem); dif != "" {
+			t.Fatalf("-expected +actual:\n%s", dif)
+		}
+	}
+}
+
 func Test_Engine_Create_Node_All(t *testing.T) {
 	var err error
 
@@ -667,7 +753,7 @@ func Test_Engine_Create_Node_All(t *testing.T) {
 	{
 		eon = engine.New(engine.Config{
 			Logger: logger.Fake(),
-			Redigo: prgAll(redigo.Default()),
+			Store:  prgAll(defSto()),
 			Timer:  tim,
 			Worker: "eon",
 		})
@@ -677,7 +763,7 @@ func Test_Engine_Create_Node_All(t *testing.T) {
 	{
 		etw = engine.New(engine.Config{
 			Logger: logger.Fake(),
-			Redigo: prgAll(redigo.Default()),
+			Store:  prgAll(defSto()),
 			Timer:  tim,
 			Worker: "etw",
 		})
@@ -687,7 +773,7 @@ func Test_Engine_Create_Node_All(t *testing.T) {
 	{
 		eth = engine.New(engine.Config{
 			Logger: logger.Fake(),
-			Redigo: prgAll(redigo.Default()),
+			Store:  prgAll(defSto()),
 			Timer:  tim,
 			Worker: "eth",
 		})
@@ -894,7 +980,7 @@ func Test_Engine_Create_Root_First(t *testing.T) {
 	{
 		eon = engine.New(engine.Config{
 			Logger: logger.Fake(),
-			Redigo: prgAll(redigo.Default()),
+			Store:  prgAll(defSto()),
 			Timer:  tim,
 		})
 	}
@@ -1121,3 +1207,250 @@ func Test_Engine_Create_Root_First(t *testing.T) {
 		}
 	}
 }
+
+// Test_Engine_CreateMany ensures that batches of tasks are only created if all
+// tasks of the batch are valid, and that invalid tasks are reported using
+// their index within the batch.
+func Test_Engine_CreateMany(t *testing.T) {
+	var err error
+
+	var eon rescue.Interface
+	{
+		eon = engine.New(engine.Config{
+			Logger: logger.Fake(),
+			Store:  prgAll(defSto()),
+		})
+	}
+
+	{
+		tas := []*task.Task{
+			{Meta: &task.Meta{"test.api.io/key": "foo"}},
+			{Root: &task.Root{"test.api.io/key": "foo"}},
+			{Meta: &task.Meta{"test.api.io/key": "bar"}},
+			{Meta: &task.Meta{"test.api.io/key": "baz"}, Cron: &task.Cron{task.Aevery: "foo"}},
+		}
+
+		err = eon.CreateMany(tas)
+		if !engine.IsTaskBatch(err) {
+			t.Fatal("expected", "taskBatchError", "got", err)
+		}
+		if !engine.IsTaskMetaEmpty(err) {
+			t.Fatal("expected", "taskMetaEmptyError", "got", err)
+		}
+		if !engine.IsTaskCron(err) {
+			t.Fatal("expected", "taskCronError", "got", err)
+		}
+		if !strings.Contains(err.Error(), "Task[1]") || !strings.Contains(err.Error(), "Task[3]") {
+			t.Fatal("expected", "Task[1] and Task[3]", "got", err.Error())
+		}
+		if strings.Contains(err.Error(), "Task[0]") || strings.Contains(err.Error(), "Task[2]") {
+			t.Fatal("expected", "Task[1] and Task[3]", "got", err.Error())
+		}
+	}
+
+	{
+		lis, err := eon.Lister(engine.All())
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if len(lis) != 0 {
+			t.Fatal("expected", 0, "got", len(lis))
+		}
+	}
+
+	{
+		var tas []*task.Task
+		for i := 0; i < 100; i++ {
+			tas = append(tas, &task.Task{Meta: &task.Meta{"test.api.io/key": fmt.Sprintf("%03d", i)}})
+		}
+
+		tas = append(tas, &task.Task{
+			Cron: &task.Cron{task.Aevery: "hour"},
+			Meta: &task.Meta{"test.api.io/key": "crn"},
+		})
+
+		err = eon.CreateMany(tas)
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	{
+		lis, err := eon.Lister(engine.All())
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if len(lis) != 101 {
+			t.Fatal("expected", 101, "got", len(lis))
+		}
+	}
+
+	{
+		lis, err := eon.Template(engine.All())
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if len(lis) != 1 {
+			t.Fatal("expected", 1, "got", len(lis))
+		}
+		if lis[0].Cron.Get().TickP1().IsZero() {
+			t.Fatal("expected", "next tick", "got", "zero time")
+		}
+	}
+
+	{
+		exi, err := eon.Exists(&task.Task{Meta: &task.Meta{"test.api.io/key": "042"}})
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if !exi {
+			t.Fatal("expected", true, "got", false)
+		}
+	}
+}
+
+// Test_Engine_CreateMany_Failure ensures that batches failing to be created do
+// neither modify the given tasks, nor leave any index entry behind, and that
+// batches are retried using new object IDs if any object ID is taken already.
+func Test_Engine_CreateMany_Failure(t *testing.T) {
+	var err error
+
+	var sto *collideBatch
+	{
+		sto = &collideBatch{Interface: prgAll(defSto())}
+	}
+
+	var eon rescue.Interface
+	{
+		eon = engine.New(engine.Config{
+			Logger: logger.Fake(),
+			Store:  sto,
+		})
+	}
+
+	var idx string
+	{
+		idx = fmt.Sprintf("%s:index:%q=%q", eon.Keyfmt(), "test.api.io/key", "foo")
+	}
+
+	var lis []*task.Task
+	{
+		lis = []*task.Task{
+			{Meta: &task.Meta{"test.api.io/key": "foo"}},
+			{Meta: &task.Meta{"test.api.io/key": "foo"}},
+		}
+	}
+
+	// Every attempt to create the batch collides with an existing object ID.
+	{
+		sto.cou = engine.Retry
+	}
+
+	{
+		err = eon.CreateMany(lis)
+		if err == nil {
+			t.Fatal("expected", "error", "got", nil)
+		}
+	}
+
+	for _, x := range lis {
+		if x.Core != nil || x.Node != nil {
+			t.Fatal("expected", "unmodified task", "got", x)
+		}
+	}
+
+	{
+		mem, err := sto.Member(idx)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if len(mem) != 0 {
+			t.Fatal("expected", 0, "got", len(mem))
+		}
+	}
+
+	// The first attempt to create the batch collides with an existing object ID.
+	{
+		sto.cou = 1
+	}
+
+	{
+		err = eon.CreateMany(lis)
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	{
+		mem, err := sto.Member(idx)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		exp := []string{
+			lis[0].Core.Map().Object(),
+			lis[1].Core.Map().Object(),
+		}
+
+		if !cmp.Equal(mem, exp) && !cmp.Equal(mem, []string{exp[1], exp[0]}) {
+			t.Fatal("expected", exp, "got", mem)
+		}
+	}
+}
+
+// collideBatch lets the next cou batches collide with an existing object ID.
+type collideBatch struct {
+	store.Interface
+	cou int
+}
+
+func (c *collideBatch) Batch(ele []store.Element) error {
+	if c.cou == 0 || len(ele) == 0 {
+		return c.Interface.Batch(ele)
+	}
+
+	{
+		c.cou--
+	}
+
+	err := c.Interface.Create(ele[0].Key, ele[0].Oid, ele[0].Val)
+	if err != nil {
+		return err
+	}
+
+	defer c.Interface.Delete(ele[0].Key, ele[0].Oid)
+
+	return c.Interface.Batch(ele)
+}
+
+// collideCreate lets the next cou tasks created under key collide with an
+// existing object ID.
+type collideCreate struct {
+	store.Interface
+	cou int
+	key string
+}
+
+func (c *collideCreate) Create(key string, oid objectid.ID, val string) error {
+	if c.cou == 0 || key != c.key {
+		return c.Interface.Create(key, oid, val)
+	}
+
+	{
+		c.cou--
+	}
+
+	err := c.Interface.Create(key, oid, val)
+	if err != nil {
+		return err
+	}
+
+	defer c.Interface.Delete(key, oid)
+
+	return c.Interface.Create(key, oid, val)
+}
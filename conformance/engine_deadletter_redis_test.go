@@ -0,0 +1,370 @@
+package conformance
+
+import (
+	"testing"
+	"time"
+
+	"github.com/xh3b4sd/logger"
+	"github.com/xh3b4sd/rescue"
+	"github.com/xh3b4sd/rescue/engine"
+	"github.com/xh3b4sd/rescue/store"
+	"github.com/xh3b4sd/rescue/task"
+	"github.com/xh3b4sd/rescue/timer"
+)
+
+// Test_Engine_Deadletter ensures that tasks halted by their circuit breaker get
+// moved into the dead-letter queue, and that dead-lettered tasks can be
+// redriven and purged.
+func Test_Engine_Deadletter(t *testing.T) {
+	var err error
+
+	var sto store.Interface
+	{
+		sto = prgAll(defSto())
+	}
+
+	var eon rescue.Interface
+	{
+		eon = engine.New(engine.Config{
+			Deadletter: true,
+			Logger:     logger.Fake(),
+			Store:      sto,
+			Worker:     "eon",
+		})
+	}
+
+	for _, x := range []string{"foo", "bar"} {
+		tas := &task.Task{
+			Core: &task.Core{
+				task.Cancel: "1",
+			},
+			Meta: &task.Meta{
+				"test.api.io/key": x,
+			},
+		}
+
+		err = eon.Create(tas)
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	// Trip the circuit breaker of both tasks.
+	for i := 0; i < 2; i++ {
+		tas, err := eon.Search()
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		err = eon.Release(tas, engine.ReleaseOptions{Reason: "test error"})
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	{
+		err = eon.Expire()
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	{
+		lis, err := eon.Lister(engine.All())
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if len(lis) != 0 {
+			t.Fatal("expected", 0, "got", len(lis))
+		}
+	}
+
+	var lis []*task.Task
+	{
+		lis, err = eon.Deadletter(engine.All())
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if len(lis) != 2 {
+			t.Fatal("expected", 2, "got", len(lis))
+		}
+		if lis[0].Core.Get().Reason() != "test error" {
+			t.Fatal("expected", "test error", "got", lis[0].Core.Get().Reason())
+		}
+	}
+
+	{
+		lis, err := eon.Deadletter(&task.Task{Meta: &task.Meta{"test.api.io/key": "foo"}})
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if len(lis) != 1 {
+			t.Fatal("expected", 1, "got", len(lis))
+		}
+	}
+
+	// Redriven tasks can be processed again.
+	{
+		err = eon.Redrive(lis[0])
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	{
+		err = eon.Redrive(lis[0])
+		if !engine.IsTaskNotFound(err) {
+			t.Fatal("expected", "taskNotFoundError", "got", err)
+		}
+	}
+
+	{
+		tas, err := eon.Search()
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if tas.Core.Get().Cycles() != 0 {
+			t.Fatal("expected", 0, "got", tas.Core.Get().Cycles())
+		}
+
+		err = eon.Delete(tas)
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	// Purged tasks are removed for good.
+	{
+		err = eon.PurgeDeadletter(lis[1])
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	{
+		lis, err := eon.Deadletter(engine.All())
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if len(lis) != 0 {
+			t.Fatal("expected", 0, "got", len(lis))
+		}
+	}
+
+	{
+		exi, err := eon.Exists(&task.Task{Meta: &task.Meta{"test.api.io/key": "*"}})
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if exi {
+			t.Fatal("expected", false, "got", true)
+		}
+	}
+}
+
+// Test_Engine_Deadletter_Deadline ensures that redriven tasks do not get
+// dead-lettered again right away, because of the deadline that they missed.
+func Test_Engine_Deadletter_Deadline(t *testing.T) {
+	var err error
+
+	var tim *timer.Timer
+	{
+		tim = timer.New()
+	}
+
+	{
+		tim.Setter(func() time.Time {
+			return musTim("2023-10-20T00:00:00Z")
+		})
+	}
+
+	var eon rescue.Interface
+	{
+		eon = engine.New(engine.Config{
+			Deadletter: true,
+			Logger:     logger.Fake(),
+			Store:      prgAll(defSto()),
+			Timer:      tim,
+			Worker:     "eon",
+		})
+	}
+
+	{
+		tas := &task.Task{
+			Core: &task.Core{
+				task.Deadline: "2023-10-20T00:05:00Z",
+			},
+			Meta: &task.Meta{
+				"test.api.io/key": "foo",
+			},
+		}
+
+		err = eon.Create(tas)
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	{
+		tim.Setter(func() time.Time {
+			return musTim("2023-10-20T00:05:00Z")
+		})
+	}
+
+	{
+		err = eon.Expire()
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	var lis []*task.Task
+	{
+		lis, err = eon.Deadletter(engine.All())
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if len(lis) != 1 {
+			t.Fatal("expected", 1, "got", len(lis))
+		}
+	}
+
+	{
+		err = eon.Redrive(lis[0])
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	{
+		err = eon.Expire()
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	{
+		tas, err := eon.Search()
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if tas.Core.Exi().Deadline() {
+			t.Fatal("expected", false, "got", true)
+		}
+	}
+}
+
+// Test_Engine_Deadletter_Move ensures that redriving tasks completes a previous
+// move that got interrupted, so that the copy within the task queue gets
+// replaced by the redriven task.
+func Test_Engine_Deadletter_Move(t *testing.T) {
+	var err error
+
+	var sto store.Interface
+	{
+		sto = prgAll(defSto())
+	}
+
+	var eon *engine.Engine
+	{
+		eon = engine.New(engine.Config{
+			Deadletter: true,
+			Logger:     logger.Fake(),
+			Store:      sto,
+			Worker:     "eon",
+		})
+	}
+
+	{
+		tas := &task.Task{
+			Core: &task.Core{
+				task.Cancel: "1",
+			},
+			Meta: &task.Meta{
+				"test.api.io/key": "foo",
+			},
+		}
+
+		err = eon.Create(tas)
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	{
+		tas, err := eon.Search()
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		err = eon.Release(tas, engine.ReleaseOptions{})
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	{
+		err = eon.Expire()
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	var lis []*task.Task
+	{
+		lis, err = eon.Deadletter(engine.All())
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if len(lis) != 1 {
+			t.Fatal("expected", 1, "got", len(lis))
+		}
+	}
+
+	// Simulate a previous move that created the copy within the task queue, but
+	// did not remove the original from the dead-letter queue.
+	{
+		err = sto.Create(eon.Keyfmt(), lis[0].Core.Get().Object(), task.ToString(lis[0]))
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	{
+		err = eon.Redrive(lis[0])
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	{
+		lis, err := eon.Deadletter(engine.All())
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if len(lis) != 0 {
+			t.Fatal("expected", 0, "got", len(lis))
+		}
+	}
+
+	{
+		tas, err := eon.Search()
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if tas.Core.Get().Cycles() != 0 {
+			t.Fatal("expected", 0, "got", tas.Core.Get().Cycles())
+		}
+	}
+}
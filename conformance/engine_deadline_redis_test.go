@@ -0,0 +1,133 @@
+package conformance
+
+import (
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/xh3b4sd/logger"
+	"github.com/xh3b4sd/rescue"
+	"github.com/xh3b4sd/rescue/engine"
+	"github.com/xh3b4sd/rescue/task"
+	"github.com/xh3b4sd/rescue/timer"
+)
+
+// Test_Engine_Deadline ensures that tasks missing their deadline are neither
+// claimed nor kept within the task queue. Overdue tasks are deleted, or moved
+// into the dead-letter queue, if enabled.
+func Test_Engine_Deadline(t *testing.T) {
+	testCases := []struct {
+		dlq bool
+	}{
+		// Case 000
+		{
+			dlq: false,
+		},
+		// Case 001
+		{
+			dlq: true,
+		},
+	}
+
+	for i, tc := range testCases {
+		t.Run(fmt.Sprintf("%03d", i), func(t *testing.T) {
+			var err error
+
+			var tim *timer.Timer
+			{
+				tim = timer.New()
+			}
+
+			{
+				tim.Setter(func() time.Time {
+					return musTim("2023-10-20T00:00:00Z")
+				})
+			}
+
+			var eon rescue.Interface
+			{
+				eon = engine.New(engine.Config{
+					Deadletter: tc.dlq,
+					Logger:     logger.Fake(),
+					Store:      prgAll(defSto()),
+					Timer:      tim,
+					Worker:     "eon",
+				})
+			}
+
+			for _, x := range []string{"foo", "bar"} {
+				tas := &task.Task{
+					Core: &task.Core{
+						task.Deadline: "2023-10-20T00:05:00Z",
+					},
+					Meta: &task.Meta{
+						"test.api.io/key": x,
+					},
+				}
+
+				err = eon.Create(tas)
+				if err != nil {
+					t.Fatal(err)
+				}
+			}
+
+			var tas *task.Task
+			{
+				tas, err = eon.Search()
+				if err != nil {
+					t.Fatal(err)
+				}
+			}
+
+			{
+				tim.Setter(func() time.Time {
+					return musTim("2023-10-20T00:05:00Z")
+				})
+			}
+
+			// Overdue tasks must not be claimed anymore.
+			{
+				_, err = eon.Search()
+				if !engine.IsTaskNotFound(err) {
+					t.Fatal("expected", "taskNotFoundError", "got", err)
+				}
+			}
+
+			// Overdue tasks that are still being processed are not removed.
+			{
+				err = eon.Expire()
+				if err != nil {
+					t.Fatal(err)
+				}
+			}
+
+			{
+				lis, err := eon.Lister(engine.All())
+				if err != nil {
+					t.Fatal(err)
+				}
+
+				if len(lis) != 1 {
+					t.Fatal("expected", 1, "got", len(lis))
+				}
+				if lis[0].Core.Get().Object() != tas.Core.Get().Object() {
+					t.Fatal("expected", tas.Core.Get().Object(), "got", lis[0].Core.Get().Object())
+				}
+			}
+
+			{
+				lis, err := eon.Deadletter(engine.All())
+				if err != nil {
+					t.Fatal(err)
+				}
+
+				if tc.dlq && len(lis) != 1 {
+					t.Fatal("expected", 1, "got", len(lis))
+				}
+				if !tc.dlq && len(lis) != 0 {
+					t.Fatal("expected", 0, "got", len(lis))
+				}
+			}
+		})
+	}
+}
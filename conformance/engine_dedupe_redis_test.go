@@ -0,0 +1,281 @@
+package conformance
+
+import (
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/xh3b4sd/logger"
+	"github.com/xh3b4sd/objectid"
+	"github.com/xh3b4sd/rescue"
+	"github.com/xh3b4sd/rescue/engine"
+	"github.com/xh3b4sd/rescue/store"
+	"github.com/xh3b4sd/rescue/task"
+	"github.com/xh3b4sd/rescue/timer"
+)
+
+// Test_Engine_Dedupe ensures that tasks defining the same dedupe key are only
+// created once, as long as the original task exists, or completed within the
+// configured dedupe window.
+func Test_Engine_Dedupe(t *testing.T) {
+	testCases := []struct {
+		ded time.Duration
+	}{
+		// Case 000
+		{
+			ded: 0,
+		},
+		// Case 001
+		{
+			ded: time.Minute,
+		},
+	}
+
+	for i, tc := range testCases {
+		t.Run(fmt.Sprintf("%03d", i), func(t *testing.T) {
+			var err error
+
+			var now time.Time
+			{
+				now = musTim("2023-10-20T00:00:00Z")
+			}
+
+			var tim *timer.Timer
+			{
+				tim = timer.New()
+			}
+
+			{
+				tim.Setter(func() time.Time {
+					return now
+				})
+			}
+
+			var eon rescue.Interface
+			{
+				eon = engine.New(engine.Config{
+					Dedupe: tc.ded,
+					Logger: logger.Fake(),
+					Store:  prgAll(defSto()),
+					Timer:  tim,
+					Worker: "eon",
+				})
+			}
+
+			cre := func(key string) objectid.ID {
+				tas := &task.Task{
+					Core: &task.Core{
+						task.Dedupe: key,
+					},
+					Meta: &task.Meta{
+						"test.api.io/key": key,
+					},
+				}
+
+				err := eon.Create(tas)
+				if err != nil {
+					t.Fatal(err)
+				}
+
+				return tas.Core.Get().Object()
+			}
+
+			cnt := func(exp int) {
+				lis, err := eon.Lister(engine.All())
+				if err != nil {
+					t.Fatal(err)
+				}
+
+				if len(lis) != exp {
+					t.Fatal("expected", exp, "got", len(lis))
+				}
+			}
+
+			var foo objectid.ID
+			{
+				foo = cre("foo")
+			}
+
+			{
+				now = now.Add(time.Second)
+			}
+
+			// Creating a duplicate while the original task exists must return the
+			// original object ID without creating another task.
+			{
+				oid := cre("foo")
+				if oid != foo {
+					t.Fatal("expected", foo, "got", oid)
+				}
+			}
+
+			{
+				oid := cre("bar")
+				if oid == foo {
+					t.Fatal("expected", "new object ID", "got", oid)
+				}
+			}
+
+			{
+				cnt(2)
+			}
+
+			{
+				tas, err := eon.Search()
+				if err != nil {
+					t.Fatal(err)
+				}
+
+				if tas.Core.Get().Object() != foo {
+					t.Fatal("expected", foo, "got", tas.Core.Get().Object())
+				}
+
+				err = eon.Delete(tas)
+				if err != nil {
+					t.Fatal(err)
+				}
+			}
+
+			{
+				now = now.Add(10 * time.Second)
+			}
+
+			// Creating a duplicate after the original task completed is only
+			// prevented within the configured dedupe window.
+			{
+				oid := cre("foo")
+				if tc.ded == 0 && oid == foo {
+					t.Fatal("expected", "new object ID", "got", oid)
+				}
+				if tc.ded != 0 && oid != foo {
+					t.Fatal("expected", foo, "got", oid)
+				}
+			}
+
+			if tc.ded == 0 {
+				cnt(2)
+			} else {
+				cnt(1)
+			}
+
+			{
+				now = now.Add(2 * time.Minute)
+			}
+
+			{
+				err = eon.Expire()
+				if err != nil {
+					t.Fatal(err)
+				}
+			}
+
+			if tc.ded != 0 {
+				oid := cre("foo")
+				if oid == foo {
+					t.Fatal("expected", "new object ID", "got", oid)
+				}
+
+				cnt(2)
+			}
+
+			{
+				err = eon.CreateMany([]*task.Task{{Core: &task.Core{task.Dedupe: "baz"}, Meta: &task.Meta{"test.api.io/key": "baz"}}})
+				if !engine.IsTaskCore(err) {
+					t.Fatal("expected", "taskCoreError", "got", err)
+				}
+			}
+		})
+	}
+}
+
+// Test_Engine_Dedupe_Index ensures that dedupe records are indexed by their
+// dedupe key, and that their index entries are removed together with them.
+func Test_Engine_Dedupe_Index(t *testing.T) {
+	var err error
+
+	var sto store.Interface
+	{
+		sto = prgAll(defSto())
+	}
+
+	var eon *engine.Engine
+	{
+		eon = engine.New(engine.Config{
+			Logger: logger.Fake(),
+			Store:  sto,
+			Worker: "eon",
+		})
+	}
+
+	var tas []*task.Task
+	for _, x := range []string{"foo", "bar"} {
+		y := &task.Task{
+			Core: &task.Core{
+				task.Dedupe: x,
+			},
+			Meta: &task.Meta{
+				"test.api.io/key": x,
+			},
+		}
+
+		err = eon.Create(y)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		tas = append(tas, y)
+	}
+
+	{
+		mem, err := sto.Member(fmt.Sprintf("%s:dedupe:%q", eon.Keyfmt(), "foo"))
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if dif := cmp.Diff([]string{tas[0].Core.Map().Object()}, mem); dif != "" {
+			t.Fatalf("-expected +actual:\n%s", dif)
+		}
+	}
+
+	// Deleting the task without dedupe window lets Expire remove its record
+	// together with its index entry.
+	{
+		cop := task.FromString(task.ToString(tas[0]))
+		cop.Core.Set().Bypass(true)
+
+		err = eon.Delete(cop)
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	{
+		err = eon.Expire()
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	{
+		mem, err := sto.Member(fmt.Sprintf("%s:dedupe:%q", eon.Keyfmt(), "foo"))
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if len(mem) != 0 {
+			t.Fatal("expected", 0, "got", len(mem))
+		}
+	}
+
+	{
+		mem, err := sto.Member(fmt.Sprintf("%s:dedupe:%q", eon.Keyfmt(), "bar"))
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if dif := cmp.Diff([]string{tas[1].Core.Map().Object()}, mem); dif != "" {
+			t.Fatalf("-expected +actual:\n%s", dif)
+		}
+	}
+}
@@ -1,5 +1,3 @@
-//go:build redis
-
 package conformance
 
 import (
@@ -8,7 +6,6 @@ import (
 	"time"
 
 	"github.com/xh3b4sd/logger"
-	"github.com/xh3b4sd/redigo"
 	"github.com/xh3b4sd/rescue"
 	"github.com/xh3b4sd/rescue/engine"
 	"github.com/xh3b4sd/rescue/task"
@@ -34,7 +31,7 @@ func Test_Engine_Exists(t *testing.T) {
 		eon = engine.New(engine.Config{
 			Expiry: 500 * time.Millisecond,
 			Logger: logger.Fake(),
-			Redigo: prgAll(redigo.Default()),
+			Store:  prgAll(defSto()),
 			Timer:  tim,
 			Worker: "eon",
 		})
@@ -378,7 +375,7 @@ func Test_Engine_Exists_Gate(t *testing.T) {
 	{
 		eon = engine.New(engine.Config{
 			Logger: logger.Fake(),
-			Redigo: prgAll(redigo.Default()),
+			Store:  prgAll(defSto()),
 			Timer:  tim,
 		})
 	}
@@ -1,5 +1,3 @@
-//go:build redis
-
 package conformance
 
 import (
@@ -7,7 +5,6 @@ import (
 	"time"
 
 	"github.com/xh3b4sd/logger"
-	"github.com/xh3b4sd/redigo"
 	"github.com/xh3b4sd/rescue"
 	"github.com/xh3b4sd/rescue/engine"
 	"github.com/xh3b4sd/rescue/task"
@@ -21,7 +18,7 @@ func Test_Engine_Extend(t *testing.T) {
 		eon = engine.New(engine.Config{
 			Expiry: time.Second,
 			Logger: logger.Fake(),
-			Redigo: prgAll(redigo.Default()),
+			Store:  prgAll(defSto()),
 			Worker: "eon",
 		})
 	}
@@ -31,7 +28,7 @@ func Test_Engine_Extend(t *testing.T) {
 		etw = engine.New(engine.Config{
 			Expiry: time.Second,
 			Logger: logger.Fake(),
-			Redigo: prgAll(redigo.Default()),
+			Store:  prgAll(defSto()),
 			Worker: "etw",
 		})
 	}
@@ -0,0 +1,164 @@
+package conformance
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/xh3b4sd/logger"
+	"github.com/xh3b4sd/rescue"
+	"github.com/xh3b4sd/rescue/engine"
+	"github.com/xh3b4sd/rescue/task"
+)
+
+// Test_Engine_Lister_Index ensures that Engine.Exists and Engine.Lister return
+// the same results, regardless whether tasks are resolved by scanning the whole
+// queue, or by using the label indexes that Engine.Expire completes.
+func Test_Engine_Lister_Index(t *testing.T) {
+	testCases := []struct {
+		met *task.Meta
+		cou int
+	}{
+		// Case 000
+		{
+			met: &task.Meta{
+				"test.api.io/key": "foo",
+			},
+			cou: 4,
+		},
+		// Case 001
+		{
+			met: &task.Meta{
+				"test.api.io/key": "foo",
+				"test.api.io/zer": "tru",
+			},
+			cou: 2,
+		},
+		// Case 002
+		{
+			met: &task.Meta{
+				"test.api.io/key": "bar",
+			},
+			cou: 1,
+		},
+		// Case 003
+		{
+			met: &task.Meta{
+				"test.api.io/key": "baz",
+			},
+			cou: 0,
+		},
+		// Case 004
+		{
+			met: &task.Meta{
+				"*api.io/ke*": "foo",
+			},
+			cou: 4,
+		},
+		// Case 005
+		{
+			met: &task.Meta{
+				"test.api.io/key": "*",
+				"test.api.io/zer": "tru",
+			},
+			cou: 2,
+		},
+		// Case 006
+		{
+			met: &task.Meta{
+				"*": "*",
+			},
+			cou: 6,
+		},
+	}
+
+	var err error
+
+	var eon rescue.Interface
+	{
+		eon = engine.New(engine.Config{
+			Logger: logger.Fake(),
+			Store:  prgAll(defSto()),
+			Worker: "eon",
+		})
+	}
+
+	var met []*task.Meta
+	{
+		met = []*task.Meta{
+			{"test.api.io/key": "foo", "test.api.io/zer": "tru"},
+			{"test.api.io/key": "foo", "test.api.io/zer": "tru", "test.api.io/sin": "baz"},
+			{"test.api.io/key": "foo"},
+			{"test.api.io/key": "bar"},
+			{"test.api.io/keys": "foo"},
+			{"test.api.io/obj": "foo"},
+		}
+	}
+
+	// Create the first half of tasks before the label indexes are complete, and
+	// the second half afterwards.
+	for i, x := range met {
+		if i == len(met)/2 {
+			err = eon.Expire()
+			if err != nil {
+				t.Fatal(err)
+			}
+		}
+
+		err = eon.Create(&task.Task{Meta: x})
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	for i, tc := range testCases {
+		t.Run(fmt.Sprintf("%03d", i), func(t *testing.T) {
+			lis, err := eon.Lister(&task.Task{Meta: tc.met})
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if len(lis) != tc.cou {
+				t.Fatal("expected", tc.cou, "got", len(lis))
+			}
+
+			for j := 1; j < len(lis); j++ {
+				if lis[j-1].Core.Get().Object().Float() >= lis[j].Core.Get().Object().Float() {
+					t.Fatal("expected tasks to be ordered by object ID")
+				}
+			}
+
+			exi, err := eon.Exists(&task.Task{Meta: tc.met})
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if exi != (tc.cou != 0) {
+				t.Fatal("expected", tc.cou != 0, "got", exi)
+			}
+		})
+	}
+
+	// Deleted tasks must not be resolved anymore using the label indexes.
+	{
+		lis, err := eon.Lister(&task.Task{Meta: &task.Meta{"test.api.io/key": "bar"}})
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		lis[0].Core.Set().Bypass(true)
+
+		err = eon.Delete(lis[0])
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		exi, err := eon.Exists(&task.Task{Meta: &task.Meta{"test.api.io/key": "bar"}})
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if exi {
+			t.Fatal("expected", false, "got", true)
+		}
+	}
+}
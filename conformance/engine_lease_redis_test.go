@@ -0,0 +1,298 @@
+package conformance
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/xh3b4sd/logger"
+	"github.com/xh3b4sd/objectid"
+	"github.com/xh3b4sd/rescue"
+	"github.com/xh3b4sd/rescue/engine"
+	"github.com/xh3b4sd/rescue/store"
+	"github.com/xh3b4sd/rescue/task"
+	"github.com/xh3b4sd/rescue/timer"
+)
+
+// Test_Engine_Lease ensures that leased tasks do not expire while being
+// processed, and that the lease context gets cancelled once ownership of the
+// task got lost.
+func Test_Engine_Lease(t *testing.T) {
+	var err error
+
+	var sto store.Interface
+	{
+		sto = prgAll(defSto())
+	}
+
+	var eon rescue.Interface
+	{
+		eon = engine.New(engine.Config{
+			Expiry: 300 * time.Millisecond,
+			Logger: logger.Fake(),
+			Store:  sto,
+			Worker: "eon",
+		})
+	}
+
+	var etw rescue.Interface
+	{
+		etw = engine.New(engine.Config{
+			Expiry: 300 * time.Millisecond,
+			Logger: logger.Fake(),
+			Store:  sto,
+			Worker: "etw",
+		})
+	}
+
+	{
+		err = eon.Create(&task.Task{Meta: &task.Meta{"test.api.io/key": "foo"}})
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	var tas *task.Task
+	{
+		tas, err = eon.Search()
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	var ctx context.Context
+	var can context.CancelFunc
+	{
+		ctx, can = eon.Lease(context.Background(), tas)
+		defer can()
+	}
+
+	// The task must not expire, even though its initial expiry passed multiple
+	// times.
+	{
+		time.Sleep(time.Second)
+	}
+
+	{
+		err = etw.Expire()
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	{
+		if ctx.Err() != nil {
+			t.Fatal("expected", nil, "got", context.Cause(ctx))
+		}
+	}
+
+	{
+		lis, err := etw.Lister(engine.All())
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if lis[0].Core.Get().Worker() != "eon" {
+			t.Fatal("expected", "eon", "got", lis[0].Core.Get().Worker())
+		}
+	}
+
+	// Deleting the task from the outside causes the lease to be lost. The lease
+	// may renew the task concurrently, in which case deleting it is attempted
+	// again.
+	{
+		cop := task.FromString(task.ToString(tas))
+		cop.Core.Set().Bypass(true)
+
+		for i := 0; i < 5; i++ {
+			err = etw.Delete(cop)
+			if !engine.IsTaskOutdated(err) {
+				break
+			}
+		}
+
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	select {
+	case <-ctx.Done():
+	case <-time.After(time.Second):
+		t.Fatal("expected", "cancelled lease", "got", "active lease")
+	}
+
+	{
+		if !engine.IsTaskNotFound(context.Cause(ctx)) {
+			t.Fatal("expected", "taskNotFoundError", "got", context.Cause(ctx))
+		}
+	}
+}
+
+// Test_Engine_Lease_Duration ensures that tasks defining their own lease
+// duration expire according to that lease, instead of the default expiry.
+func Test_Engine_Lease_Duration(t *testing.T) {
+	var err error
+
+	var tim *timer.Timer
+	{
+		tim = timer.New()
+	}
+
+	{
+		tim.Setter(func() time.Time {
+			return musTim("2023-10-20T00:00:00Z")
+		})
+	}
+
+	var eon rescue.Interface
+	{
+		eon = engine.New(engine.Config{
+			Logger: logger.Fake(),
+			Store:  prgAll(defSto()),
+			Timer:  tim,
+			Worker: "eon",
+		})
+	}
+
+	{
+		tas := &task.Task{
+			Core: &task.Core{
+				task.Lease: "5 minutes",
+			},
+			Meta: &task.Meta{
+				"test.api.io/key": "foo",
+			},
+		}
+
+		err = eon.Create(tas)
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	var tas *task.Task
+	{
+		tas, err = eon.Search()
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if !tas.Core.Get().Expiry().Equal(musTim("2023-10-20T00:05:00Z")) {
+			t.Fatal("expected", musTim("2023-10-20T00:05:00Z"), "got", tas.Core.Get().Expiry())
+		}
+	}
+
+	// The task must not expire after the default expiry passed.
+	{
+		tim.Setter(func() time.Time {
+			return musTim("2023-10-20T00:04:00Z")
+		})
+	}
+
+	{
+		err = eon.Expire()
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	{
+		err = eon.Extend(tas)
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	{
+		lis, err := eon.Lister(engine.All())
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if !lis[0].Core.Get().Expiry().Equal(musTim("2023-10-20T00:09:00Z")) {
+			t.Fatal("expected", musTim("2023-10-20T00:09:00Z"), "got", lis[0].Core.Get().Expiry())
+		}
+	}
+
+	{
+		tim.Setter(func() time.Time {
+			return musTim("2023-10-20T00:09:00Z")
+		})
+	}
+
+	{
+		err = eon.Expire()
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	{
+		lis, err := eon.Lister(engine.All())
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if lis[0].Core.Exi().Worker() {
+			t.Fatal("expected", "expired task", "got", lis[0].Core.Get().Worker())
+		}
+	}
+}
+
+// Test_Engine_Lease_Stop ensures that tasks can be deleted right after their
+// lease got stopped, even if the lease was renewing the task at that time.
+func Test_Engine_Lease_Stop(t *testing.T) {
+	var err error
+
+	var eon rescue.Interface
+	{
+		eon = engine.New(engine.Config{
+			Expiry: 30 * time.Millisecond,
+			Logger: logger.Fake(),
+			Store:  &slowUpdate{Interface: prgAll(defSto())},
+			Worker: "eon",
+		})
+	}
+
+	for i := 0; i < 10; i++ {
+		{
+			err = eon.Create(&task.Task{Meta: &task.Meta{"test.api.io/key": "foo"}})
+			if err != nil {
+				t.Fatal(err)
+			}
+		}
+
+		var tas *task.Task
+		{
+			tas, err = eon.Search()
+			if err != nil {
+				t.Fatal(err)
+			}
+		}
+
+		{
+			_, can := eon.Lease(context.Background(), tas)
+			time.Sleep(time.Duration(10+i*2) * time.Millisecond)
+			can()
+		}
+
+		{
+			err = eon.Delete(tas)
+			if err != nil {
+				t.Fatal(err)
+			}
+		}
+	}
+}
+
+// slowUpdate delays every update, so that renewals of leased tasks are still
+// in flight when their lease gets stopped.
+type slowUpdate struct {
+	store.Interface
+}
+
+func (s *slowUpdate) Update(key string, oid objectid.ID, cur string, val string) (bool, error) {
+	time.Sleep(20 * time.Millisecond)
+	return s.Interface.Update(key, oid, cur, val)
+}
@@ -1,5 +1,3 @@
-//go:build redis
-
 package conformance
 
 import (
@@ -11,7 +9,6 @@ import (
 
 	"github.com/google/go-cmp/cmp"
 	"github.com/xh3b4sd/logger"
-	"github.com/xh3b4sd/redigo"
 	"github.com/xh3b4sd/rescue"
 	"github.com/xh3b4sd/rescue/engine"
 	"github.com/xh3b4sd/rescue/task"
@@ -39,7 +36,7 @@ func Test_Engine_Lifecycle_Cron_3Days(t *testing.T) {
 	{
 		eon = engine.New(engine.Config{
 			Logger: logger.Fake(),
-			Redigo: prgAll(redigo.Default()),
+			Store:  prgAll(defSto()),
 			Timer:  tim,
 			Worker: "eon",
 		})
@@ -327,7 +324,7 @@ func Test_Engine_Lifecycle_Cron_Failure(t *testing.T) {
 	{
 		eon = engine.New(engine.Config{
 			Logger: logger.Fake(),
-			Redigo: prgAll(redigo.Default()),
+			Store:  prgAll(defSto()),
 			Timer:  tim,
 			Worker: "eon",
 		})
@@ -337,7 +334,7 @@ func Test_Engine_Lifecycle_Cron_Failure(t *testing.T) {
 	{
 		etw = engine.New(engine.Config{
 			Logger: logger.Fake(),
-			Redigo: prgAll(redigo.Default()),
+			Store:  prgAll(defSto()),
 			Timer:  tim,
 			Worker: "etw",
 		})
@@ -836,7 +833,7 @@ func Test_Engine_Lifecycle_Cron_Resolve(t *testing.T) {
 	{
 		eon = engine.New(engine.Config{
 			Logger: logger.Fake(),
-			Redigo: prgAll(redigo.Default()),
+			Store:  prgAll(defSto()),
 			Timer:  tim,
 			Worker: "eon",
 		})
@@ -846,7 +843,7 @@ func Test_Engine_Lifecycle_Cron_Resolve(t *testing.T) {
 	{
 		etw = engine.New(engine.Config{
 			Logger: logger.Fake(),
-			Redigo: prgAll(redigo.Default()),
+			Store:  prgAll(defSto()),
 			Timer:  tim,
 			Worker: "etw",
 		})
@@ -1301,7 +1298,7 @@ func Test_Engine_Lifecycle_Node_All_Failure(t *testing.T) {
 	{
 		eon = engine.New(engine.Config{
 			Logger: logger.Fake(),
-			Redigo: prgAll(redigo.Default()),
+			Store:  prgAll(defSto()),
 			Timer:  tim,
 		})
 	}
@@ -1434,7 +1431,7 @@ func Test_Engine_Lifecycle_Node_All_Failure(t *testing.T) {
 	{
 		etw = engine.New(engine.Config{
 			Logger: logger.Fake(),
-			Redigo: redigo.Default(),
+			Store:  defSto(),
 			Timer:  tim,
 		})
 	}
@@ -1591,7 +1588,7 @@ func Test_Engine_Lifecycle_Race(t *testing.T) {
 	{
 		eon = engine.New(engine.Config{
 			Logger: logger.Fake(),
-			Redigo: prgAll(redigo.Default()),
+			Store:  prgAll(defSto()),
 			Worker: "eon",
 		})
 	}
@@ -1600,7 +1597,7 @@ func Test_Engine_Lifecycle_Race(t *testing.T) {
 	{
 		etw = engine.New(engine.Config{
 			Logger: logger.Fake(),
-			Redigo: prgAll(redigo.Default()),
+			Store:  prgAll(defSto()),
 			Worker: "etw",
 		})
 	}
@@ -1805,7 +1802,7 @@ func Test_Engine_Lifecycle_Sync(t *testing.T) {
 			Expiry: 500 * time.Millisecond,
 			Logger: logger.Fake(),
 			Queue:  "one", // engines use different queues
-			Redigo: prgAll(redigo.Default()),
+			Store:  prgAll(defSto()),
 			Timer:  tim,
 		})
 	}
@@ -1816,7 +1813,7 @@ func Test_Engine_Lifecycle_Sync(t *testing.T) {
 			Expiry: 500 * time.Millisecond,
 			Logger: logger.Fake(),
 			Queue:  "two", // engines use different queues
-			Redigo: prgAll(redigo.Default()),
+			Store:  prgAll(defSto()),
 			Timer:  tim,
 		})
 	}
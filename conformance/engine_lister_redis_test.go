@@ -1,5 +1,3 @@
-//go:build redis
-
 package conformance
 
 import (
@@ -10,7 +8,6 @@ import (
 
 	"github.com/google/go-cmp/cmp"
 	"github.com/xh3b4sd/logger"
-	"github.com/xh3b4sd/redigo"
 	"github.com/xh3b4sd/rescue"
 	"github.com/xh3b4sd/rescue/engine"
 	"github.com/xh3b4sd/rescue/task"
@@ -36,7 +33,7 @@ func Test_Engine_Lister(t *testing.T) {
 		eon = engine.New(engine.Config{
 			Expiry: 500 * time.Millisecond,
 			Logger: logger.Fake(),
-			Redigo: prgAll(redigo.Default()),
+			Store:  prgAll(defSto()),
 			Timer:  tim,
 			Worker: "eon",
 		})
@@ -47,7 +44,7 @@ func Test_Engine_Lister(t *testing.T) {
 		etw = engine.New(engine.Config{
 			Expiry: 500 * time.Millisecond,
 			Logger: logger.Fake(),
-			Redigo: prgAll(redigo.Default()),
+			Store:  prgAll(defSto()),
 			Timer:  tim,
 			Worker: "etw",
 		})
@@ -354,7 +351,7 @@ func Test_Engine_Lister_Cancel(t *testing.T) {
 		eon = engine.New(engine.Config{
 			Expiry: 500 * time.Millisecond,
 			Logger: logger.Fake(),
-			Redigo: prgAll(redigo.Default()),
+			Store:  prgAll(defSto()),
 			Worker: "eon",
 		})
 	}
@@ -364,7 +361,7 @@ func Test_Engine_Lister_Cancel(t *testing.T) {
 		etw = engine.New(engine.Config{
 			Expiry: 500 * time.Millisecond,
 			Logger: logger.Fake(),
-			Redigo: prgAll(redigo.Default()),
+			Store:  prgAll(defSto()),
 			Worker: "etw",
 		})
 	}
@@ -603,7 +600,7 @@ func Test_Engine_Lister_Gate(t *testing.T) {
 	{
 		eon = engine.New(engine.Config{
 			Logger: logger.Fake(),
-			Redigo: prgAll(redigo.Default()),
+			Store:  prgAll(defSto()),
 			Timer:  tim,
 		})
 	}
@@ -1095,7 +1092,7 @@ func Test_Engine_Lister_Gate_Node_All(t *testing.T) {
 	{
 		eon = engine.New(engine.Config{
 			Logger: logger.Fake(),
-			Redigo: prgAll(redigo.Default()),
+			Store:  prgAll(defSto()),
 			Timer:  tim,
 		})
 	}
@@ -0,0 +1,251 @@
+package conformance
+
+import (
+	"testing"
+	"time"
+
+	"github.com/xh3b4sd/logger"
+	"github.com/xh3b4sd/rescue"
+	"github.com/xh3b4sd/rescue/engine"
+	"github.com/xh3b4sd/rescue/store"
+	"github.com/xh3b4sd/rescue/task"
+	"github.com/xh3b4sd/rescue/timer"
+)
+
+// Test_Engine_Multicast ensures that tasks defining the delivery method "mny"
+// are processed once by every target worker, and only by them, and that those
+// tasks are removed once every target worker completed them.
+func Test_Engine_Multicast(t *testing.T) {
+	var err error
+
+	var now time.Time
+	{
+		now = musTim("2023-10-20T00:00:00Z")
+	}
+
+	var tim *timer.Timer
+	{
+		tim = timer.New()
+	}
+
+	{
+		tim.Setter(func() time.Time {
+			return now
+		})
+	}
+
+	var sto store.Interface
+	{
+		sto = prgAll(defSto())
+	}
+
+	var eng []rescue.Interface
+	for _, x := range []string{"eon", "etw", "eth"} {
+		eng = append(eng, engine.New(engine.Config{
+			Logger: logger.Fake(),
+			Store:  sto,
+			Timer:  tim,
+			Worker: x,
+		}))
+	}
+
+	var eon rescue.Interface
+	var etw rescue.Interface
+	var eth rescue.Interface
+	{
+		eon = eng[0]
+		etw = eng[1]
+		eth = eng[2]
+	}
+
+	{
+		err = eon.Create(&task.Task{
+			Meta: &task.Meta{"test.api.io/key": "val"},
+			Node: &task.Node{task.Method: task.MthdMny, task.Worker: "eon,etw"},
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	// eth is not a target worker and must never be provided with the task.
+	{
+		_, err = eth.Search()
+		if !engine.IsTaskNotFound(err) {
+			t.Fatal("expected", "taskNotFoundError", "got", err)
+		}
+	}
+
+	var tas *task.Task
+	{
+		tas, err = eon.Search()
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if tas.Core.Get().Worker() != "" {
+			t.Fatal("expected", "unowned task", "got", tas.Core.Get().Worker())
+		}
+	}
+
+	// eon is processing the task already, and so it must not get the task
+	// again.
+	{
+		_, err = eon.Search()
+		if !engine.IsTaskNotFound(err) {
+			t.Fatal("expected", "taskNotFoundError", "got", err)
+		}
+	}
+
+	{
+		err = eon.Delete(tas)
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	{
+		lis, err := eon.Lister(engine.All())
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if len(lis) != 1 {
+			t.Fatal("expected", 1, "got", len(lis))
+		}
+		if lis[0].Core.Get().Complete() != "eon" {
+			t.Fatal("expected", "eon", "got", lis[0].Core.Get().Complete())
+		}
+	}
+
+	// eon completed the task already, and so it can neither get nor complete the
+	// task again.
+	{
+		_, err = eon.Search()
+		if !engine.IsTaskNotFound(err) {
+			t.Fatal("expected", "taskNotFoundError", "got", err)
+		}
+	}
+
+	{
+		err = eon.Delete(tas)
+		if !engine.IsTaskOutdated(err) {
+			t.Fatal("expected", "taskOutdatedError", "got", err)
+		}
+	}
+
+	// etw fails to process the task within its expiry, and so it gets the task
+	// again afterwards.
+	{
+		_, err = etw.Search()
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	{
+		now = now.Add(engine.Expiry + time.Second)
+	}
+
+	{
+		tas, err = etw.Search()
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	{
+		err = etw.Delete(tas)
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	{
+		lis, err := eon.Lister(engine.All())
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if len(lis) != 0 {
+			t.Fatal("expected", 0, "got", len(lis))
+		}
+	}
+}
+
+// Test_Engine_Multicast_Cleanup ensures that tasks defining the delivery
+// method "mny" are removed once the retention period passed, even if not every
+// target worker completed them.
+func Test_Engine_Multicast_Cleanup(t *testing.T) {
+	var err error
+
+	var now time.Time
+	{
+		now = musTim("2023-10-20T00:00:00Z")
+	}
+
+	var tim *timer.Timer
+	{
+		tim = timer.New()
+	}
+
+	{
+		tim.Setter(func() time.Time {
+			return now
+		})
+	}
+
+	var eon rescue.Interface
+	{
+		eon = engine.New(engine.Config{
+			Logger: logger.Fake(),
+			Store:  prgAll(defSto()),
+			Timer:  tim,
+			Worker: "eon",
+		})
+	}
+
+	{
+		err = eon.Create(&task.Task{
+			Meta: &task.Meta{"test.api.io/key": "val"},
+			Node: &task.Node{task.Method: task.MthdMny, task.Worker: "eon,etw"},
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	{
+		tas, err := eon.Search()
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		err = eon.Delete(tas)
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	{
+		now = now.Add(engine.Week + time.Second)
+	}
+
+	{
+		err = eon.Expire()
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	{
+		lis, err := eon.Lister(engine.All())
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if len(lis) != 0 {
+			t.Fatal("expected", 0, "got", len(lis))
+		}
+	}
+}
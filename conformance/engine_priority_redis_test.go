@@ -0,0 +1,74 @@
+package conformance
+
+import (
+	"testing"
+	"time"
+
+	"github.com/xh3b4sd/logger"
+	"github.com/xh3b4sd/rescue"
+	"github.com/xh3b4sd/rescue/engine"
+	"github.com/xh3b4sd/rescue/task"
+	"github.com/xh3b4sd/rescue/timer"
+)
+
+// Test_Engine_Priority ensures that tasks with a higher priority are claimed
+// first, while tasks of the same priority are claimed in the order of their
+// creation.
+func Test_Engine_Priority(t *testing.T) {
+	var err error
+
+	var tim *timer.Timer
+	{
+		tim = timer.New()
+	}
+
+	var eon rescue.Interface
+	{
+		eon = engine.New(engine.Config{
+			Logger: logger.Fake(),
+			Store:  prgAll(defSto()),
+			Timer:  tim,
+			Worker: "eon",
+		})
+	}
+
+	var tas []*task.Task
+	{
+		tas = []*task.Task{
+			{Meta: &task.Meta{"test.api.io/key": "low"}, Core: &task.Core{task.Priority: "-1"}},
+			{Meta: &task.Meta{"test.api.io/key": "de1"}},
+			{Meta: &task.Meta{"test.api.io/key": "hi1"}, Core: &task.Core{task.Priority: "5"}},
+			{Meta: &task.Meta{"test.api.io/key": "de2"}},
+			{Meta: &task.Meta{"test.api.io/key": "hi2"}, Core: &task.Core{task.Priority: "5"}},
+		}
+	}
+
+	// Tasks are created one second apart from each other, so that their object
+	// IDs reflect the order of their creation.
+	for i, x := range tas {
+		tim.Setter(func() time.Time {
+			return musTim("2023-10-20T00:00:00Z").Add(time.Duration(i) * time.Second)
+		})
+
+		err = eon.Create(x)
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	for _, x := range []string{"hi1", "hi2", "de1", "de2", "low"} {
+		tas, err := eon.Search()
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if tas.Meta.Get("test.api.io/key") != x {
+			t.Fatal("expected", x, "got", tas.Meta.Get("test.api.io/key"))
+		}
+
+		err = eon.Delete(tas)
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+}
@@ -0,0 +1,213 @@
+package conformance
+
+import (
+	"fmt"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/xh3b4sd/logger"
+	"github.com/xh3b4sd/rescue"
+	"github.com/xh3b4sd/rescue/engine"
+	"github.com/xh3b4sd/rescue/metric"
+	"github.com/xh3b4sd/rescue/store"
+	"github.com/xh3b4sd/rescue/task"
+	"github.com/xh3b4sd/rescue/timer"
+)
+
+// Test_Engine_Rate ensures that workers never claim more tasks matching a rate
+// limit than the rule's token bucket allows, and that throttled tasks are
+// skipped in favour of other tasks.
+func Test_Engine_Rate(t *testing.T) {
+	var err error
+
+	var now time.Time
+	{
+		now = musTim("2023-10-20T00:00:00Z")
+	}
+
+	var tim *timer.Timer
+	{
+		tim = timer.New()
+	}
+
+	{
+		tim.Setter(func() time.Time {
+			return now
+		})
+	}
+
+	var met *metric.Collection
+	{
+		met = metric.Default()
+	}
+
+	var eon rescue.Interface
+	{
+		eon = engine.New(engine.Config{
+			Logger: logger.Fake(),
+			Metric: met,
+			Rate: []engine.Rate{
+				{Limit: 2, Meta: &task.Meta{"test.api.io/provider": "github"}, Window: time.Minute},
+			},
+			Store:  prgAll(defSto()),
+			Timer:  tim,
+			Worker: "eon",
+		})
+	}
+
+	for _, x := range []string{"github", "github", "github", "github", "gitlab"} {
+		err = eon.Create(&task.Task{Meta: &task.Meta{"test.api.io/provider": x}})
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	{
+		lis, err := eon.SearchN(10)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		cnt := map[string]int{}
+		for _, x := range lis {
+			cnt[x.Meta.Get("test.api.io/provider")]++
+		}
+
+		if cnt["github"] != 2 {
+			t.Fatal("expected", 2, "got", cnt["github"])
+		}
+		if cnt["gitlab"] != 1 {
+			t.Fatal("expected", 1, "got", cnt["gitlab"])
+		}
+	}
+
+	{
+		_, err = eon.Search()
+		if !engine.IsTaskNotFound(err) {
+			t.Fatal("expected", "taskNotFoundError", "got", err)
+		}
+	}
+
+	{
+		if met.Task.Throttled.Get() != 2 {
+			t.Fatal("expected", 2, "got", met.Task.Throttled.Get())
+		}
+	}
+
+	// Half of the window refills half of the tokens.
+	{
+		now = now.Add(30 * time.Second)
+	}
+
+	{
+		_, err = eon.Search()
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	{
+		_, err = eon.Search()
+		if !engine.IsTaskNotFound(err) {
+			t.Fatal("expected", "taskNotFoundError", "got", err)
+		}
+	}
+
+	{
+		now = now.Add(30 * time.Second)
+	}
+
+	{
+		_, err = eon.Search()
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+}
+
+// Test_Engine_Rate_Race ensures that rate limits hold across many workers
+// claiming tasks concurrently.
+func Test_Engine_Rate_Race(t *testing.T) {
+	var err error
+
+	var tim *timer.Timer
+	{
+		tim = timer.New()
+	}
+
+	{
+		tim.Setter(func() time.Time {
+			return musTim("2023-10-20T00:00:00Z")
+		})
+	}
+
+	var sto store.Interface
+	{
+		sto = prgAll(defSto())
+	}
+
+	var eng []rescue.Interface
+	for i := 0; i < 5; i++ {
+		eng = append(eng, engine.New(engine.Config{
+			Logger: logger.Fake(),
+			Rate: []engine.Rate{
+				{Limit: 3, Meta: &task.Meta{"test.api.io/provider": "github"}, Window: time.Hour},
+			},
+			Store:  sto,
+			Timer:  tim,
+			Worker: fmt.Sprintf("e%02d", i),
+		}))
+	}
+
+	for i := 0; i < 20; i++ {
+		err = eng[0].Create(&task.Task{Meta: &task.Meta{"test.api.io/provider": "github"}})
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	var wai sync.WaitGroup
+
+	for _, x := range eng {
+		wai.Add(1)
+
+		go func(x rescue.Interface) {
+			defer wai.Done()
+
+			for j := 0; j < 5; j++ {
+				_, err := x.Search()
+				if engine.IsTaskNotFound(err) {
+					continue
+				} else if err != nil {
+					panic(err)
+				}
+			}
+		}(x)
+	}
+
+	{
+		wai.Wait()
+	}
+
+	var lis []*task.Task
+	{
+		lis, err = eng[0].Lister(engine.All())
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	var own int
+	for _, x := range lis {
+		if x.Core.Get().Worker() != "" {
+			own++
+		}
+	}
+
+	{
+		if own != 3 {
+			t.Fatal("expected", 3, "got", own)
+		}
+	}
+}
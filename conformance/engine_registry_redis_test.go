@@ -0,0 +1,244 @@
+package conformance
+
+import (
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/xh3b4sd/logger"
+	"github.com/xh3b4sd/rescue"
+	"github.com/xh3b4sd/rescue/engine"
+	"github.com/xh3b4sd/rescue/store"
+	"github.com/xh3b4sd/rescue/task"
+	"github.com/xh3b4sd/rescue/timer"
+)
+
+// Test_Engine_Registry ensures that workers announce themselves within the
+// worker registry, that the balancer accounts for live workers not owning any
+// task yet, and that workers which stopped sending heartbeats are dropped.
+func Test_Engine_Registry(t *testing.T) {
+	var err error
+
+	var now time.Time
+	{
+		now = musTim("2023-10-20T00:00:00Z")
+	}
+
+	var tim *timer.Timer
+	{
+		tim = timer.New()
+	}
+
+	{
+		tim.Setter(func() time.Time {
+			return now
+		})
+	}
+
+	var sto store.Interface
+	{
+		sto = prgAll(defSto())
+	}
+
+	var eon rescue.Interface
+	{
+		eon = engine.New(engine.Config{
+			Cleanup: time.Hour,
+			Labels:  map[string]string{"region": "eu"},
+			Logger:  logger.Fake(),
+			Store:   sto,
+			Timer:   tim,
+			Worker:  "eon",
+		})
+	}
+
+	var etw rescue.Interface
+	{
+		etw = engine.New(engine.Config{
+			Cleanup: time.Hour,
+			Logger:  logger.Fake(),
+			Store:   sto,
+			Timer:   tim,
+			Worker:  "etw",
+		})
+	}
+
+	for _, x := range []rescue.Interface{etw, eon, etw} {
+		err = x.Heartbeat()
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	{
+		lis, err := eon.Registry()
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		exp := []*engine.Member{
+			{Heartbeat: now, Labels: map[string]string{"region": "eu"}, Progress: now, Start: now, Worker: "eon"},
+			{Heartbeat: now, Progress: now, Start: now, Worker: "etw"},
+		}
+
+		if dif := cmp.Diff(exp, lis); dif != "" {
+			t.Fatalf("-expected +actual:\n%s", dif)
+		}
+	}
+
+	for i := 0; i < 4; i++ {
+		err = eon.Create(&task.Task{Meta: &task.Meta{"test.api.io/key": "val"}})
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	// etw is alive without owning any task, which is why eon must only claim
+	// its fair share of tasks.
+	{
+		lis, err := eon.SearchN(10)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if len(lis) != 2 {
+			t.Fatal("expected", 2, "got", len(lis))
+		}
+	}
+
+	{
+		now = now.Add(engine.Heartbeat + time.Second)
+	}
+
+	{
+		err = eon.Heartbeat()
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	{
+		lis, err := etw.Registry()
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if len(lis) != 1 {
+			t.Fatal("expected", 1, "got", len(lis))
+		}
+		if lis[0].Worker != "eon" {
+			t.Fatal("expected", "eon", "got", lis[0].Worker)
+		}
+		if !lis[0].Start.Before(lis[0].Heartbeat) {
+			t.Fatal("expected", "start before heartbeat", "got", lis[0].Start, lis[0].Heartbeat)
+		}
+	}
+
+	// etw stopped sending heartbeats, which is why eon may claim all remaining
+	// tasks.
+	{
+		lis, err := eon.SearchN(10)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if len(lis) != 2 {
+			t.Fatal("expected", 2, "got", len(lis))
+		}
+	}
+
+	// The record of etw is removed once the cleanup period passed.
+	{
+		now = now.Add(time.Hour)
+	}
+
+	{
+		err = eon.Heartbeat()
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	{
+		err = eon.Expire()
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	{
+		lis, err := sto.Lister(eon.Keyfmt() + ":registry")
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if len(lis) != 1 {
+			t.Fatal("expected", 1, "got", len(lis))
+		}
+	}
+}
+
+// Test_Engine_Registry_Uni ensures that live workers which cannot own any
+// task do not take the balanced share of the workers that can, e.g. tasks
+// defining the delivery method "uni".
+func Test_Engine_Registry_Uni(t *testing.T) {
+	var err error
+
+	var sto store.Interface
+	{
+		sto = prgAll(defSto())
+	}
+
+	var eon rescue.Interface
+	{
+		eon = engine.New(engine.Config{
+			Logger: logger.Fake(),
+			Store:  sto,
+			Worker: "a",
+		})
+	}
+
+	var etw rescue.Interface
+	{
+		etw = engine.New(engine.Config{
+			Logger: logger.Fake(),
+			Store:  sto,
+			Worker: "b",
+		})
+	}
+
+	for _, x := range []rescue.Interface{eon, etw} {
+		err = x.Heartbeat()
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	{
+		err = eon.Create(&task.Task{
+			Meta: &task.Meta{"test.api.io/key": "val"},
+			Node: &task.Node{task.Method: task.MthdUni, task.Worker: "b"},
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	{
+		_, err = eon.Search()
+		if !engine.IsTaskNotFound(err) {
+			t.Fatal("expected", "taskNotFoundError", "got", err)
+		}
+	}
+
+	{
+		tas, err := etw.Search()
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if tas.Core.Get().Worker() != "b" {
+			t.Fatal("expected", "b", "got", tas.Core.Get().Worker())
+		}
+	}
+}
@@ -0,0 +1,181 @@
+package conformance
+
+import (
+	"testing"
+	"time"
+
+	"github.com/xh3b4sd/logger"
+	"github.com/xh3b4sd/rescue"
+	"github.com/xh3b4sd/rescue/engine"
+	"github.com/xh3b4sd/rescue/store"
+	"github.com/xh3b4sd/rescue/task"
+	"github.com/xh3b4sd/rescue/timer"
+)
+
+// Test_Engine_Release ensures that released tasks can be claimed again by
+// other workers right away, unless their next execution got deferred, and that
+// only owners can release their tasks.
+func Test_Engine_Release(t *testing.T) {
+	var err error
+
+	var tim *timer.Timer
+	{
+		tim = timer.New()
+	}
+
+	{
+		tim.Setter(func() time.Time {
+			return musTim("2023-10-20T00:00:00Z")
+		})
+	}
+
+	var sto store.Interface
+	{
+		sto = prgAll(defSto())
+	}
+
+	var eon rescue.Interface
+	{
+		eon = engine.New(engine.Config{
+			Logger: logger.Fake(),
+			Store:  sto,
+			Timer:  tim,
+			Worker: "eon",
+		})
+	}
+
+	var etw rescue.Interface
+	{
+		etw = engine.New(engine.Config{
+			Logger: logger.Fake(),
+			Store:  sto,
+			Timer:  tim,
+			Worker: "etw",
+		})
+	}
+
+	{
+		err = eon.Create(&task.Task{Meta: &task.Meta{"test.api.io/key": "foo"}})
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	var tas *task.Task
+	{
+		tas, err = eon.Search()
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	// Tasks can only be released by their owners.
+	{
+		cop := task.FromString(task.ToString(tas))
+		cop.Core.Set().Worker("etw")
+
+		err = etw.Release(cop, engine.ReleaseOptions{})
+		if !engine.IsTaskOutdated(err) {
+			t.Fatal("expected", "taskOutdatedError", "got", err)
+		}
+	}
+
+	{
+		err = eon.Release(tas, engine.ReleaseOptions{Reason: "upstream unavailable"})
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	// Released tasks cannot be released twice.
+	{
+		err = eon.Release(tas, engine.ReleaseOptions{})
+		if !engine.IsTaskOutdated(err) {
+			t.Fatal("expected", "taskOutdatedError", "got", err)
+		}
+	}
+
+	{
+		tas, err = etw.Search()
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if tas.Core.Get().Worker() != "etw" {
+			t.Fatal("expected", "etw", "got", tas.Core.Get().Worker())
+		}
+		if tas.Core.Get().Cycles() != 1 {
+			t.Fatal("expected", 1, "got", tas.Core.Get().Cycles())
+		}
+		if tas.Core.Get().Reason() != "upstream unavailable" {
+			t.Fatal("expected", "upstream unavailable", "got", tas.Core.Get().Reason())
+		}
+	}
+
+	{
+		err = etw.Release(tas, engine.ReleaseOptions{Defer: "5 minutes"})
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	// The retry delay is recorded as tick+1, without turning the released task
+	// into a deferred task.
+	{
+		lis, err := eon.Lister(engine.All())
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if lis[0].Cron.Len() != 1 || !lis[0].Cron.Get().TickP1().Equal(musTim("2023-10-20T00:05:00Z")) {
+			t.Fatal("expected", musTim("2023-10-20T00:05:00Z"), "got", lis[0].Cron)
+		}
+	}
+
+	// Deferred tasks cannot be claimed before their retry delay passed.
+	{
+		_, err = eon.Search()
+		if !engine.IsTaskNotFound(err) {
+			t.Fatal("expected", "taskNotFoundError", "got", err)
+		}
+	}
+
+	{
+		tim.Setter(func() time.Time {
+			return musTim("2023-10-20T00:05:00Z")
+		})
+	}
+
+	{
+		tas, err = eon.Search()
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if tas.Core.Get().Cycles() != 2 {
+			t.Fatal("expected", 2, "got", tas.Core.Get().Cycles())
+		}
+		if tas.Cron != nil {
+			t.Fatal("expected", nil, "got", tas.Cron)
+		}
+	}
+
+	// Deleting the released task completes it, instead of deferring it again.
+	{
+		err = eon.Delete(tas)
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	{
+		lis, err := eon.Lister(engine.All())
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if len(lis) != 0 {
+			t.Fatal("expected", 0, "got", len(lis))
+		}
+	}
+}
@@ -0,0 +1,342 @@
+package conformance
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/xh3b4sd/logger"
+	"github.com/xh3b4sd/rescue"
+	"github.com/xh3b4sd/rescue/engine"
+	"github.com/xh3b4sd/rescue/store"
+	"github.com/xh3b4sd/rescue/task"
+)
+
+// Test_Engine_Search_Race ensures that concurrent workers never claim the same
+// task twice, even though searching for tasks does not acquire the global lock.
+func Test_Engine_Search_Race(t *testing.T) {
+	var err error
+
+	var sto store.Interface
+	{
+		sto = prgAll(defSto())
+	}
+
+	var eng []rescue.Interface
+	for i := 0; i < 5; i++ {
+		eng = append(eng, engine.New(engine.Config{
+			Logger: logger.Fake(),
+			Store:  sto,
+			Worker: fmt.Sprintf("e%02d", i),
+		}))
+	}
+
+	for i := 0; i < 20; i++ {
+		tas := &task.Task{
+			Meta: &task.Meta{
+				"test.api.io/key": fmt.Sprintf("%02d", i),
+			},
+		}
+
+		err = eng[0].Create(tas)
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	var mut sync.Mutex
+	var wai sync.WaitGroup
+
+	cla := map[string]string{}
+	dup := map[string]int{}
+	own := map[string][]*task.Task{}
+
+	for _, x := range eng {
+		wai.Add(1)
+
+		go func(x rescue.Interface) {
+			defer wai.Done()
+
+			for j := 0; j < 20; j++ {
+				tas, err := x.Search()
+				if engine.IsTaskNotFound(err) {
+					continue
+				} else if err != nil {
+					panic(err)
+				}
+
+				mut.Lock()
+				{
+					oid := tas.Core.Map().Object()
+
+					if cla[oid] != "" {
+						dup[oid]++
+					}
+
+					cla[oid] = tas.Core.Get().Worker()
+					own[tas.Core.Get().Worker()] = append(own[tas.Core.Get().Worker()], tas)
+				}
+				mut.Unlock()
+			}
+		}(x)
+	}
+
+	{
+		wai.Wait()
+	}
+
+	{
+		if len(dup) != 0 {
+			t.Fatal("expected", 0, "got", len(dup))
+		}
+		if len(cla) == 0 {
+			t.Fatal("expected tasks to be claimed")
+		}
+	}
+
+	for _, x := range eng {
+		wai.Add(1)
+
+		go func(x rescue.Interface) {
+			defer wai.Done()
+
+			for _, y := range own[x.Worker()] {
+				err := x.Delete(y)
+				if err != nil {
+					panic(err)
+				}
+			}
+		}(x)
+	}
+
+	{
+		wai.Wait()
+	}
+
+	var lis []*task.Task
+	{
+		lis, err = eng[0].Lister(engine.All())
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	{
+		if len(lis)+len(cla) != 20 {
+			t.Fatal("expected", 20, "got", len(lis)+len(cla))
+		}
+	}
+}
+
+// Test_Engine_SearchN ensures that workers can claim multiple tasks at once,
+// while tasks addressed to a particular worker are still preferred, and
+// ownership is never assigned twice.
+func Test_Engine_SearchN(t *testing.T) {
+	var err error
+
+	var sto store.Interface
+	{
+		sto = prgAll(defSto())
+	}
+
+	var eon rescue.Interface
+	{
+		eon = engine.New(engine.Config{
+			Logger: logger.Fake(),
+			Store:  sto,
+			Worker: "eon",
+		})
+	}
+
+	var etw rescue.Interface
+	{
+		etw = engine.New(engine.Config{
+			Logger: logger.Fake(),
+			Store:  sto,
+			Worker: "etw",
+		})
+	}
+
+	for i := 0; i < 6; i++ {
+		tas := &task.Task{
+			Meta: &task.Meta{
+				"test.api.io/key": fmt.Sprintf("%02d", i),
+			},
+		}
+
+		err = eon.Create(tas)
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	{
+		tas := &task.Task{
+			Meta: &task.Meta{
+				"test.api.io/key": "uni",
+			},
+			Node: &task.Node{
+				task.Method: task.MthdUni,
+				task.Worker: "eon",
+			},
+		}
+
+		err = eon.Create(tas)
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	{
+		_, err = eon.SearchN(0)
+		if !engine.IsSearchCount(err) {
+			t.Fatal("expected", "searchCountError", "got", err)
+		}
+	}
+
+	cla := map[string]string{}
+
+	{
+		lis, err := eon.SearchN(3)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if len(lis) != 3 {
+			t.Fatal("expected", 3, "got", len(lis))
+		}
+		if lis[0].Meta.Get("test.api.io/key") != "uni" {
+			t.Fatal("expected", "uni", "got", lis[0].Meta.Get("test.api.io/key"))
+		}
+
+		for _, x := range lis {
+			cla[x.Core.Map().Object()] = x.Core.Get().Worker()
+		}
+	}
+
+	{
+		lis, err := etw.SearchN(10)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if len(lis) == 0 || len(lis) > 4 {
+			t.Fatal("expected", "1 to 4", "got", len(lis))
+		}
+
+		for _, x := range lis {
+			if cla[x.Core.Map().Object()] != "" {
+				t.Fatal("expected", "unclaimed task", "got", cla[x.Core.Map().Object()])
+			}
+
+			cla[x.Core.Map().Object()] = x.Core.Get().Worker()
+		}
+	}
+
+	{
+		lis, err := eon.Lister(engine.All())
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		for _, x := range lis {
+			if x.Core.Get().Worker() != cla[x.Core.Map().Object()] {
+				t.Fatal("expected", cla[x.Core.Map().Object()], "got", x.Core.Get().Worker())
+			}
+			if x.Core.Get().Worker() != "" && x.Core.Get().Expiry().IsZero() {
+				t.Fatal("expected", "expiry", "got", "zero time")
+			}
+		}
+	}
+}
+
+// Test_Engine_SearchWait ensures that workers blocked in Engine.SearchWait get
+// woken up as soon as a task got created, long before the configured wakeup
+// interval elapsed.
+func Test_Engine_SearchWait(t *testing.T) {
+	var err error
+
+	var sto store.Interface
+	{
+		sto = prgAll(defSto())
+	}
+
+	var eon rescue.Interface
+	{
+		eon = engine.New(engine.Config{
+			Logger: logger.Fake(),
+			Store:  sto,
+			Wakeup: time.Minute,
+			Worker: "eon",
+		})
+	}
+
+	var etw rescue.Interface
+	{
+		etw = engine.New(engine.Config{
+			Logger: logger.Fake(),
+			Store:  sto,
+			Worker: "etw",
+		})
+	}
+
+	{
+		ctx, can := context.WithTimeout(context.Background(), 50*time.Millisecond)
+		defer can()
+
+		_, err = eon.SearchWait(ctx)
+		if !errors.Is(err, context.DeadlineExceeded) {
+			t.Fatal("expected", context.DeadlineExceeded, "got", err)
+		}
+	}
+
+	var tas *task.Task
+	var don chan struct{}
+	{
+		don = make(chan struct{})
+	}
+
+	go func() {
+		defer close(don)
+
+		ctx, can := context.WithTimeout(context.Background(), 10*time.Second)
+		defer can()
+
+		tas, err = eon.SearchWait(ctx)
+	}()
+
+	{
+		time.Sleep(100 * time.Millisecond)
+	}
+
+	var sta time.Time
+	{
+		sta = time.Now()
+	}
+
+	{
+		err := etw.Create(&task.Task{Meta: &task.Meta{"test.api.io/key": "foo"}})
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	{
+		<-don
+	}
+
+	{
+		if err != nil {
+			t.Fatal(err)
+		}
+		if tas.Meta.Get("test.api.io/key") != "foo" {
+			t.Fatal("expected", "foo", "got", tas.Meta.Get("test.api.io/key"))
+		}
+		if time.Since(sta) > 5*time.Second {
+			t.Fatal("expected", "notification", "got", "wakeup after", time.Since(sta))
+		}
+	}
+}
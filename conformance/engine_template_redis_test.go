@@ -0,0 +1,294 @@
+package conformance
+
+import (
+	"testing"
+
+	"github.com/xh3b4sd/logger"
+	"github.com/xh3b4sd/objectid"
+	"github.com/xh3b4sd/rescue"
+	"github.com/xh3b4sd/rescue/engine"
+	"github.com/xh3b4sd/rescue/store"
+	"github.com/xh3b4sd/rescue/task"
+)
+
+func Test_Engine_Template(t *testing.T) {
+	var err error
+
+	var sto store.Interface
+	{
+		sto = prgAll(defSto())
+	}
+
+	var eon rescue.Interface
+	{
+		eon = engine.New(engine.Config{
+			Logger: logger.Fake(),
+			Store:  sto,
+			Worker: "eon",
+		})
+	}
+
+	{
+		tas := &task.Task{
+			Cron: &task.Cron{
+				task.Aevery: "hour",
+			},
+			Meta: &task.Meta{
+				"test.api.io/key": "crn",
+			},
+		}
+
+		err = eon.Create(tas)
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	{
+		tas := &task.Task{
+			Gate: &task.Gate{
+				"test.api.io/k-0": task.Waiting,
+			},
+			Meta: &task.Meta{
+				"test.api.io/key": "gat",
+			},
+		}
+
+		err = eon.Create(tas)
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	{
+		tas := &task.Task{
+			Meta: &task.Meta{
+				"test.api.io/key": "run",
+			},
+		}
+
+		err = eon.Create(tas)
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	// Task templates must be stored separately from runnable tasks.
+	{
+		lis, err := sto.Lister(eon.Keyfmt())
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if len(lis) != 1 {
+			t.Fatal("expected", 1, "got", len(lis))
+		}
+	}
+
+	{
+		lis, err := eon.Template(engine.All())
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if len(lis) != 2 {
+			t.Fatal("expected", 2, "got", len(lis))
+		}
+		if lis[0].Meta.Get("test.api.io/key") == lis[1].Meta.Get("test.api.io/key") {
+			t.Fatal("expected", "different task templates", "got", "the same")
+		}
+		if lis[0].Meta.Get("test.api.io/key") == "run" || lis[1].Meta.Get("test.api.io/key") == "run" {
+			t.Fatal("expected", "task templates", "got", "runnable task")
+		}
+	}
+
+	{
+		lis, err := eon.Template(&task.Task{Meta: &task.Meta{"test.api.io/key": "run"}})
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if len(lis) != 0 {
+			t.Fatal("expected", 0, "got", len(lis))
+		}
+	}
+
+	// Lister must return task templates and runnable tasks together.
+	{
+		lis, err := eon.Lister(engine.All())
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if len(lis) != 3 {
+			t.Fatal("expected", 3, "got", len(lis))
+		}
+	}
+
+	{
+		tas, err := eon.Search()
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if tas.Meta.Get("test.api.io/key") != "run" {
+			t.Fatal("expected", "run", "got", tas.Meta.Get("test.api.io/key"))
+		}
+	}
+
+	// Task templates must be deletable using the bypass label.
+	{
+		lis, err := eon.Template(&task.Task{Meta: &task.Meta{"test.api.io/key": "gat"}})
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		lis[0].Core.Set().Bypass(true)
+
+		err = eon.Delete(lis[0])
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	{
+		lis, err := eon.Template(engine.All())
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if len(lis) != 1 {
+			t.Fatal("expected", 1, "got", len(lis))
+		}
+	}
+}
+
+// Test_Engine_Template_Migrate ensures that task templates of queues created
+// before task templates got stored separately are still found, and that they
+// get migrated transparently.
+func Test_Engine_Template_Migrate(t *testing.T) {
+	var err error
+
+	var sto store.Interface
+	{
+		sto = prgAll(defSto())
+	}
+
+	var eon rescue.Interface
+	{
+		eon = engine.New(engine.Config{
+			Logger: logger.Fake(),
+			Store:  sto,
+			Worker: "eon",
+		})
+	}
+
+	// Simulate a task template that got created within the sorted set of
+	// runnable tasks.
+	{
+		tas := &task.Task{
+			Core: &task.Core{},
+			Gate: &task.Gate{
+				"test.api.io/k-0": task.Waiting,
+			},
+			Meta: &task.Meta{
+				"test.api.io/key": "gat",
+			},
+			Node: &task.Node{
+				task.Method: task.MthdAny,
+			},
+		}
+
+		var oid objectid.ID
+		{
+			oid = objectid.Random(objectid.Time(musTim("2023-10-20T00:00:00Z")))
+		}
+
+		{
+			tas.Core.Set().Object(oid)
+		}
+
+		err = sto.Create(eon.Keyfmt(), oid, task.ToString(tas))
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	{
+		lis, err := eon.Template(engine.All())
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if len(lis) != 1 {
+			t.Fatal("expected", 1, "got", len(lis))
+		}
+	}
+
+	// Trigger tasks must still update task templates that did not get migrated
+	// yet.
+	{
+		tas := &task.Task{
+			Gate: &task.Gate{
+				"test.api.io/k-0": task.Trigger,
+			},
+			Meta: &task.Meta{
+				"test.api.io/key": "tri",
+			},
+		}
+
+		err = eon.Create(tas)
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	{
+		tas, err := eon.Search()
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		err = eon.Delete(tas)
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	{
+		err = eon.Expire()
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	// The triggered task remains within the sorted set of runnable tasks, while
+	// the task template got migrated.
+	{
+		lis, err := sto.Lister(eon.Keyfmt())
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if len(lis) != 1 {
+			t.Fatal("expected", 1, "got", len(lis))
+		}
+		if task.FromString(lis[0]).Root == nil {
+			t.Fatal("expected triggered task")
+		}
+	}
+
+	{
+		lis, err := eon.Template(engine.All())
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if len(lis) != 1 {
+			t.Fatal("expected", 1, "got", len(lis))
+		}
+		if lis[0].Gate.Get("test.api.io/k-0") != task.Waiting {
+			t.Fatal("expected", task.Waiting, "got", lis[0].Gate.Get("test.api.io/k-0"))
+		}
+	}
+}
@@ -1,27 +1,25 @@
-//go:build redis
-
 package conformance
 
 import (
 	"testing"
 
 	"github.com/xh3b4sd/logger"
-	"github.com/xh3b4sd/redigo"
 	"github.com/xh3b4sd/rescue"
 	"github.com/xh3b4sd/rescue/engine"
+	"github.com/xh3b4sd/rescue/store"
 	"github.com/xh3b4sd/rescue/task"
 )
 
 func Test_Engine_Exists_Host(t *testing.T) {
 	var err error
 
-	var red redigo.Interface
+	var sto store.Interface
 	{
-		red = redigo.Default()
+		sto = defSto()
 	}
 
 	{
-		err = red.Purge()
+		err = sto.Purge()
 		if err != nil {
 			t.Fatal(err)
 		}
@@ -31,7 +29,7 @@ func Test_Engine_Exists_Host(t *testing.T) {
 	{
 		eon = engine.New(engine.Config{
 			Logger: logger.Fake(),
-			Redigo: red,
+			Store:  sto,
 		})
 	}
 
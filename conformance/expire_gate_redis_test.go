@@ -1,5 +1,3 @@
-//go:build redis
-
 package conformance
 
 import (
@@ -9,9 +7,9 @@ import (
 
 	"github.com/google/go-cmp/cmp"
 	"github.com/xh3b4sd/logger"
-	"github.com/xh3b4sd/redigo"
 	"github.com/xh3b4sd/rescue"
 	"github.com/xh3b4sd/rescue/engine"
+	"github.com/xh3b4sd/rescue/store"
 	"github.com/xh3b4sd/rescue/task"
 	"github.com/xh3b4sd/rescue/timer"
 )
@@ -19,13 +17,13 @@ import (
 func Test_Engine_Expire_Gate(t *testing.T) {
 	var err error
 
-	var red redigo.Interface
+	var sto store.Interface
 	{
-		red = redigo.Default()
+		sto = defSto()
 	}
 
 	{
-		err = red.Purge()
+		err = sto.Purge()
 		if err != nil {
 			t.Fatal(err)
 		}
@@ -49,7 +47,7 @@ func Test_Engine_Expire_Gate(t *testing.T) {
 	{
 		eon = engine.New(engine.Config{
 			Logger: logger.Fake(),
-			Redigo: red,
+			Store:  sto,
 			Timer:  tim,
 			Worker: "eon",
 		})
@@ -59,7 +57,7 @@ func Test_Engine_Expire_Gate(t *testing.T) {
 	{
 		etw = engine.New(engine.Config{
 			Logger: logger.Fake(),
-			Redigo: red,
+			Store:  sto,
 			Timer:  tim,
 			Worker: "etw",
 		})
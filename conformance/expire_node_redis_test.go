@@ -1,5 +1,3 @@
-//go:build redis
-
 package conformance
 
 import (
@@ -9,9 +7,9 @@ import (
 
 	"github.com/google/go-cmp/cmp"
 	"github.com/xh3b4sd/logger"
-	"github.com/xh3b4sd/redigo"
 	"github.com/xh3b4sd/rescue"
 	"github.com/xh3b4sd/rescue/engine"
+	"github.com/xh3b4sd/rescue/store"
 	"github.com/xh3b4sd/rescue/task"
 	"github.com/xh3b4sd/rescue/timer"
 )
@@ -19,13 +17,13 @@ import (
 func Test_Engine_Expire_Node_All(t *testing.T) {
 	var err error
 
-	var red redigo.Interface
+	var sto store.Interface
 	{
-		red = redigo.Default()
+		sto = defSto()
 	}
 
 	{
-		err = red.Purge()
+		err = sto.Purge()
 		if err != nil {
 			t.Fatal(err)
 		}
@@ -49,7 +47,7 @@ func Test_Engine_Expire_Node_All(t *testing.T) {
 	{
 		eon = engine.New(engine.Config{
 			Logger: logger.Fake(),
-			Redigo: red,
+			Store:  sto,
 			Timer:  tim,
 			Worker: "eon",
 		})
@@ -59,7 +57,7 @@ func Test_Engine_Expire_Node_All(t *testing.T) {
 	{
 		etw = engine.New(engine.Config{
 			Logger: logger.Fake(),
-			Redigo: red,
+			Store:  sto,
 			Timer:  tim,
 			Worker: "etw",
 		})
@@ -424,13 +422,13 @@ func Test_Engine_Expire_Node_All(t *testing.T) {
 func Test_Engine_Expire_Node_Any(t *testing.T) {
 	var err error
 
-	var red redigo.Interface
+	var sto store.Interface
 	{
-		red = redigo.Default()
+		sto = defSto()
 	}
 
 	{
-		err = red.Purge()
+		err = sto.Purge()
 		if err != nil {
 			t.Fatal(err)
 		}
@@ -441,7 +439,7 @@ func Test_Engine_Expire_Node_Any(t *testing.T) {
 		eon = engine.New(engine.Config{
 			Expiry: time.Millisecond,
 			Logger: logger.Fake(),
-			Redigo: red,
+			Store:  sto,
 			Worker: "eon",
 		})
 	}
@@ -451,7 +449,7 @@ func Test_Engine_Expire_Node_Any(t *testing.T) {
 		etw = engine.New(engine.Config{
 			Expiry: time.Millisecond,
 			Logger: logger.Fake(),
-			Redigo: red,
+			Store:  sto,
 			Worker: "etw",
 		})
 	}
@@ -508,7 +506,13 @@ func Test_Engine_Expire_Node_Any(t *testing.T) {
 	// For engine one we simulate failure so that the acquired task can expire and
 	// be rescheduled to engine two. For the simulation we call Expire which is
 	// the responsibility of every worker to do periodically. It does not matter
-	// which engine executes the expiration process.
+	// which engine executes the expiration process. Note that we have to wait
+	// for the configured expiry to pass, since the in-memory storage responds
+	// faster than the expiry of a single millisecond.
+	{
+		time.Sleep(2 * time.Millisecond)
+	}
+
 	{
 		err = eon.Expire()
 		if err != nil {
@@ -560,13 +564,13 @@ func Test_Engine_Expire_Node_Any(t *testing.T) {
 func Test_Engine_Expire_Node_Uni_Cleanup(t *testing.T) {
 	var err error
 
-	var red redigo.Interface
+	var sto store.Interface
 	{
-		red = redigo.Default()
+		sto = defSto()
 	}
 
 	{
-		err = red.Purge()
+		err = sto.Purge()
 		if err != nil {
 			t.Fatal(err)
 		}
@@ -590,7 +594,7 @@ func Test_Engine_Expire_Node_Uni_Cleanup(t *testing.T) {
 	{
 		etw = engine.New(engine.Config{
 			Logger: logger.Fake(),
-			Redigo: red,
+			Store:  sto,
 			Timer:  tim,
 			Worker: "etw",
 		})
@@ -707,13 +711,13 @@ func Test_Engine_Expire_Node_Uni_Cleanup(t *testing.T) {
 func Test_Engine_Expire_Node_Uni_Lifecycle(t *testing.T) {
 	var err error
 
-	var red redigo.Interface
+	var sto store.Interface
 	{
-		red = redigo.Default()
+		sto = defSto()
 	}
 
 	{
-		err = red.Purge()
+		err = sto.Purge()
 		if err != nil {
 			t.Fatal(err)
 		}
@@ -737,7 +741,7 @@ func Test_Engine_Expire_Node_Uni_Lifecycle(t *testing.T) {
 	{
 		eon = engine.New(engine.Config{
 			Logger: logger.Fake(),
-			Redigo: red,
+			Store:  sto,
 			Timer:  tim,
 			Worker: "eon",
 		})
@@ -747,7 +751,7 @@ func Test_Engine_Expire_Node_Uni_Lifecycle(t *testing.T) {
 	{
 		etw = engine.New(engine.Config{
 			Logger: logger.Fake(),
-			Redigo: red,
+			Store:  sto,
 			Timer:  tim,
 			Worker: "etw",
 		})
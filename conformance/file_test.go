@@ -0,0 +1,38 @@
+//go:build file
+
+package conformance
+
+import (
+	"os"
+	"path/filepath"
+
+	"github.com/xh3b4sd/rescue/store"
+	"github.com/xh3b4sd/tracer"
+)
+
+// fil is the file backed storage shared by all engines within a single
+// conformance test, just like the engines would share a single Redis instance.
+var fil = musFil()
+
+// defSto returns the storage implementation that the conformance tests are
+// executed against. Running the conformance tests with the file tag uses the
+// embedded file backed storage.
+func defSto() store.Interface {
+	return fil
+}
+
+func musFil() *store.File {
+	dir, err := os.MkdirTemp("", "rescue-conformance-")
+	if err != nil {
+		tracer.Panic(tracer.Mask(err))
+	}
+
+	sto, err := store.NewFile(store.FileConfig{
+		Path: filepath.Join(dir, "queue.log"),
+	})
+	if err != nil {
+		tracer.Panic(tracer.Mask(err))
+	}
+
+	return sto
+}
@@ -0,0 +1,18 @@
+//go:build !redis && !file
+
+package conformance
+
+import (
+	"github.com/xh3b4sd/rescue/store"
+)
+
+// mem is the process local storage shared by all engines within a single
+// conformance test, just like the engines would share a single Redis instance.
+var mem = store.NewMemory()
+
+// defSto returns the storage implementation that the conformance tests are
+// executed against. Running the conformance tests without the redis tag uses
+// the in-memory storage.
+func defSto() store.Interface {
+	return mem
+}
@@ -3,30 +3,15 @@
 package conformance
 
 import (
-	"time"
-
 	"github.com/xh3b4sd/redigo"
-	"github.com/xh3b4sd/tracer"
+	"github.com/xh3b4sd/rescue/store"
 )
 
-func musTim(str string) time.Time {
-	tim, err := time.Parse("2006-01-02T15:04:05.999999Z", str)
-	if err != nil {
-		panic(err)
-	}
-
-	return tim
-}
-
-// prgAll is a convenience function for calling FLUSHALL. The provided redigo
-// interface is returned as is.
-func prgAll(red redigo.Interface) redigo.Interface {
-	{
-		err := red.Purge()
-		if err != nil {
-			tracer.Panic(tracer.Mask(err))
-		}
-	}
-
-	return red
+// defSto returns the storage implementation that the conformance tests are
+// executed against. Running the conformance tests with the redis tag uses
+// sorted sets in Redis.
+func defSto() store.Interface {
+	return store.NewRedis(store.RedisConfig{
+		Redigo: redigo.Default(),
+	})
 }
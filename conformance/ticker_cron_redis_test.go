@@ -1,5 +1,3 @@
-//go:build redis
-
 package conformance
 
 import (
@@ -9,9 +7,10 @@ import (
 
 	"github.com/google/go-cmp/cmp"
 	"github.com/xh3b4sd/logger"
-	"github.com/xh3b4sd/redigo"
+	"github.com/xh3b4sd/objectid"
 	"github.com/xh3b4sd/rescue"
 	"github.com/xh3b4sd/rescue/engine"
+	"github.com/xh3b4sd/rescue/store"
 	"github.com/xh3b4sd/rescue/task"
 	"github.com/xh3b4sd/rescue/timer"
 )
@@ -19,13 +18,13 @@ import (
 func Test_Engine_Ticker_Cron_All(t *testing.T) {
 	var err error
 
-	var red redigo.Interface
+	var sto store.Interface
 	{
-		red = redigo.Default()
+		sto = defSto()
 	}
 
 	{
-		err = red.Purge()
+		err = sto.Purge()
 		if err != nil {
 			t.Fatal(err)
 		}
@@ -49,7 +48,7 @@ func Test_Engine_Ticker_Cron_All(t *testing.T) {
 	{
 		eon = engine.New(engine.Config{
 			Logger: logger.Fake(),
-			Redigo: red,
+			Store:  sto,
 			Timer:  tim,
 		})
 	}
@@ -336,16 +335,115 @@ func Test_Engine_Ticker_Cron_All(t *testing.T) {
 	}
 }
 
+// Test_Engine_Ticker_Cron_Linger ensures that scheduled tasks lingering from
+// the previous interval get replaced, even if they change concurrently.
+func Test_Engine_Ticker_Cron_Linger(t *testing.T) {
+	var err error
+
+	var sto *changeDelete
+	{
+		sto = &changeDelete{Interface: prgAll(defSto())}
+	}
+
+	var tim *timer.Timer
+	{
+		tim = timer.New()
+	}
+
+	{
+		tim.Setter(func() time.Time {
+			return musTim("2023-10-20T00:00:00Z")
+		})
+	}
+
+	var eon rescue.Interface
+	{
+		eon = engine.New(engine.Config{
+			Logger: logger.Fake(),
+			Store:  sto,
+			Timer:  tim,
+		})
+	}
+
+	{
+		err = eon.Create(&task.Task{
+			Cron: &task.Cron{
+				task.Aevery: "hour",
+			},
+			Meta: &task.Meta{
+				"test.api.io/key": "foo",
+			},
+			Node: &task.Node{
+				task.Method: task.MthdAll,
+			},
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	for _, x := range []string{"2023-10-20T01:00:01Z", "2023-10-20T02:00:01Z"} {
+		{
+			tim.Setter(func() time.Time {
+				return musTim(x)
+			})
+		}
+
+		// The lingering task changes right before the ticker deletes it.
+		{
+			sto.fun = func(key string, oid objectid.ID) {
+				jsn, err := sto.Search(key, oid)
+				if err != nil {
+					t.Fatal(err)
+				}
+
+				tas := task.FromString(jsn)
+				tas.Sync = &task.Sync{"test.api.io/changed": "true"}
+
+				_, err = sto.Update(key, oid, jsn, task.ToString(tas))
+				if err != nil {
+					t.Fatal(err)
+				}
+			}
+		}
+
+		{
+			err = eon.Ticker()
+			if err != nil {
+				t.Fatal(err)
+			}
+		}
+	}
+
+	{
+		lis, err := eon.Lister(engine.All())
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		var cou int
+		for _, x := range lis {
+			if x.Root != nil {
+				cou++
+			}
+		}
+
+		if cou != 1 {
+			t.Fatal("expected", 1, "got", cou)
+		}
+	}
+}
+
 func Test_Engine_Ticker_Cron_Uni(t *testing.T) {
 	var err error
 
-	var red redigo.Interface
+	var sto store.Interface
 	{
-		red = redigo.Default()
+		sto = defSto()
 	}
 
 	{
-		err = red.Purge()
+		err = sto.Purge()
 		if err != nil {
 			t.Fatal(err)
 		}
@@ -369,7 +467,7 @@ func Test_Engine_Ticker_Cron_Uni(t *testing.T) {
 	{
 		eon = engine.New(engine.Config{
 			Logger: logger.Fake(),
-			Redigo: red,
+			Store:  sto,
 			Timer:  tim,
 		})
 	}
@@ -667,3 +765,20 @@ func Test_Engine_Ticker_Cron_Uni(t *testing.T) {
 		t.Fatal("expected", false, "got", true)
 	}
 }
+
+// changeDelete calls fun once right before deleting any element, so that the
+// element to be deleted can be changed concurrently.
+type changeDelete struct {
+	store.Interface
+	fun func(string, objectid.ID)
+}
+
+func (c *changeDelete) Delete(key string, oid objectid.ID, cur ...string) (bool, error) {
+	if c.fun != nil {
+		fun := c.fun
+		c.fun = nil
+		fun(key, oid)
+	}
+
+	return c.Interface.Delete(key, oid, cur...)
+}
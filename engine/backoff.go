@@ -0,0 +1,99 @@
+package engine
+
+import (
+	"math"
+	"math/rand/v2"
+	"time"
+
+	"github.com/xh3b4sd/rescue/task"
+)
+
+// Backoff describes the optional retry delay of tasks that got expired or
+// released without being completed. Retried tasks are not claimable before
+// their next tick, which is recorded as tick+1 in Task.Cron, the same way it is
+// done for deferred tasks. The retry delay grows exponentially with the amount
+// of cycles that a task went through already.
+//
+//	Base * Factor^(Cycles-1)
+//
+// Backoff is disabled for a zero Base.
+type Backoff struct {
+	// Base is the retry delay of the first cycle.
+	Base time.Duration
+	// Factor is the multiplier applied to the retry delay of every further
+	// cycle. Factor defaults to 2.
+	Factor float64
+	// Jitter is the fraction between 0 and 1 by which any retry delay is
+	// randomly shortened, so that many tasks failing at once are not retried at
+	// once again.
+	Jitter float64
+	// Max is the upper limit of any retry delay. Max defaults to 1 week.
+	Max time.Duration
+}
+
+// Delay returns the retry delay of a task that went through the given amount
+// of cycles.
+func (b Backoff) Delay(cyc int64) time.Duration {
+	if b.Base <= 0 || cyc < 1 {
+		return 0
+	}
+
+	var fac float64
+	{
+		fac = b.Factor
+	}
+
+	if fac < 1 {
+		fac = 2
+	}
+
+	var del float64
+	{
+		del = float64(b.Base) * math.Pow(fac, float64(cyc-1))
+	}
+
+	if b.Max > 0 && del > float64(b.Max) {
+		del = float64(b.Max)
+	}
+
+	// Exponential growth overflows eventually, in which case the longest
+	// possible delay is used.
+	if del >= math.MaxInt64 {
+		return math.MaxInt64
+	}
+
+	if b.Jitter > 0 {
+		del -= del * math.Min(b.Jitter, 1) * rand.Float64()
+	}
+
+	return time.Duration(del)
+}
+
+// backoff defers the next execution of the given task according to the
+// configured backoff policy, based on the task's current cycles.
+func (e *Engine) backoff(tas *task.Task, now time.Time) {
+	var del time.Duration
+	{
+		del = e.bck.Delay(tas.Core.Get().Cycles())
+	}
+
+	if del == 0 {
+		return
+	}
+
+	if tas.Cron == nil {
+		tas.Cron = &task.Cron{}
+	}
+
+	{
+		tas.Cron.Set().TickP1(now.Add(del))
+	}
+}
+
+// retried expresses whether the given task defines Task.Cron only because its
+// next execution got delayed after being expired or released. Such tasks are
+// neither deferred nor scheduled, which is why their Task.Cron is removed once
+// they got claimed again.
+func retried(tas *task.Task) bool {
+	return tas.Cron != nil && tas.Cron.Len() == 1 && tas.Cron.Exi().TickP1()
+}
@@ -0,0 +1,82 @@
+package engine
+
+import (
+	"fmt"
+	"math"
+	"testing"
+	"time"
+)
+
+func Test_Engine_Backoff_Delay(t *testing.T) {
+	testCases := []struct {
+		bck Backoff
+		cyc int64
+		del time.Duration
+	}{
+		// Case 000, ensures that backoff is disabled by default.
+		{
+			bck: Backoff{},
+			cyc: 3,
+			del: 0,
+		},
+		// Case 001
+		{
+			bck: Backoff{Base: time.Second},
+			cyc: 0,
+			del: 0,
+		},
+		// Case 002
+		{
+			bck: Backoff{Base: time.Second},
+			cyc: 1,
+			del: time.Second,
+		},
+		// Case 003, ensures that Factor defaults to 2.
+		{
+			bck: Backoff{Base: time.Second},
+			cyc: 4,
+			del: 8 * time.Second,
+		},
+		// Case 004
+		{
+			bck: Backoff{Base: time.Second, Factor: 3},
+			cyc: 3,
+			del: 9 * time.Second,
+		},
+		// Case 005
+		{
+			bck: Backoff{Base: time.Second, Max: 5 * time.Second},
+			cyc: 4,
+			del: 5 * time.Second,
+		},
+		// Case 006, ensures that exponential growth does not overflow.
+		{
+			bck: Backoff{Base: time.Second},
+			cyc: 1000,
+			del: math.MaxInt64,
+		},
+	}
+
+	for i, tc := range testCases {
+		t.Run(fmt.Sprintf("%03d", i), func(t *testing.T) {
+			del := tc.bck.Delay(tc.cyc)
+			if del != tc.del {
+				t.Fatal("expected", tc.del, "got", del)
+			}
+		})
+	}
+}
+
+func Test_Engine_Backoff_Jitter(t *testing.T) {
+	var bck Backoff
+	{
+		bck = Backoff{Base: time.Minute, Jitter: 0.5}
+	}
+
+	for i := 0; i < 100; i++ {
+		del := bck.Delay(2)
+		if del < time.Minute || del > 2*time.Minute {
+			t.Fatal("expected", "1 to 2 minutes", "got", del)
+		}
+	}
+}
@@ -0,0 +1,44 @@
+package engine
+
+import "github.com/xh3b4sd/rescue/task"
+
+// accepts expresses whether this worker is capable of processing the given
+// task. Workers without any configured capability are capable of processing
+// any task. Otherwise the given task must match any of the configured Task.Meta
+// selectors, the same way Task.Meta.Has matches labels.
+func (e *Engine) accepts(tas *task.Task) bool {
+	return supports(e.cpb, tas)
+}
+
+// capable returns all of the given tasks that this worker is capable of
+// processing.
+func (e *Engine) capable(lis []*task.Task) []*task.Task {
+	if len(e.cpb) == 0 {
+		return lis
+	}
+
+	var fil []*task.Task
+	for _, x := range lis {
+		if e.accepts(x) {
+			fil = append(fil, x)
+		}
+	}
+
+	return fil
+}
+
+// supports expresses whether a worker configured with the given capabilities
+// is capable of processing the given task.
+func supports(cpb []*task.Meta, tas *task.Task) bool {
+	if len(cpb) == 0 {
+		return true
+	}
+
+	for _, x := range cpb {
+		if x != nil && tas.Meta != nil && tas.Meta.Has(*x) {
+			return true
+		}
+	}
+
+	return false
+}
@@ -0,0 +1,67 @@
+package engine
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/xh3b4sd/rescue/task"
+)
+
+func Test_Engine_Capability_Accepts(t *testing.T) {
+	testCases := []struct {
+		cpb []*task.Meta
+		tas *task.Task
+		acc bool
+	}{
+		// Case 000, ensures that workers without capabilities accept any task.
+		{
+			cpb: nil,
+			tas: &task.Task{Meta: &task.Meta{"x.api.io/kind": "foo"}},
+			acc: true,
+		},
+		// Case 001
+		{
+			cpb: []*task.Meta{{"x.api.io/kind": "foo"}},
+			tas: &task.Task{Meta: &task.Meta{"x.api.io/kind": "foo"}},
+			acc: true,
+		},
+		// Case 002
+		{
+			cpb: []*task.Meta{{"x.api.io/kind": "foo"}},
+			tas: &task.Task{Meta: &task.Meta{"x.api.io/kind": "bar"}},
+			acc: false,
+		},
+		// Case 003, ensures that any of many capabilities is sufficient.
+		{
+			cpb: []*task.Meta{{"x.api.io/kind": "foo"}, {"x.api.io/kind": "bar"}},
+			tas: &task.Task{Meta: &task.Meta{"x.api.io/kind": "bar"}},
+			acc: true,
+		},
+		// Case 004, ensures that wildcards can be used.
+		{
+			cpb: []*task.Meta{{"x.api.io/kind": "*"}},
+			tas: &task.Task{Meta: &task.Meta{"x.api.io/kind": "bar"}},
+			acc: true,
+		},
+		// Case 005
+		{
+			cpb: []*task.Meta{{"x.api.io/kind": "foo", "x.api.io/zone": "eu"}},
+			tas: &task.Task{Meta: &task.Meta{"x.api.io/kind": "foo", "x.api.io/zone": "us"}},
+			acc: false,
+		},
+	}
+
+	for i, tc := range testCases {
+		t.Run(fmt.Sprintf("%03d", i), func(t *testing.T) {
+			var e *Engine
+			{
+				e = &Engine{cpb: tc.cpb}
+			}
+
+			acc := e.accepts(tc.tas)
+			if acc != tc.acc {
+				t.Fatal("expected", tc.acc, "got", acc)
+			}
+		})
+	}
+}
@@ -0,0 +1,171 @@
+package engine
+
+import (
+	"time"
+
+	"github.com/xh3b4sd/objectid"
+	"github.com/xh3b4sd/rescue/task"
+	"github.com/xh3b4sd/tracer"
+)
+
+// coalesce lets the given task supersede the oldest runnable task that is not
+// owned by any worker, and that defines the same coalescing selector, the same
+// Task.Node and the same Task.Meta values for all of the selected keys. The
+// superseded task keeps its object ID, but adopts the Task.Sync, the deadline,
+// the lease and the priority of the given task. coalesce returns the object ID
+// of the superseded task, if any. coalesce must only be called while holding
+// the global lock.
+func (e *Engine) coalesce(tas *task.Task) (objectid.ID, error) {
+	var err error
+
+	var sel []string
+	{
+		sel = selector(tas)
+	}
+
+	var met task.Meta
+	{
+		met = task.Meta{}
+	}
+
+	for _, x := range sel {
+		met[x] = tas.Meta.Get(x)
+	}
+
+	var idx bool
+	var lis []*task.Task
+	{
+		lis, idx, err = e.indexed(&task.Task{Meta: &met})
+		if err != nil {
+			return "", tracer.Mask(err)
+		}
+	}
+
+	if !idx {
+		lis, err = e.searchAll()
+		if err != nil {
+			return "", tracer.Mask(err)
+		}
+	}
+
+	// The given task is not completed yet, which is why it may not define the
+	// default delivery method that it is going to be stored with.
+	var nod task.Node
+	{
+		nod = task.Node{task.Method: task.MthdAny}
+	}
+
+	if tas.Node != nil {
+		for k, v := range *tas.Node {
+			nod[k] = v
+		}
+	}
+
+	var now time.Time
+	{
+		now = e.tim.Create()
+	}
+
+	for _, x := range lis {
+		if !supersedes(tas, x, &nod, met, now) {
+			continue
+		}
+
+		// Only tasks that are not owned by any worker can be superseded. The task
+		// might have been claimed or released concurrently, in which case the next
+		// candidate is looked at.
+		fun := func(t *task.Task) bool {
+			if t.Core.Get().Worker() != "" || !supersedes(tas, t, &nod, met, now) {
+				return false
+			}
+
+			{
+				t.Sync = tas.Sync
+			}
+
+			// The superseded task is scheduled the way the given task asks for, so
+			// that the latest request is not dropped once an older deadline passed.
+			{
+				adopt(t.Core, tas.Core)
+			}
+
+			return true
+		}
+
+		var upd bool
+		{
+			k := e.Keyfmt()
+			o := x.Core.Get().Object()
+
+			upd, err = e.modify(k, o, fun)
+			if err != nil {
+				return "", tracer.Mask(err)
+			}
+		}
+
+		if upd {
+			return x.Core.Get().Object(), nil
+		}
+	}
+
+	return "", nil
+}
+
+// supersedes expresses whether the given task tas may supersede the given
+// candidate task can, given the Task.Node and the Task.Meta values that tas
+// selects. Task templates, halted tasks, tasks deferred or retried in the
+// future and tasks that missed their deadline are not runnable, and so they
+// must not absorb any newer task. Tasks addressed to different workers, tasks
+// defining another circuit breaker and tasks waiting for other triggers are
+// not interchangeable either.
+func supersedes(tas *task.Task, can *task.Task, nod *task.Node, met task.Meta, now time.Time) bool {
+	if isTpl(can) || halted(can) || missed(can, now) {
+		return false
+	}
+
+	if can.Cron != nil && can.Cron.Exi().TickP1() && can.Cron.Get().TickP1().After(now) {
+		return false
+	}
+
+	if can.Core.Get().Coalesce() != tas.Core.Get().Coalesce() || !can.Node.Eql(nod) {
+		return false
+	}
+
+	if can.Core.Get().Cancel() != tas.Core.Get().Cancel() {
+		return false
+	}
+
+	if (can.Gate.Len() != 0 || tas.Gate.Len() != 0) && !can.Gate.Eql(tas.Gate) {
+		return false
+	}
+
+	return can.Meta.Has(met)
+}
+
+// adopt overwrites the deadline, the lease and the priority of the stored
+// task cur with the ones of the given task tas, including their absence.
+func adopt(cur *task.Core, tas *task.Core) {
+	if tas.Exi().Deadline() {
+		cur.Set().Deadline(tas.Get().Deadline())
+	} else {
+		cur.Prg().Deadline()
+	}
+
+	if tas.Exi().Lease() {
+		cur.Set().Lease(tas.Get().Lease())
+	} else {
+		cur.Prg().Lease()
+	}
+
+	if tas.Exi().Priority() {
+		cur.Set().Priority(tas.Get().Priority())
+	} else {
+		cur.Prg().Priority()
+	}
+}
+
+// selector returns the Task.Meta keys of the coalescing selector that the
+// given task defines in Task.Core.
+func selector(tas *task.Task) []string {
+	return split(tas.Core.Get().Coalesce())
+}
@@ -0,0 +1,141 @@
+package engine
+
+import (
+	"fmt"
+
+	"github.com/xh3b4sd/objectid"
+	"github.com/xh3b4sd/rescue/task"
+	"github.com/xh3b4sd/tracer"
+)
+
+func (e *Engine) Completed(tas *task.Task) ([]string, error) {
+	var err error
+	var wrk []string
+
+	e.met.Engine.Completed.Cal.Inc()
+
+	o := func() error {
+		wrk, err = e.completed(tas)
+		if err != nil {
+			return tracer.Mask(err)
+		}
+
+		return nil
+	}
+
+	err = e.met.Engine.Completed.Dur.Sin(o)
+	if err != nil {
+		e.met.Engine.Completed.Err.Inc()
+		return nil, tracer.Mask(err)
+	}
+
+	return wrk, nil
+}
+
+func (e *Engine) completed(tas *task.Task) ([]string, error) {
+	var err error
+
+	{
+		if tas == nil {
+			return nil, tracer.Maskf(taskEmptyError, "Task must not be empty")
+		}
+		if tas.Core.Emp() {
+			return nil, tracer.Maskf(taskCoreError, "Task.Core must not be empty")
+		}
+		if !tas.Core.Exi().Object() {
+			return nil, tracer.Maskf(taskCoreError, "Task.Core does not define %s", task.Object)
+		}
+	}
+
+	var jsn string
+	{
+		_, jsn, err = e.lookup(tas.Core.Get().Object())
+		if err != nil {
+			return nil, tracer.Mask(err)
+		}
+	}
+
+	if jsn == "" {
+		e.met.Task.NotFound.Inc()
+		return nil, tracer.Maskf(taskNotFoundError, "%s", tas.Core.Map().Object())
+	}
+
+	var cur *task.Task
+	{
+		cur = task.FromString(jsn)
+	}
+
+	// Multicasted tasks record their completions within the stored task, because
+	// their last target worker deletes them once every target worker completed
+	// them.
+	if cur.Node.Get(task.Method) == task.MthdMny {
+		return acknowledged(cur), nil
+	}
+
+	var wrk []string
+	{
+		wrk, err = e.sto.Member(e.ackKey(cur.Core.Get().Object()))
+		if err != nil {
+			return nil, tracer.Mask(err)
+		}
+	}
+
+	return wrk, nil
+}
+
+// acknowledge records that this worker completed the given broadcasted task.
+// Completions are kept within a set of their own, so that any amount of
+// workers can complete the same task concurrently without ever modifying the
+// stored task.
+func (e *Engine) acknowledge(tas *task.Task) error {
+	err := e.sto.Attach(e.ackKey(tas.Core.Get().Object()), e.wrk)
+	if err != nil {
+		return tracer.Mask(err)
+	}
+
+	return nil
+}
+
+// completes expresses whether this worker completed the given broadcasted
+// task already.
+func (e *Engine) completes(tas *task.Task) (bool, error) {
+	wrk, err := e.sto.Member(e.ackKey(tas.Core.Get().Object()))
+	if err != nil {
+		return false, tracer.Mask(err)
+	}
+
+	return contains(wrk, e.wrk), nil
+}
+
+// unacknowledge removes the recorded completions of the given broadcasted
+// task, once the task itself got removed from the queue.
+func (e *Engine) unacknowledge(tas *task.Task) error {
+	var err error
+
+	if tas.Node.Get(task.Method) != task.MthdAll {
+		return nil
+	}
+
+	var wrk []string
+	{
+		wrk, err = e.sto.Member(e.ackKey(tas.Core.Get().Object()))
+		if err != nil {
+			return tracer.Mask(err)
+		}
+	}
+
+	{
+		err = e.sto.Detach(e.ackKey(tas.Core.Get().Object()), wrk...)
+		if err != nil {
+			return tracer.Mask(err)
+		}
+	}
+
+	return nil
+}
+
+// ackKey returns the key of the set containing the workers that completed the
+// given broadcasted task.
+func (e *Engine) ackKey(oid objectid.ID) string {
+	return fmt.Sprintf("%s%scomplete%s%s", e.Keyfmt(), e.sep, e.sep, oid)
+}
@@ -0,0 +1,162 @@
+package engine
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/xh3b4sd/objectid"
+	"github.com/xh3b4sd/rescue/store"
+	"github.com/xh3b4sd/rescue/task"
+	"github.com/xh3b4sd/tracer"
+)
+
+// Concurrency describes a queue level rule limiting the amount of tasks that
+// may be owned by workers at once across the whole network. The rule applies
+// to all tasks matching the given Task.Meta labels, e.g. all tasks of a
+// particular customer, in order to protect downstream systems. Every worker of
+// the same queue must be configured with the same rules. Note that tasks
+// defining the delivery method "all" or "mny" are not subject to any rule.
+type Concurrency struct {
+	// Limit is the maximum amount of matching tasks owned by workers at once.
+	Limit int
+	// Meta is the label selector of the tasks that the rule applies to.
+	Meta *task.Meta
+}
+
+// label returns the label selector of this rule in a stable format, so that
+// the rule can be identified within metrics.
+func (c Concurrency) label() string {
+	var lab []string
+
+	if c.Meta != nil {
+		for k, v := range *c.Meta {
+			lab = append(lab, fmt.Sprintf("%s=%s", k, v))
+		}
+	}
+
+	sort.Strings(lab)
+
+	return strings.Join(lab, ",")
+}
+
+// inflight returns the amount of owned tasks matching each of the configured
+// concurrency rules, given a list of all runnable tasks.
+func (e *Engine) inflight(lis []*task.Task) []int {
+	inf := make([]int, len(e.con))
+
+	for _, x := range lis {
+		if x.Core.Get().Worker() == "" {
+			continue
+		}
+
+		for i, y := range e.con {
+			if limits(y.Meta, x) {
+				inf[i]++
+			}
+		}
+	}
+
+	return inf
+}
+
+// admit expresses whether the given task can be claimed without exceeding any
+// of the configured concurrency rules. Admitted tasks are accounted for in the
+// given in-flight counts right away, so that claiming many tasks at once
+// respects all rules as well.
+func (e *Engine) admit(tas *task.Task, inf []int) bool {
+	for i, x := range e.con {
+		if limits(x.Meta, tas) && inf[i] >= x.Limit {
+			return false
+		}
+	}
+
+	for i, x := range e.con {
+		if limits(x.Meta, tas) {
+			inf[i]++
+		}
+	}
+
+	return true
+}
+
+// limited expresses whether any of the configured concurrency rules applies to
+// the given task.
+func (e *Engine) limited(tas *task.Task) bool {
+	for _, x := range e.con {
+		if limits(x.Meta, tas) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// saturate records the ratio of the given in-flight counts to the limits of
+// their respective concurrency rules.
+func (e *Engine) saturate(inf []int) {
+	for i, x := range e.con {
+		if x.Limit > 0 {
+			e.met.Task.Saturation.Set(x.label(), float64(inf[i])/float64(x.Limit))
+		}
+	}
+}
+
+// version returns the current version of all claims subject to concurrency
+// rules. Every claim of any limited task increments this version within the
+// same atomic write operation that assigns task ownership. Workers read the
+// version before reading the task queue, so that a claim based on outdated
+// in-flight counts fails, and is retried based on the most recent state of the
+// queue.
+func (e *Engine) version() (store.Element, error) {
+	var err error
+
+	var oid objectid.ID
+	{
+		oid = objectid.System()
+	}
+
+	var jsn string
+	{
+		jsn, err = e.sto.Search(e.conKey(), oid)
+		if err != nil {
+			return store.Element{}, tracer.Mask(err)
+		}
+	}
+
+	// The very first claim of any limited task within this queue creates the
+	// version. Workers doing so concurrently agree on the same version.
+	if jsn == "" {
+		jsn = "0"
+
+		err = e.sto.Create(e.conKey(), oid, jsn)
+		if store.IsElementExists(err) {
+			return e.version()
+		} else if err != nil {
+			return store.Element{}, tracer.Mask(err)
+		}
+	}
+
+	var ver int64
+	{
+		ver, err = strconv.ParseInt(jsn, 10, 64)
+		if err != nil {
+			return store.Element{}, tracer.Mask(err)
+		}
+	}
+
+	return store.Element{Cur: jsn, Key: e.conKey(), Oid: oid, Val: strconv.FormatInt(ver+1, 10)}, nil
+}
+
+// conKey returns the key of the sorted set containing the version of all
+// claims subject to concurrency rules.
+func (e *Engine) conKey() string {
+	return fmt.Sprintf("%s%sconcurrency", e.Keyfmt(), e.sep)
+}
+
+// limits expresses whether the rule defining the given label selector applies
+// to the given task.
+func limits(met *task.Meta, tas *task.Task) bool {
+	return met != nil && tas.Meta != nil && tas.Meta.Has(*met)
+}
@@ -1,10 +1,13 @@
 package engine
 
 import (
+	"errors"
+	"fmt"
 	"strconv"
 	"time"
 
 	"github.com/xh3b4sd/objectid"
+	"github.com/xh3b4sd/rescue/store"
 	"github.com/xh3b4sd/rescue/task"
 	"github.com/xh3b4sd/rescue/ticker"
 	"github.com/xh3b4sd/tracer"
@@ -44,12 +47,20 @@ func (e *Engine) create(tas *task.Task) error {
 		}
 	}
 
-	// Creating tasks implies certain write operations on the task queue such as
-	// adding a new task to a sorted set in redis. Due to such write operations
-	// we need to ensure that only one process at a time can write information
-	// back to the queue. Otherwise worker behaviour would be inconsistent and
-	// the integrity of the queue could not be guaranteed.
+	// Creating tasks implies a single write operation on the task queue, adding
+	// a new task to the underlying sorted set. Since new tasks are identified by
+	// their unique object IDs, we do not need to acquire the global lock here.
+	// Tasks defining a dedupe key or a coalescing selector are the exception,
+	// because looking up the tasks they may duplicate or supersede must not
+	// happen concurrently.
+	var coa bool
+	var ded bool
 	{
+		coa = tas.Core != nil && tas.Core.Exi().Coalesce()
+		ded = tas.Core != nil && tas.Core.Exi().Dedupe()
+	}
+
+	if coa || ded {
 		err := e.loc.Acquire()
 		if err != nil {
 			return tracer.Mask(err)
@@ -63,11 +74,236 @@ func (e *Engine) create(tas *task.Task) error {
 		}()
 	}
 
-	var oid objectid.ID
-	{
-		oid = objectid.Random(objectid.Time(e.tim.Create()))
+	if ded {
+		var dup objectid.ID
+		{
+			dup, err = e.dedupe(tas, e.tim.Create())
+			if err != nil {
+				return tracer.Mask(err)
+			}
+		}
+
+		// The given task duplicates an existing or recently completed task, and
+		// so we only inform the caller about the object ID of the original task.
+		if dup != "" {
+			{
+				tas.Core.Set().Object(dup)
+			}
+
+			{
+				e.met.Task.Dedupe.Inc()
+			}
+
+			return nil
+		}
+	}
+
+	if coa {
+		var sup objectid.ID
+		{
+			sup, err = e.coalesce(tas)
+			if err != nil {
+				return tracer.Mask(err)
+			}
+		}
+
+		// The given task superseded an older task, which adopted the given task's
+		// Task.Sync already, and so we only inform the caller about the object ID
+		// of the older task.
+		if sup != "" {
+			{
+				tas.Core.Set().Object(sup)
+			}
+
+			{
+				e.met.Task.Coalesce.Inc()
+			}
+
+			return nil
+		}
 	}
 
+	// The task is prepared using a copy of the given task, so that the given
+	// task is only modified once it got created. Should the object ID be taken
+	// already, the task is retried using a new object ID, the same way batches
+	// are.
+	for i := 0; i < Retry; i++ {
+		var oid objectid.ID
+		{
+			oid = objectid.Random(objectid.Time(e.tim.Create()))
+		}
+
+		var cpy *task.Task
+		{
+			cpy = task.FromString(task.ToString(tas))
+		}
+
+		var key string
+		{
+			key = e.complete(cpy, tic, oid)
+		}
+
+		// The task is indexed before it gets created, so that created tasks are
+		// never missing from the label indexes. Tasks that did not get created
+		// are removed from the label indexes again.
+		{
+			err = e.index(cpy)
+			if err != nil {
+				e.deindexMany([]*task.Task{cpy})
+				return tracer.Mask(err)
+			}
+		}
+
+		if ded {
+			err = e.record(cpy)
+			if err != nil {
+				e.deindexMany([]*task.Task{cpy})
+			}
+
+			if store.IsElementExists(err) {
+				continue
+			} else if err != nil {
+				return tracer.Mask(err)
+			}
+		}
+
+		{
+			err = e.sto.Create(key, oid, task.ToString(cpy))
+			if err != nil {
+				e.deindexMany([]*task.Task{cpy})
+			}
+
+			if err != nil && ded {
+				err := e.discard(cpy)
+				if err != nil {
+					e.lerror(tracer.Mask(err))
+				}
+			}
+
+			if store.IsElementExists(err) {
+				continue
+			} else if err != nil {
+				return tracer.Mask(err)
+			}
+		}
+
+		{
+			*tas = *cpy
+		}
+
+		// Task templates are never claimed by workers, which is why only runnable
+		// tasks cause workers to be woken up.
+		if key == e.Keyfmt() {
+			e.notify()
+		}
+
+		return nil
+	}
+
+	return tracer.Mask(err)
+}
+
+func (e *Engine) CreateMany(tas []*task.Task) error {
+	var err error
+
+	e.met.Engine.CreateMany.Cal.Inc()
+
+	o := func() error {
+		err = e.createMany(tas)
+		if err != nil {
+			return tracer.Mask(err)
+		}
+
+		return nil
+	}
+
+	err = e.met.Engine.CreateMany.Dur.Sin(o)
+	if err != nil {
+		e.met.Engine.CreateMany.Err.Inc()
+		return tracer.Mask(err)
+	}
+
+	return nil
+}
+
+func (e *Engine) createMany(tas []*task.Task) error {
+	var err error
+
+	// All tasks are validated before any of them gets written, so that invalid
+	// tasks are reported all at once using the index of the respective task
+	// within the given list.
+	var bat []error
+	var tic []*ticker.Ticker
+	for i, x := range tas {
+		t, err := e.verCre(x)
+		if err != nil {
+			bat = append(bat, fmt.Errorf("Task[%d]: %w", i, err))
+		} else if x.Core != nil && x.Core.Exi().Coalesce() {
+			bat = append(bat, fmt.Errorf("Task[%d]: %w", i, tracer.Maskf(taskCoreError, "Task.Core must not define %s for batches", task.Coalesce)))
+		} else if x.Core != nil && x.Core.Exi().Dedupe() {
+			bat = append(bat, fmt.Errorf("Task[%d]: %w", i, tracer.Maskf(taskCoreError, "Task.Core must not define %s for batches", task.Dedupe)))
+		}
+
+		tic = append(tic, t)
+	}
+
+	if len(bat) != 0 {
+		return tracer.Mask(errors.Join(append([]error{tracer.Maskf(taskBatchError, "%d of %d tasks are invalid", len(bat), len(tas))}, bat...)...))
+	}
+
+	if len(tas) == 0 {
+		return nil
+	}
+
+	// Every task of the batch requires its own unique object ID, while all of
+	// them are created within the same point in time. Should any of the object
+	// IDs be taken already, the whole batch is retried using new object IDs.
+	// The batch is prepared using copies of the given tasks, so that the given
+	// tasks are only modified once the batch got created.
+	for i := 0; i < Retry; i++ {
+		var cpy []*task.Task
+		var ele []store.Element
+		{
+			cpy, ele = e.element(tas, tic)
+		}
+
+		// Every task is indexed before the batch gets created, so that created
+		// tasks are never missing from the label indexes. Tasks that did not get
+		// created are removed from the label indexes again.
+		{
+			err = e.indexMany(cpy)
+			if err != nil {
+				return tracer.Mask(err)
+			}
+		}
+
+		err = e.sto.Batch(ele)
+		if err != nil {
+			e.deindexMany(cpy)
+		}
+
+		if store.IsElementExists(err) {
+			continue
+		} else if err != nil {
+			return tracer.Mask(err)
+		}
+
+		for j, x := range cpy {
+			*tas[j] = *x
+		}
+
+		e.notify()
+
+		return nil
+	}
+
+	return tracer.Mask(err)
+}
+
+// complete assigns the given object ID to the given task, and fills in all the
+// defaults that tasks are stored with. complete returns the key of the sorted
+// set that the task has to be created in.
+func (e *Engine) complete(tas *task.Task, tic *ticker.Ticker, oid objectid.ID) string {
 	if tas.Core == nil {
 		tas.Core = &task.Core{}
 	}
@@ -89,18 +325,56 @@ func (e *Engine) create(tas *task.Task) error {
 		tas.Node.Set(task.Method, task.MthdAny)
 	}
 
+	// Task templates are stored separately from runnable tasks, so that workers
+	// searching for tasks do not have to skip them.
+	if isTpl(tas) {
+		return e.tplKey()
+	}
+
+	return e.Keyfmt()
+}
+
+// element prepares copies of the given tasks for being created as a single
+// batch. Every copy is assigned a new object ID that is unique within the
+// batch. The given tasks are not modified.
+func (e *Engine) element(tas []*task.Task, tic []*ticker.Ticker) ([]*task.Task, []store.Element) {
+	var now time.Time
 	{
-		k := e.Keyfmt()
-		v := task.ToString(tas)
-		s := oid.Float()
+		now = e.tim.Create()
+	}
 
-		err = e.red.Sorted().Create().Score(k, v, s)
-		if err != nil {
-			return tracer.Mask(err)
+	var cpy []*task.Task
+	var ele []store.Element
+
+	see := map[objectid.ID]bool{}
+	for i, x := range tas {
+		var oid objectid.ID
+		for {
+			oid = objectid.Random(objectid.Time(now))
+			if !see[oid] {
+				break
+			}
+		}
+
+		{
+			see[oid] = true
 		}
+
+		var c *task.Task
+		{
+			c = task.FromString(task.ToString(x))
+		}
+
+		var key string
+		{
+			key = e.complete(c, tic[i], oid)
+		}
+
+		cpy = append(cpy, c)
+		ele = append(ele, store.Element{Key: key, Oid: oid, Val: task.ToString(c)})
 	}
 
-	return nil
+	return cpy, ele
 }
 
 func (e *Engine) verCre(tas *task.Task) (*ticker.Ticker, error) {
@@ -108,17 +382,55 @@ func (e *Engine) verCre(tas *task.Task) (*ticker.Ticker, error) {
 		if tas == nil {
 			return nil, tracer.Maskf(taskEmptyError, "Task must not be empty")
 		}
-		if tas.Core != nil && !(tas.Core.Len() == 1 && tas.Core.Has(Can())) {
-			return nil, tracer.Maskf(taskCoreError, "Task.Core must be empty")
+		if tas.Core != nil && (tas.Core.Emp() || tas.Core.Len() != tas.Core.Any(task.Cancel, task.Coalesce, task.Deadline, task.Dedupe, task.Lease, task.Priority).Len()) {
+			return nil, tracer.Maskf(taskCoreError, "Task.Core must only contain reserved keys [%s %s %s %s %s %s]", task.Cancel, task.Coalesce, task.Deadline, task.Dedupe, task.Lease, task.Priority)
 		}
-		if tas.Core != nil && !(tas.Core.Has(Can()) && natNum(tas.Core.Map().Cancel())) {
+		if tas.Core != nil && tas.Core.Exi().Cancel() && !natNum(tas.Core.Map().Cancel()) {
 			return nil, tracer.Maskf(taskCoreError, "Task.Core does not define a positive number for %s", task.Cancel)
 		}
+		if tas.Core != nil && tas.Core.Exi().Lease() && ticker.New(tas.Core.Get().Lease()).Duration() == 0 {
+			return nil, tracer.Maskf(taskCoreError, "Task.Core format must be valid, got %s = %q", task.Lease, tas.Core.Get().Lease())
+		}
+		if tas.Core != nil && tas.Core.Exi().Priority() && !intNum(tas.Core.Map().Priority()) {
+			return nil, tracer.Maskf(taskCoreError, "Task.Core does not define a number for %s", task.Priority)
+		}
 		if tas.Meta == nil || tas.Meta.Emp() {
 			return nil, tracer.Maskf(taskMetaEmptyError, "Task.Meta must not be empty")
 		}
 	}
 
+	if tas.Core != nil && tas.Core.Exi().Coalesce() {
+		if tas.Core.Exi().Dedupe() {
+			return nil, tracer.Maskf(taskCoreError, "Task.Core must not define %s and %s together", task.Coalesce, task.Dedupe)
+		}
+
+		if isTpl(tas) {
+			return nil, tracer.Maskf(taskCoreError, "Task.Core must not define %s for task templates", task.Coalesce)
+		}
+
+		if tas.Node != nil && tas.Node.Get(task.Method) == task.MthdAll {
+			return nil, tracer.Maskf(taskCoreError, `Task.Core must not define %s if delivery method "all" is configured`, task.Coalesce)
+		}
+		if tas.Node != nil && tas.Node.Get(task.Method) == task.MthdMny {
+			return nil, tracer.Maskf(taskCoreError, `Task.Core must not define %s if delivery method "mny" is configured`, task.Coalesce)
+		}
+
+		var sel []string
+		{
+			sel = selector(tas)
+		}
+
+		if len(sel) == 0 {
+			return nil, tracer.Maskf(taskCoreError, "Task.Core must define Task.Meta keys for %s", task.Coalesce)
+		}
+
+		for _, x := range sel {
+			if !tas.Meta.Exi(x) {
+				return nil, tracer.Maskf(taskCoreError, "Task.Meta must define %s selected by %s", x, task.Coalesce)
+			}
+		}
+	}
+
 	if tas.Node != nil {
 		if !tas.Node.Has(Met()) {
 			return nil, tracer.Maskf(taskHostError, "Task.Node must contain reserved key [%s]", task.Method)
@@ -137,6 +449,19 @@ func (e *Engine) verCre(tas *task.Task) (*ticker.Ticker, error) {
 			return nil, tracer.Maskf(taskHostError, `Task.Node must not contain any more labels if delivery method "any" is configured`)
 		}
 
+		if met == task.MthdMny && !tas.Node.Exi(task.Worker) {
+			return nil, tracer.Maskf(taskHostError, `Task.Node must only contain reserved keys [%s %s] if delivery method "mny" is configured`, task.Method, task.Worker)
+		}
+		if met == task.MthdMny && tas.Node.Len() != 2 {
+			return nil, tracer.Maskf(taskHostError, `Task.Node must only contain reserved keys [%s %s] if delivery method "mny" is configured`, task.Method, task.Worker)
+		}
+		if met == task.MthdMny && len(targets(tas)) == 0 {
+			return nil, tracer.Maskf(taskHostError, `Task.Node must define workers for %s if delivery method "mny" is configured`, task.Worker)
+		}
+		if met == task.MthdMny && (tas.Cron != nil || tas.Gate != nil || tas.Root != nil) {
+			return nil, tracer.Maskf(taskHostError, `Task.Cron, Task.Gate and Task.Root must not be configured if delivery method "mny" is configured`)
+		}
+
 		if met == task.MthdUni && !tas.Node.Exi(task.Worker) {
 			return nil, tracer.Maskf(taskHostError, `Task.Node must only contain reserved keys [%s %s] if delivery method "uni" is configured`, task.Method, task.Worker)
 		}
@@ -145,8 +470,8 @@ func (e *Engine) verCre(tas *task.Task) (*ticker.Ticker, error) {
 		}
 
 		for k, v := range *tas.Node {
-			if k == task.Method && v != task.MthdAll && v != task.MthdAny && v != task.MthdUni {
-				return nil, tracer.Maskf(labelValueError, "Task.Node must only contain one of the reserved values [%s %s %s]", task.MthdAll, task.MthdAny, task.MthdUni)
+			if k == task.Method && v != task.MthdAll && v != task.MthdAny && v != task.MthdMny && v != task.MthdUni {
+				return nil, tracer.Maskf(labelValueError, "Task.Node must only contain one of the reserved values [%s %s %s %s]", task.MthdAll, task.MthdAny, task.MthdMny, task.MthdUni)
 			}
 		}
 	}
@@ -201,6 +526,17 @@ func (e *Engine) verCre(tas *task.Task) (*ticker.Ticker, error) {
 		}
 	}
 
+	if tas.Core != nil && tas.Core.Exi().Deadline() {
+		tim, err := time.Parse(ticker.Layout, tas.Core.Map().Deadline())
+		if err != nil {
+			return nil, tracer.Maskf(taskCoreError, "Task.Core format must be valid, got %s = %q", task.Deadline, tas.Core.Map().Deadline())
+		}
+
+		if !tim.After(now) {
+			return nil, tracer.Maskf(taskCoreError, "Task.Core %s must be in the future", task.Deadline)
+		}
+	}
+
 	if tas.Gate != nil {
 		if tas.Gate.Has(Del()) {
 			return nil, tracer.Maskf(labelReservedError, "Task.Gate must not contain reserved value [deleted]")
@@ -247,6 +583,11 @@ func (e *Engine) verCre(tas *task.Task) (*ticker.Ticker, error) {
 	return tic, nil
 }
 
+func intNum(s string) bool {
+	_, err := strconv.ParseInt(s, 10, 64)
+	return err == nil
+}
+
 func natNum(s string) bool {
 	num, err := strconv.Atoi(s)
 	return err == nil && num > 0
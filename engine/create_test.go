@@ -141,6 +141,119 @@ func Test_Engine_Create_Core_Error(t *testing.T) {
 				},
 			},
 		},
+		// Case 011
+		{
+			tas: &task.Task{
+				Core: &task.Core{
+					task.Priority: "baz",
+				},
+				Meta: &task.Meta{
+					"foo": "bar",
+				},
+			},
+		},
+		// Case 012
+		{
+			tas: &task.Task{
+				Core: &task.Core{
+					task.Object:   "bar",
+					task.Priority: "3",
+				},
+				Meta: &task.Meta{
+					"foo": "bar",
+				},
+			},
+		},
+		// Case 013
+		{
+			tas: &task.Task{
+				Core: &task.Core{},
+				Meta: &task.Meta{
+					"foo": "bar",
+				},
+			},
+		},
+		// Case 014
+		{
+			tas: &task.Task{
+				Core: &task.Core{
+					task.Lease: "5 seconds",
+				},
+				Meta: &task.Meta{
+					"foo": "bar",
+				},
+			},
+		},
+		// Case 015
+		{
+			tas: &task.Task{
+				Core: &task.Core{
+					task.Deadline: "tomorrow",
+				},
+				Meta: &task.Meta{
+					"foo": "bar",
+				},
+			},
+		},
+		// Case 016
+		{
+			tas: &task.Task{
+				Core: &task.Core{
+					task.Deadline: "2023-10-20T00:00:00Z",
+				},
+				Meta: &task.Meta{
+					"foo": "bar",
+				},
+			},
+		},
+		// Case 017
+		{
+			tas: &task.Task{
+				Core: &task.Core{
+					task.Coalesce: "baz",
+				},
+				Meta: &task.Meta{
+					"foo": "bar",
+				},
+			},
+		},
+		// Case 018
+		{
+			tas: &task.Task{
+				Core: &task.Core{
+					task.Coalesce: " , ",
+				},
+				Meta: &task.Meta{
+					"foo": "bar",
+				},
+			},
+		},
+		// Case 019
+		{
+			tas: &task.Task{
+				Core: &task.Core{
+					task.Coalesce: "foo",
+					task.Dedupe:   "foo",
+				},
+				Meta: &task.Meta{
+					"foo": "bar",
+				},
+			},
+		},
+		// Case 020
+		{
+			tas: &task.Task{
+				Core: &task.Core{
+					task.Coalesce: "foo",
+				},
+				Meta: &task.Meta{
+					"foo": "bar",
+				},
+				Node: &task.Node{
+					task.Method: task.MthdAll,
+				},
+			},
+		},
 	}
 
 	for i, tc := range testCases {
@@ -209,6 +322,74 @@ func Test_Engine_Create_Core_No_Error(t *testing.T) {
 				},
 			},
 		},
+		// Case 004
+		{
+			tas: &task.Task{
+				Core: &task.Core{
+					task.Priority: "5",
+				},
+				Meta: &task.Meta{
+					"foo": "bar",
+				},
+			},
+		},
+		// Case 005
+		{
+			tas: &task.Task{
+				Core: &task.Core{
+					task.Cancel:   "3",
+					task.Priority: "-2",
+				},
+				Meta: &task.Meta{
+					"foo": "bar",
+				},
+			},
+		},
+		// Case 006
+		{
+			tas: &task.Task{
+				Core: &task.Core{
+					task.Lease: "5 minutes",
+				},
+				Meta: &task.Meta{
+					"foo": "bar",
+				},
+			},
+		},
+		// Case 007
+		{
+			tas: &task.Task{
+				Core: &task.Core{
+					task.Deadline: time.Now().UTC().Add(time.Hour).Format(ticker.Layout),
+				},
+				Meta: &task.Meta{
+					"foo": "bar",
+				},
+			},
+		},
+		// Case 008
+		{
+			tas: &task.Task{
+				Core: &task.Core{
+					task.Dedupe: "foo",
+				},
+				Meta: &task.Meta{
+					"foo": "bar",
+				},
+			},
+		},
+		// Case 009
+		{
+			tas: &task.Task{
+				Core: &task.Core{
+					task.Coalesce: "foo, baz",
+				},
+				Meta: &task.Meta{
+					"baz": "zap",
+					"foo": "bar",
+				},
+			},
+		},
 	}
 
 	for i, tc := range testCases {
@@ -237,7 +418,7 @@ func Test_Engine_Create_Cron_Error(t *testing.T) {
 		{
 			tas: &task.Task{
 				Cron: &task.Cron{
-					task.Aevery: "hour",
+					task.Aevery: "6 hours",
 					task.Aexact: time.Now().UTC().Add(5 * time.Minute).Format(ticker.Layout),
 				},
 				Meta: &task.Meta{
@@ -295,7 +476,7 @@ func Test_Engine_Create_Cron_No_Error(t *testing.T) {
 		{
 			tas: &task.Task{
 				Cron: &task.Cron{
-					task.Aevery: "hour",
+					task.Aevery: "6 hours",
 				},
 				Meta: &task.Meta{
 					"foo": "bar",
@@ -431,6 +612,44 @@ func Test_Engine_Create_Host_Error(t *testing.T) {
 				},
 			},
 		},
+		// Case 008
+		{
+			tas: &task.Task{
+				Meta: &task.Meta{
+					"foo": "bar",
+				},
+				Node: &task.Node{
+					task.Method: task.MthdMny,
+				},
+			},
+		},
+		// Case 009
+		{
+			tas: &task.Task{
+				Meta: &task.Meta{
+					"foo": "bar",
+				},
+				Node: &task.Node{
+					task.Method: task.MthdMny,
+					task.Worker: " , ",
+				},
+			},
+		},
+		// Case 010
+		{
+			tas: &task.Task{
+				Cron: &task.Cron{
+					task.Aevery: "6 hours",
+				},
+				Meta: &task.Meta{
+					"foo": "bar",
+				},
+				Node: &task.Node{
+					task.Method: task.MthdMny,
+					task.Worker: "foo,bar",
+				},
+			},
+		},
 	}
 
 	for i, tc := range testCases {
@@ -489,6 +708,18 @@ func Test_Engine_Create_Host_No_Error(t *testing.T) {
 				},
 			},
 		},
+		// Case 003
+		{
+			tas: &task.Task{
+				Meta: &task.Meta{
+					"foo": "bar",
+				},
+				Node: &task.Node{
+					task.Method: task.MthdMny,
+					task.Worker: "foo,bar",
+				},
+			},
+		},
 	}
 
 	for i, tc := range testCases {
@@ -695,7 +926,7 @@ func Test_Engine_Create_Gate_Error(t *testing.T) {
 		{
 			tas: &task.Task{
 				Cron: &task.Cron{
-					task.Aevery: "hour",
+					task.Aevery: "6 hours",
 				},
 				Meta: &task.Meta{
 					"foo": "bar",
@@ -746,7 +977,7 @@ func Test_Engine_Create_Gate_No_Error(t *testing.T) {
 		{
 			tas: &task.Task{
 				Cron: &task.Cron{
-					task.Aevery: "hour",
+					task.Aevery: "6 hours",
 				},
 				Meta: &task.Meta{
 					"foo": "bar",
@@ -868,7 +1099,7 @@ func Test_Engine_Create_Sync_Error(t *testing.T) {
 				},
 				Sync: &task.Sync{
 					task.Paging: "foo",
-					task.Aevery: "hour",
+					task.Aevery: "6 hours",
 				},
 			},
 		},
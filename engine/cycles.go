@@ -45,60 +45,41 @@ func (e *Engine) cycles(tas *task.Task) error {
 		}
 	}
 
-	// Creating tasks implies certain write operations on the task queue such as
-	// adding a new task to a sorted set in redis. Due to such write operations
-	// we need to ensure that only one process at a time can write information
-	// back to the queue. Otherwise worker behaviour would be inconsistent and
-	// the integrity of the queue could not be guaranteed.
+	// Resetting the cycles count of a task implies certain write operations on
+	// the task queue. The task is modified based on its most recent state, so
+	// that concurrent modifications by other workers do not get lost.
+	fun := func(t *task.Task) bool {
+		t.Core.Prg().Expiry()
+		t.Core.Prg().Worker()
+		t.Core.Prg().Cycles()
+
+		return true
+	}
+
+	var key string
 	{
-		err := e.loc.Acquire()
+		key, _, err = e.lookup(tas.Core.Get().Object())
 		if err != nil {
 			return tracer.Mask(err)
 		}
-
-		defer func() {
-			err := e.loc.Release()
-			if err != nil {
-				e.lerror(tracer.Mask(err))
-			}
-		}()
 	}
 
-	var jsn []string
-	{
-		k := e.Keyfmt()
-		s := tas.Core.Get().Object().Float()
+	var upd bool
+	if key != "" {
+		o := tas.Core.Get().Object()
 
-		jsn, err = e.red.Sorted().Search().Score(k, s, s)
+		upd, err = e.modify(key, o, fun)
 		if err != nil {
 			return tracer.Mask(err)
 		}
 	}
 
-	if len(jsn) != 1 || jsn[0] == "" {
+	if !upd {
 		return tracer.Mask(fmt.Errorf("no task found for object ID %q", tas.Core.Map().Object()))
 	}
 
-	var upd *task.Task
-	{
-		upd = task.FromString(jsn[0])
-	}
-
 	{
-		upd.Core.Prg().Expiry()
-		upd.Core.Prg().Worker()
-		upd.Core.Prg().Cycles()
-	}
-
-	{
-		k := e.Keyfmt()
-		v := task.ToString(upd)
-		s := upd.Core.Get().Object().Float()
-
-		_, err := e.red.Sorted().Update().Value(k, v, s)
-		if err != nil {
-			return tracer.Mask(err)
-		}
+		e.notify()
 	}
 
 	return nil
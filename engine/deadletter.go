@@ -0,0 +1,362 @@
+package engine
+
+import (
+	"fmt"
+
+	"github.com/xh3b4sd/objectid"
+	"github.com/xh3b4sd/rescue/store"
+	"github.com/xh3b4sd/rescue/task"
+	"github.com/xh3b4sd/tracer"
+)
+
+func (e *Engine) Deadletter(tas *task.Task) ([]*task.Task, error) {
+	var err error
+	var lis []*task.Task
+
+	e.met.Engine.Deadletter.Cal.Inc()
+
+	o := func() error {
+		lis, err = e.deadletter(tas)
+		if err != nil {
+			return tracer.Mask(err)
+		}
+
+		return nil
+	}
+
+	err = e.met.Engine.Deadletter.Dur.Sin(o)
+	if err != nil {
+		e.met.Engine.Deadletter.Err.Inc()
+		return nil, tracer.Mask(err)
+	}
+
+	return lis, nil
+}
+
+func (e *Engine) PurgeDeadletter(tas *task.Task) error {
+	var err error
+
+	e.met.Engine.PurgeDeadletter.Cal.Inc()
+
+	o := func() error {
+		err = e.purgeDeadletter(tas)
+		if err != nil {
+			return tracer.Mask(err)
+		}
+
+		return nil
+	}
+
+	err = e.met.Engine.PurgeDeadletter.Dur.Sin(o)
+	if err != nil {
+		e.met.Engine.PurgeDeadletter.Err.Inc()
+		return tracer.Mask(err)
+	}
+
+	return nil
+}
+
+func (e *Engine) Redrive(tas *task.Task) error {
+	var err error
+
+	e.met.Engine.Redrive.Cal.Inc()
+
+	o := func() error {
+		err = e.redrive(tas)
+		if err != nil {
+			return tracer.Mask(err)
+		}
+
+		return nil
+	}
+
+	err = e.met.Engine.Redrive.Dur.Sin(o)
+	if err != nil {
+		e.met.Engine.Redrive.Err.Inc()
+		return tracer.Mask(err)
+	}
+
+	return nil
+}
+
+func (e *Engine) deadletter(tas *task.Task) ([]*task.Task, error) {
+	var err error
+
+	{
+		err = verLis(tas)
+		if err != nil {
+			return nil, tracer.Mask(err)
+		}
+	}
+
+	var str []string
+	{
+		str, err = e.sto.Lister(e.dlqKey())
+		if err != nil {
+			return nil, tracer.Mask(err)
+		}
+	}
+
+	{
+		e.met.Task.Deadletter.Set(float64(len(str)))
+	}
+
+	var fil []*task.Task
+	for _, s := range str {
+		x := task.FromString(s)
+		if x.Has(tas) {
+			fil = append(fil, x)
+		}
+	}
+
+	return fil, nil
+}
+
+func (e *Engine) purgeDeadletter(tas *task.Task) error {
+	var err error
+
+	{
+		err = verDlq(tas)
+		if err != nil {
+			return tracer.Mask(err)
+		}
+	}
+
+	var jsn string
+	{
+		jsn, err = e.sto.Search(e.dlqKey(), tas.Core.Get().Object())
+		if err != nil {
+			return tracer.Mask(err)
+		}
+	}
+
+	if jsn == "" {
+		e.met.Task.NotFound.Inc()
+		return tracer.Mask(taskNotFoundError)
+	}
+
+	var del bool
+	{
+		del, err = e.sto.Delete(e.dlqKey(), tas.Core.Get().Object(), jsn)
+		if err != nil {
+			return tracer.Mask(err)
+		}
+	}
+
+	if !del {
+		e.met.Task.Outdated.Inc()
+		return tracer.Maskf(taskOutdatedError, "%s", tas.Core.Map().Object())
+	}
+
+	{
+		err = e.deindex(task.FromString(jsn))
+		if err != nil {
+			return tracer.Mask(err)
+		}
+	}
+
+	return nil
+}
+
+func (e *Engine) redrive(tas *task.Task) error {
+	var err error
+
+	{
+		err = verDlq(tas)
+		if err != nil {
+			return tracer.Mask(err)
+		}
+	}
+
+	// Redriving tasks implies certain write operations across the task queue
+	// and the dead-letter queue. We need to ensure that halted tasks are not
+	// moved concurrently by Engine.Expire while we move them back.
+	{
+		err := e.loc.Acquire()
+		if err != nil {
+			return tracer.Mask(err)
+		}
+
+		defer func() {
+			err := e.loc.Release()
+			if err != nil {
+				e.lerror(tracer.Mask(err))
+			}
+		}()
+	}
+
+	var jsn string
+	{
+		jsn, err = e.sto.Search(e.dlqKey(), tas.Core.Get().Object())
+		if err != nil {
+			return tracer.Mask(err)
+		}
+	}
+
+	if jsn == "" {
+		e.met.Task.NotFound.Inc()
+		return tracer.Mask(taskNotFoundError)
+	}
+
+	// Redriven tasks get another full set of cycles before the circuit breaker
+	// halts them again. Tasks that got dead-lettered because they missed their
+	// deadline would be dead-lettered again right away, and so their deadline
+	// gets removed.
+	var cur *task.Task
+	{
+		cur = task.FromString(jsn)
+	}
+
+	{
+		cur.Core.Prg().Cycles()
+		cur.Core.Prg().Expiry()
+		cur.Core.Prg().Worker()
+	}
+
+	if missed(cur, e.tim.Redrive()) {
+		cur.Core.Prg().Deadline()
+	}
+
+	{
+		err = e.move(e.dlqKey(), e.Keyfmt(), jsn, task.ToString(cur))
+		if err != nil {
+			return tracer.Mask(err)
+		}
+	}
+
+	{
+		e.notify()
+	}
+
+	return nil
+}
+
+// letter moves the given task from the task queue into the dead-letter queue.
+// letter must only be called while holding the global lock.
+func (e *Engine) letter(tas *task.Task) error {
+	var err error
+
+	var jsn string
+	{
+		jsn = task.ToString(tas)
+	}
+
+	{
+		err = e.move(e.Keyfmt(), e.dlqKey(), jsn, jsn)
+		if err != nil {
+			return tracer.Mask(err)
+		}
+	}
+
+	return nil
+}
+
+// move copies the element cur from the sorted set under src into the sorted set
+// under dst as element val, and removes cur from src afterwards, the same way
+// migrate does for task templates. The copy is removed again if cur changed
+// concurrently, in which case taskOutdatedError is returned. move must only be
+// called while holding the global lock.
+func (e *Engine) move(src string, dst string, cur string, val string) error {
+	var err error
+
+	var oid objectid.ID
+	{
+		oid = task.FromString(cur).Core.Get().Object()
+	}
+
+	// Creating the copy fails if a previous move did not complete. The existing
+	// copy is outdated, e.g. because it still carries the cycles count that
+	// Redrive resets, and so we replace it with val.
+	{
+		err = e.sto.Create(dst, oid, val)
+		if store.IsElementExists(err) {
+			err = e.replace(dst, oid, val)
+			if err != nil {
+				return tracer.Mask(err)
+			}
+		} else if err != nil {
+			return tracer.Mask(err)
+		}
+	}
+
+	var del bool
+	{
+		del, err = e.sto.Delete(src, oid, cur)
+		if err != nil {
+			return tracer.Mask(err)
+		}
+	}
+
+	if !del {
+		_, err = e.sto.Delete(dst, oid, val)
+		if err != nil {
+			return tracer.Mask(err)
+		}
+
+		e.met.Task.Outdated.Inc()
+		return tracer.Maskf(taskOutdatedError, "%s", oid)
+	}
+
+	return nil
+}
+
+// replace overwrites the element identified by the given object ID within the
+// sorted set under key with val. replace must only be called while holding
+// the global lock.
+func (e *Engine) replace(key string, oid objectid.ID, val string) error {
+	for i := 0; i < Retry; i++ {
+		jsn, err := e.sto.Search(key, oid)
+		if err != nil {
+			return tracer.Mask(err)
+		}
+
+		if jsn == "" {
+			err = e.sto.Create(key, oid, val)
+			if store.IsElementExists(err) {
+				continue
+			} else if err != nil {
+				return tracer.Mask(err)
+			}
+
+			return nil
+		}
+
+		upd, err := e.sto.Update(key, oid, jsn, val)
+		if err != nil {
+			return tracer.Mask(err)
+		}
+
+		if upd {
+			return nil
+		}
+	}
+
+	e.met.Task.Outdated.Inc()
+	return tracer.Maskf(taskOutdatedError, "%s", oid)
+}
+
+// dlqKey returns the key of the sorted set containing all tasks of this queue
+// that got halted by their circuit breaker, given that the dead-letter queue
+// is enabled.
+func (e *Engine) dlqKey() string {
+	return fmt.Sprintf("%s%sdeadletter", e.Keyfmt(), e.sep)
+}
+
+// halted expresses whether the given task tripped its circuit breaker, so that
+// it is not processed anymore until its cycles got reset.
+func halted(tas *task.Task) bool {
+	return tas.Core.Exi().Cancel() && tas.Core.Get().Cycles() >= tas.Core.Get().Cancel()
+}
+
+func verDlq(tas *task.Task) error {
+	{
+		if tas == nil {
+			return tracer.Maskf(taskEmptyError, "Task must not be empty")
+		}
+		if tas.Core.Emp() {
+			return tracer.Maskf(taskCoreError, "Task.Core must not be empty")
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,60 @@
+package engine
+
+import (
+	"time"
+
+	"github.com/xh3b4sd/rescue/task"
+	"github.com/xh3b4sd/tracer"
+)
+
+// missed expresses whether the given task did not get processed before its
+// deadline passed at the given point in time.
+func missed(tas *task.Task, now time.Time) bool {
+	return tas.Core.Exi().Deadline() && !tas.Core.Get().Deadline().After(now)
+}
+
+// overdue removes the given task that missed its deadline from the task queue.
+// The task is moved into the dead-letter queue, if enabled, and deleted for
+// good otherwise. The returned bool indicates whether the task got removed.
+// overdue must only be called while holding the global lock.
+func (e *Engine) overdue(tas *task.Task) (bool, error) {
+	var err error
+
+	if e.dlq {
+		err = e.letter(tas)
+		if IsTaskOutdated(err) {
+			return false, nil
+		} else if err != nil {
+			return false, tracer.Mask(err)
+		}
+	} else {
+		var del bool
+		{
+			del, err = e.sto.Delete(e.Keyfmt(), tas.Core.Get().Object(), task.ToString(tas))
+			if err != nil {
+				return false, tracer.Mask(err)
+			}
+		}
+
+		if !del {
+			return false, nil
+		}
+
+		err = e.deindex(tas)
+		if err != nil {
+			return false, tracer.Mask(err)
+		}
+	}
+
+	{
+		e.mut.Lock()
+		delete(e.cac, tas.Core.Get().Object())
+		e.mut.Unlock()
+	}
+
+	{
+		e.met.Task.Deadline.Inc()
+	}
+
+	return true, nil
+}
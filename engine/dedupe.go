@@ -0,0 +1,289 @@
+package engine
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/xh3b4sd/objectid"
+	"github.com/xh3b4sd/rescue/task"
+	"github.com/xh3b4sd/tracer"
+)
+
+// dedupe returns the object ID of the task that the given task duplicates, if
+// any. A task duplicates another task if both define the same dedupe key, while
+// the other task does either still exist, or completed within the configured
+// dedupe window. Only the records indexed for the given task's dedupe key are
+// looked at. Stale records are removed along the way. dedupe must only be
+// called while holding the global lock.
+func (e *Engine) dedupe(tas *task.Task, now time.Time) (objectid.ID, error) {
+	var err error
+
+	var key string
+	{
+		key = tas.Core.Get().Dedupe()
+	}
+
+	var mem []string
+	{
+		mem, err = e.sto.Member(e.dedIdx(key))
+		if err != nil {
+			return "", tracer.Mask(err)
+		}
+	}
+
+	for _, x := range mem {
+		var jsn string
+		{
+			jsn, err = e.sto.Search(e.dedKey(), objectid.ID(x))
+			if err != nil {
+				return "", tracer.Mask(err)
+			}
+		}
+
+		var rec *task.Task
+		if jsn != "" {
+			rec = task.FromString(jsn)
+		}
+
+		// The record got removed already, e.g. by collect, and only its index
+		// entry remained. Index entries may further point to records of other
+		// dedupe keys, if recording a task failed because its object ID was
+		// taken already.
+		if rec == nil || rec.Core.Get().Dedupe() != key {
+			err = e.sto.Detach(e.dedIdx(key), x)
+			if err != nil {
+				return "", tracer.Mask(err)
+			}
+
+			continue
+		}
+
+		var dup bool
+		{
+			dup, err = e.duplicate(rec, now)
+			if err != nil {
+				return "", tracer.Mask(err)
+			}
+		}
+
+		if dup {
+			return rec.Core.Get().Object(), nil
+		}
+
+		{
+			err = e.discard(rec, jsn)
+			if err != nil {
+				return "", tracer.Mask(err)
+			}
+		}
+	}
+
+	return "", nil
+}
+
+// duplicate expresses whether the given dedupe record is still in effect at the
+// given point in time. Every record is in effect for as long as its task
+// exists, be it in the task queue, or in the dead-letter queue. Records of
+// completed tasks define the expiry of their dedupe window in addition.
+func (e *Engine) duplicate(rec *task.Task, now time.Time) (bool, error) {
+	if rec.Core.Exi().Expiry() && rec.Core.Get().Expiry().After(now) {
+		return true, nil
+	}
+
+	var oid objectid.ID
+	{
+		oid = rec.Core.Get().Object()
+	}
+
+	{
+		_, jsn, err := e.lookup(oid)
+		if err != nil {
+			return false, tracer.Mask(err)
+		}
+
+		if jsn != "" {
+			return true, nil
+		}
+	}
+
+	{
+		jsn, err := e.sto.Search(e.dlqKey(), oid)
+		if err != nil {
+			return false, tracer.Mask(err)
+		}
+
+		if jsn != "" {
+			return true, nil
+		}
+	}
+
+	return false, nil
+}
+
+// record remembers the dedupe key of the given task, so that duplicates of the
+// given task are not created anymore. The record is indexed by its dedupe key
+// before it gets written, so that dedupe finds every record. record must only
+// be called while holding the global lock.
+func (e *Engine) record(tas *task.Task) error {
+	var rec *task.Task
+	{
+		rec = &task.Task{
+			Core: &task.Core{},
+		}
+	}
+
+	{
+		rec.Core.Set().Dedupe(tas.Core.Get().Dedupe())
+		rec.Core.Set().Object(tas.Core.Get().Object())
+	}
+
+	{
+		err := e.sto.Attach(e.dedIdx(rec.Core.Get().Dedupe()), rec.Core.Map().Object())
+		if err != nil {
+			return tracer.Mask(err)
+		}
+	}
+
+	{
+		err := e.sto.Create(e.dedKey(), rec.Core.Get().Object(), task.ToString(rec))
+		if err != nil {
+			return tracer.Mask(err)
+		}
+	}
+
+	return nil
+}
+
+// discard removes the given dedupe record together with its index entry. The
+// optional value cur is used to only remove the record if it did not change
+// meanwhile, e.g. because its dedupe window got started concurrently. The
+// index entry is kept in that case. discard must only be called while holding
+// the global lock.
+func (e *Engine) discard(rec *task.Task, cur ...string) error {
+	var err error
+
+	var del bool
+	{
+		del, err = e.sto.Delete(e.dedKey(), rec.Core.Get().Object(), cur...)
+		if err != nil {
+			return tracer.Mask(err)
+		}
+	}
+
+	if !del && len(cur) != 0 {
+		return nil
+	}
+
+	{
+		err = e.sto.Detach(e.dedIdx(rec.Core.Get().Dedupe()), rec.Core.Map().Object())
+		if err != nil {
+			return tracer.Mask(err)
+		}
+	}
+
+	return nil
+}
+
+// settle keeps the dedupe record of the given task in effect for the
+// configured dedupe window, starting at the given point in time. settle is
+// called once the given task got deleted, so that tasks that failed to be
+// deleted do not start their dedupe window. Without dedupe window, the dedupe
+// record is not in effect anymore once the task got deleted.
+func (e *Engine) settle(tas *task.Task, now time.Time) error {
+	var err error
+
+	if e.ded == 0 || !tas.Core.Exi().Dedupe() {
+		return nil
+	}
+
+	var oid objectid.ID
+	{
+		oid = tas.Core.Get().Object()
+	}
+
+	var jsn string
+	{
+		jsn, err = e.sto.Search(e.dedKey(), oid)
+		if err != nil {
+			return tracer.Mask(err)
+		}
+	}
+
+	if jsn == "" {
+		return nil
+	}
+
+	var rec *task.Task
+	{
+		rec = task.FromString(jsn)
+	}
+
+	{
+		rec.Core.Set().Expiry(now.Add(e.ded))
+	}
+
+	{
+		_, err = e.sto.Update(e.dedKey(), oid, jsn, task.ToString(rec))
+		if err != nil {
+			return tracer.Mask(err)
+		}
+	}
+
+	return nil
+}
+
+// collect removes all dedupe records that are not in effect anymore at the
+// given point in time, e.g. because their dedupe window passed, or because
+// their tasks got removed. collect must only be called while holding the global
+// lock.
+func (e *Engine) collect(now time.Time) error {
+	var err error
+
+	var str []string
+	{
+		str, err = e.sto.Lister(e.dedKey())
+		if err != nil {
+			return tracer.Mask(err)
+		}
+	}
+
+	for _, s := range str {
+		var rec *task.Task
+		{
+			rec = task.FromString(s)
+		}
+
+		var dup bool
+		{
+			dup, err = e.duplicate(rec, now)
+			if err != nil {
+				return tracer.Mask(err)
+			}
+		}
+
+		if dup {
+			continue
+		}
+
+		{
+			err = e.discard(rec, s)
+			if err != nil {
+				return tracer.Mask(err)
+			}
+		}
+	}
+
+	return nil
+}
+
+// dedKey returns the key of the sorted set containing the dedupe records of
+// all tasks of this queue that got created using a dedupe key.
+func (e *Engine) dedKey() string {
+	return fmt.Sprintf("%s%sdedupe", e.Keyfmt(), e.sep)
+}
+
+// dedIdx returns the key of the set containing the object IDs of all dedupe
+// records defining the given dedupe key.
+func (e *Engine) dedIdx(key string) string {
+	return fmt.Sprintf("%s%sdedupe%s%q", e.Keyfmt(), e.sep, e.sep, key)
+}
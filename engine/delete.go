@@ -44,80 +44,57 @@ func (e *Engine) delete(tas *task.Task) error {
 		}
 	}
 
-	// Deleting tasks implies certain write operations on the task
-	// queue such as updating the owner information. Due to such write
-	// operations we need to ensure that only one process at a time can write
-	// information back to the queue. Otherwise worker behaviour would be
-	// inconsistent and the integrity of the queue could not be guaranteed.
+	// Deleting tasks implies certain write operations on the task queue such as
+	// removing the task or updating its owner information. Workers do not
+	// acquire the global lock for doing so. Instead, every write operation
+	// compares the task's current state with the state that the ownership check
+	// below was based on. If the task changed meanwhile, the write operation
+	// fails and the outdated worker process is informed accordingly.
 	{
-		err := e.loc.Acquire()
+		loc, err := e.deleteLocal(tas)
 		if err != nil {
 			return tracer.Mask(err)
 		}
 
-		defer func() {
-			err := e.loc.Release()
-			if err != nil {
-				e.lerror(tracer.Mask(err))
-			}
-		}()
-	}
-
-	var loc *local
-	{
-		loc = e.cac[tas.Core.Get().Object()]
+		if loc {
+			return nil
+		}
 	}
 
-	// Allow the local deletion of any broadcasted task that is not a task
-	// template.
-	if loc != nil {
-		all := tas.Node.Get(task.Method) == task.MthdAll
-		byp := tas.Core.Exi().Bypass()
-		crn := tas.Cron == nil
-		gat := tas.Gate == nil
-
-		if all && !byp && crn && gat {
-			// We set this worker's internal time pointer to the expiry of the oldest
-			// local task that we track internally. We do this to respect the expiry
-			// of broadcasted tasks indexed locally. Tasks may fail and have to be
-			// picked up again. Any more broadcasted tasks defining the delivery
-			// method "all" may be processed as well if they got created after the
-			// task that we just completed, because we are processing everything in
-			// first-in-first-out fashion.
-			{
-				e.pnt = expiry(e.cac)
-			}
-
-			// Since this worker did its part in processing the broadcasted task, we
-			// can mark this task's local copy as done.
-			{
-				loc.don = true
-			}
-
-			{
-				e.cac[tas.Core.Get().Object()] = loc
-			}
-
-			return nil
+	// Tasks defining the delivery method "mny" are not owned by any worker.
+	// Instead, every target worker records its own completion, and the task is
+	// only removed once every target worker completed it.
+	if tas.Node.Get(task.Method) == task.MthdMny && !tas.Core.Exi().Bypass() {
+		err = e.deleteMany(tas)
+		if err != nil {
+			return tracer.Mask(err)
 		}
+
+		return nil
 	}
 
-	var cur *task.Task
+	// The given task may either be a runnable task or a task template. We
+	// remember the key of the sorted set that the task was found in, so that all
+	// write operations below apply to the right sorted set.
+	var key string
+	var jsn string
 	{
-		k := e.Keyfmt()
-		s := tas.Core.Get().Object().Float()
+		o := tas.Core.Get().Object()
 
-		str, err := e.red.Sorted().Search().Score(k, s, s)
+		key, jsn, err = e.lookup(o)
 		if err != nil {
 			return tracer.Mask(err)
 		}
+	}
 
-		if len(str) != 1 {
-			e.met.Task.NotFound.Inc()
-			return tracer.Maskf(taskNotFoundError, "%s", tas.Core.Map().Object())
-		}
+	if jsn == "" {
+		e.met.Task.NotFound.Inc()
+		return tracer.Maskf(taskNotFoundError, "%s", tas.Core.Map().Object())
+	}
 
-		cur = task.FromString(str[0])
+	var cur *task.Task
+	{
+		cur = task.FromString(jsn)
 	}
 
 	// We need to check the user given task against the actually stored tasks in
@@ -147,18 +124,18 @@ func (e *Engine) delete(tas *task.Task) error {
 	}
 
 	// If the ownership of a task changed meanwhile, return an error to the
-	// outdated worker process.
+	// outdated worker process. Note that the cycles count is only incremented if
+	// the task did not change again in the meantime.
 	if !equ {
 		{
 			cur.Core.Set().Cycles(cur.Core.Get().Cycles() + 1)
 		}
 
 		{
-			k := e.Keyfmt()
+			o := cur.Core.Get().Object()
 			v := task.ToString(cur)
-			s := cur.Core.Get().Object().Float()
 
-			_, err := e.red.Sorted().Update().Value(k, v, s)
+			_, err := e.sto.Update(key, o, jsn, v)
 			if err != nil {
 				return tracer.Mask(err)
 			}
@@ -171,21 +148,111 @@ func (e *Engine) delete(tas *task.Task) error {
 		return tracer.Maskf(taskOutdatedError, "%s", cur.Core.Map().Object())
 	}
 
-	var lis []*task.Task
+	var now time.Time
+	{
+		now = e.tim.Delete()
+	}
+
+	// We need to check whether the given task that we are asked to delete
+	// contains an @defer statement in Task.Cron. If the current task has such a
+	// statement, then we need to update the task in our task queue instead of
+	// deleting it for good. Important here is that no other Task.Cron statement
+	// is present. If for instance the tick+1 label is also present, then that
+	// means that this task has already been deferred and properly executed.
+	var def bool
+	{
+		def = tas.Cron != nil && tas.Cron.Exi().Adefer() && tas.Cron.Len() == 1
+	}
+
+	// Update any task defining Task.Cron or Task.Sync and expire it immediatelly
+	// so that it can be picked up again with the updated synced data. For
+	// non-empty Task.Cron the tick+1 label is important here, for non-empty
+	// Task.Sync the paging pointer is important here.
+	if tas.Gate == nil && tas.Root == nil && (tas.Pag() || def) {
+		{
+			tas.Core.Prg().Expiry()
+			tas.Core.Prg().Worker()
+			tas.Core.Set().Cycles(tas.Core.Get().Cycles() + 1)
+		}
+
+		// Given the condition above, if the task got deferred, then we have a
+		// @defer definition to set a tick+1 for. Requeued tasks may define
+		// Task.Cron without @defer, e.g. @exact, which is kept as is.
+		if def {
+			var tic *ticker.Ticker
+			{
+				tic = ticker.New(tas.Cron.Get().Adefer(), now)
+			}
+
+			var dur time.Duration
+			{
+				dur = tic.Duration()
+			}
+
+			if dur == 0 {
+				return tracer.Maskf(taskCronError, "Task.Cron format must be valid, got @defer = %q", tas.Cron.Get().Adefer())
+			}
+
+			{
+				tas.Cron.Set().TickP1(now.Add(dur))
+			}
+		}
+
+		{
+			o := tas.Core.Get().Object()
+			v := task.ToString(tas)
+
+			upd, err := e.sto.Update(key, o, jsn, v)
+			if err != nil {
+				return tracer.Mask(err)
+			}
+
+			if !upd {
+				e.met.Task.Outdated.Inc()
+				return tracer.Maskf(taskOutdatedError, "%s", tas.Core.Map().Object())
+			}
+		}
+
+		// Requeued tasks can be claimed again right away, while deferred tasks
+		// have to wait for their next tick.
+		if tas.Pag() && !def {
+			e.notify()
+		}
+
+		return nil
+	}
+
+	// Delete the given task, but only if it did not change since we verified
+	// its ownership above. The task might have expired meanwhile, in which case
+	// another worker may have claimed it already.
 	{
-		lis, err = e.searchAll()
+		o := tas.Core.Get().Object()
+
+		del, err := e.sto.Delete(key, o, jsn)
 		if err != nil {
 			return tracer.Mask(err)
 		}
+
+		if !del {
+			e.met.Task.Outdated.Inc()
+			return tracer.Maskf(taskOutdatedError, "%s", tas.Core.Map().Object())
+		}
 	}
 
 	{
-		e.met.Task.Inactive.Set(float64(len(lis)))
+		err = e.deindex(cur)
+		if err != nil {
+			return tracer.Mask(err)
+		}
 	}
 
-	var now time.Time
+	// Completed tasks defining a dedupe key keep preventing duplicates for the
+	// configured dedupe window.
 	{
-		now = e.tim.Delete()
+		err = e.settle(cur, now)
+		if err != nil {
+			return tracer.Mask(err)
+		}
 	}
 
 	// We want to update all the task templates that define matching keys for the
@@ -193,7 +260,15 @@ func (e *Engine) delete(tas *task.Task) error {
 	// defines Task.Gate themselves. Any matching label key will have the
 	// corresponding reserved value of either "deleted" or "waiting".
 	if tas.Gate != nil && tas.Gate.Has(Tri()) {
-		for _, x := range lis {
+		var tpl []*task.Task
+		{
+			tpl, err = e.searchTpl()
+			if err != nil {
+				return tracer.Mask(err)
+			}
+		}
+
+		for _, x := range tpl {
 			// Any task that does not define Task.Gate is not a task template, and so
 			// we ignore it and move on to the next task.
 			if x.Gate == nil {
@@ -206,101 +281,126 @@ func (e *Engine) delete(tas *task.Task) error {
 				continue
 			}
 
-			var gat []string
-			{
-				gat = x.Gate.Any(tas.Gate.Key()...).Key()
-			}
-
 			// Any task template that does not contain any of the given trigger task's
 			// label keys is not the associated task template that we are looking for,
 			// and so we ignore it and move on to the next task.
-			if len(gat) == 0 {
+			if len(x.Gate.Any(tas.Gate.Key()...).Key()) == 0 {
 				continue
 			}
 
-			// Since we found a matching task template that defines the given trigger
-			// task's label keys including their corresponding reserved values
-			// "waiting", we set the values of those keys to "deleted" and update the
-			// system state of the underlying sorted set below.
-			for _, y := range gat {
-				x.Gate.Set(y, task.Deleted)
-			}
+			// Other workers may complete trigger tasks of the same task template
+			// concurrently. So we modify the most recent state of the task template,
+			// and only create the triggered task once our modification got written
+			// successfully.
+			var tri *task.Task
 
-			if x.Sync != nil && tas.Sync != nil {
-				var syn []string
+			fun := func(t *task.Task) bool {
 				{
-					syn = x.Sync.Any(tas.Sync.Key()...).Key()
+					tri = nil
 				}
 
-				for _, y := range syn {
-					x.Sync.Set(y, tas.Sync.Get(y))
+				var gat []string
+				{
+					gat = t.Gate.Any(tas.Gate.Key()...).Key()
 				}
-			}
 
-			// Any task template that does not contain any reserved value "waiting"
-			// anymore does only contain reserved values "deleted". That means this
-			// task template can cause the creation of its trigger task, causing the
-			// task template to be reset for the next cycle.
-			if !x.Gate.Has(Wai()) {
-				var t *task.Task
-				{
-					t = &task.Task{
-						Core: &task.Core{},
-						Meta: x.Meta,
-						Node: x.Node,
-						Root: &task.Root{
-							task.Object: x.Core.Map().Object(),
-						},
-						Sync: x.Sync,
+				if len(gat) == 0 {
+					return false
+				}
+
+				// Since we found a matching task template that defines the given
+				// trigger task's label keys including their corresponding reserved
+				// values "waiting", we set the values of those keys to "deleted" and
+				// update the system state of the underlying sorted set below.
+				for _, y := range gat {
+					t.Gate.Set(y, task.Deleted)
+				}
+
+				if t.Sync != nil && tas.Sync != nil {
+					var syn []string
+					{
+						syn = t.Sync.Any(tas.Sync.Key()...).Key()
+					}
+
+					for _, y := range syn {
+						t.Sync.Set(y, tas.Sync.Get(y))
+					}
+				}
+
+				// Any task template that does not contain any reserved value
+				// "waiting" anymore does only contain reserved values "deleted". That
+				// means this task template can cause the creation of its trigger task,
+				// causing the task template to be reset for the next cycle.
+				if !t.Gate.Has(Wai()) {
+					{
+						tri = &task.Task{
+							Core: &task.Core{},
+							Meta: t.Meta,
+							Node: t.Node,
+							Root: &task.Root{
+								task.Object: t.Core.Map().Object(),
+							},
+							Sync: t.Sync,
+						}
 					}
+
+					// Once all reserved values flipped from "waiting" to "deleted"
+					// within a task template, reset all reserved values back to
+					// "waiting" for the next cycle to begin.
+					for _, y := range t.Gate.Key() {
+						t.Gate.Set(y, task.Waiting)
+					}
+				}
+
+				return true
+			}
+
+			{
+				o := x.Core.Get().Object()
+
+				_, err := e.modifyTpl(o, fun)
+				if err != nil {
+					return tracer.Mask(err)
 				}
+			}
 
+			if tri != nil {
 				var oid objectid.ID
 				{
 					oid = objectid.Random(objectid.Time(now))
 				}
 
 				{
-					t.Core.Set().Object(oid)
+					tri.Core.Set().Object(oid)
 				}
 
-				if t.Node == nil {
-					t.Node = &task.Node{}
+				if tri.Node == nil {
+					tri.Node = &task.Node{}
 				}
 
-				if t.Node.Get(task.Method) == "" {
-					t.Node.Set(task.Method, task.MthdAny)
+				if tri.Node.Get(task.Method) == "" {
+					tri.Node.Set(task.Method, task.MthdAny)
 				}
 
 				{
-					k := e.Keyfmt()
-					v := task.ToString(t)
-					s := oid.Float()
-
-					err = e.red.Sorted().Create().Score(k, v, s)
+					err = e.index(tri)
 					if err != nil {
 						return tracer.Mask(err)
 					}
 				}
 
-				// Once all reserved values flipped from "waiting" to "deleted" within a
-				// task template and the associated trigger task got created, reset all
-				// reserved values back to "waiting" for the next cycle to begin.
-				for _, y := range x.Gate.Key() {
-					x.Gate.Set(y, task.Waiting)
-				}
-			}
+				{
+					k := e.Keyfmt()
+					v := task.ToString(tri)
 
-			// Update the system state of the task template in the underlying sorted
-			// set.
-			{
-				k := e.Keyfmt()
-				v := task.ToString(x)
-				s := x.Core.Get().Object().Float()
+					err = e.sto.Create(k, oid, v)
+					if err != nil {
+						return tracer.Mask(err)
+					}
+				}
 
-				_, err := e.red.Sorted().Update().Value(k, v, s)
-				if err != nil {
-					return tracer.Mask(err)
+				{
+					e.notify()
 				}
 			}
 		}
@@ -309,95 +409,82 @@ func (e *Engine) delete(tas *task.Task) error {
 	// Update any task template defining Task.Cron with the scheduled task data
 	// specified in Task.Sync, if such data exists.
 	if tas.Root != nil && tas.Root.Exi(task.Object) && tas.Sync != nil && !tas.Sync.Emp() {
-		for _, x := range lis {
-			if x.Core.Map().Object() != tas.Root.Get(task.Object) {
-				continue
-			}
-
-			{
-				x.Sync = tas.Sync
-			}
+		fun := func(t *task.Task) bool {
+			t.Sync = tas.Sync
+			return true
+		}
 
-			{
-				k := e.Keyfmt()
-				v := task.ToString(x)
-				s := x.Core.Get().Object().Float()
+		{
+			o := objectid.ID(tas.Root.Get(task.Object))
 
-				_, err := e.red.Sorted().Update().Value(k, v, s)
-				if err != nil {
-					return tracer.Mask(err)
-				}
+			_, err := e.modifyTpl(o, fun)
+			if err != nil {
+				return tracer.Mask(err)
 			}
 		}
 	}
 
-	// We need to check whether the given task that we are asked to delete
-	// contains an @defer statement in Task.Cron. If the current task has such a
-	// statement, then we need to update the task in our task queue instead of
-	// deleting it for good. Important here is that no other Task.Cron statement
-	// is present. If for instance the tick+1 label is also present, then that
-	// means that this task has already been deferred and properly executed.
-	var def bool
+	return nil
+}
+
+// deleteLocal allows the local deletion of any broadcasted task that is not a
+// task template. The returned bool indicates whether the given task got
+// completed locally.
+func (e *Engine) deleteLocal(tas *task.Task) (bool, error) {
+	var loc *local
 	{
-		def = tas.Cron != nil && tas.Cron.Exi().Adefer() && tas.Cron.Len() == 1
+		e.mut.Lock()
+		loc = e.cac[tas.Core.Get().Object()]
+		e.mut.Unlock()
 	}
 
-	// Update any task defining Task.Cron or Task.Sync and expire it immediatelly
-	// so that it can be picked up again with the updated synced data. For
-	// non-empty Task.Cron the tick+1 label is important here, for non-empty
-	// Task.Sync the paging pointer is important here.
-	if tas.Gate == nil && tas.Root == nil && (tas.Pag() || def) {
-		{
-			tas.Core.Prg().Expiry()
-			tas.Core.Prg().Worker()
-			tas.Core.Set().Cycles(tas.Core.Get().Cycles() + 1)
-		}
+	if loc == nil {
+		return false, nil
+	}
 
-		// Given the condition above, if Task.Cron is defined here, then we have a
-		// @defer definition to set a tick+1 for.
-		if tas.Cron != nil {
-			var tic *ticker.Ticker
-			{
-				tic = ticker.New(tas.Cron.Get().Adefer(), now)
-			}
+	all := tas.Node.Get(task.Method) == task.MthdAll
+	byp := tas.Core.Exi().Bypass()
+	crn := tas.Cron == nil
+	gat := tas.Gate == nil
 
-			var dur time.Duration
-			{
-				dur = tic.Duration()
-			}
-
-			if dur == 0 {
-				return tracer.Maskf(taskCronError, "Task.Cron format must be valid, got @defer = %q", tas.Cron.Get().Adefer())
-			}
+	if !all || byp || !crn || !gat {
+		return false, nil
+	}
 
-			{
-				tas.Cron.Set().TickP1(now.Add(dur))
-			}
+	// Every worker completing a broadcasted task records its completion, so
+	// that producers can tell which workers processed it, and so that this
+	// worker does not process it again after a restart. The local copy is only
+	// marked as done once the completion got recorded.
+	{
+		err := e.acknowledge(tas)
+		if err != nil {
+			return false, tracer.Mask(err)
 		}
+	}
 
-		{
-			k := e.Keyfmt()
-			v := task.ToString(tas)
-			s := tas.Core.Get().Object().Float()
-
-			_, err := e.red.Sorted().Update().Value(k, v, s)
-			if err != nil {
-				return tracer.Mask(err)
-			}
-		}
+	e.mut.Lock()
+	defer e.mut.Unlock()
 
-		return nil
+	// We set this worker's internal time pointer to the expiry of the oldest
+	// local task that we track internally. We do this to respect the expiry of
+	// broadcasted tasks indexed locally. Tasks may fail and have to be picked up
+	// again. Any more broadcasted tasks defining the delivery method "all" may be
+	// processed as well if they got created after the task that we just
+	// completed, because we are processing everything in first-in-first-out
+	// fashion.
+	{
+		e.pnt = expiry(e.cac)
 	}
 
+	// Since this worker did its part in processing the broadcasted task, we can
+	// mark this task's local copy as done.
 	{
-		k := e.Keyfmt()
-		s := tas.Core.Get().Object().Float()
+		loc.don = true
+	}
 
-		err = e.red.Sorted().Delete().Score(k, s)
-		if err != nil {
-			return tracer.Mask(err)
-		}
+	{
+		e.cac[tas.Core.Get().Object()] = loc
 	}
 
-	return nil
+	return true, nil
 }
@@ -1,80 +1,171 @@
 package engine
 
 import (
+	"sync"
 	"time"
 
 	"github.com/google/uuid"
-	"github.com/xh3b4sd/breakr"
 	"github.com/xh3b4sd/logger"
 	"github.com/xh3b4sd/objectid"
 	"github.com/xh3b4sd/redigo"
 	"github.com/xh3b4sd/redigo/locker"
-	"github.com/xh3b4sd/redigo/pool"
 	"github.com/xh3b4sd/rescue/balancer"
 	"github.com/xh3b4sd/rescue/metric"
+	"github.com/xh3b4sd/rescue/store"
+	"github.com/xh3b4sd/rescue/task"
 	"github.com/xh3b4sd/rescue/timer"
 	"github.com/xh3b4sd/tracer"
 )
 
+const (
+	// Ceiling is the default upper limit of the expiry that tasks may define
+	// using task.rescue.io/lease.
+	Ceiling = time.Hour
+)
+
 const (
 	// Expiry is the default expiry of any given task.
 	Expiry = 30 * time.Second
 )
 
+const (
+	// Heartbeat is the default timeout after which workers that did not send any
+	// heartbeat are not considered to be alive anymore.
+	Heartbeat = 30 * time.Second
+)
+
+const (
+	// Retry is the amount of attempts to modify a task that changed concurrently.
+	// Tasks are modified using compare-and-update semantics, so that workers do
+	// not have to acquire the global lock for searching, extending and deleting
+	// tasks. If a task changed between reading and writing it, the modification
+	// is attempted again based on the most recent state of the queue.
+	Retry = 5
+)
+
+const (
+	// Wakeup is the default interval at which workers blocked in
+	// Engine.SearchWait search for tasks again, in case they did not get
+	// notified about any change of the queue.
+	Wakeup = 5 * time.Second
+)
+
 const (
 	// Week is the time.Duration of 7 days.
 	Week = 7 * 24 * time.Hour
 )
 
 type Config struct {
-	Balancer balancer.Interface
-	Cleanup  time.Duration
-	Expiry   time.Duration
-	Locker   locker.Interface
-	Logger   logger.Interface
-	Metric   *metric.Collection
-	Queue    string
-	Redigo   redigo.Interface
-	Sepkey   string
-	Timer    *timer.Timer
-	Worker   string
+	Aging       time.Duration
+	Backoff     Backoff
+	Balancer    balancer.Interface
+	Capability  []*task.Meta
+	Ceiling     time.Duration
+	Cleanup     time.Duration
+	Concurrency []Concurrency
+	Deadletter  bool
+	Dedupe      time.Duration
+	Expiry      time.Duration
+	Heartbeat   time.Duration
+	Labels      map[string]string
+	Locker      locker.Interface
+	Logger      logger.Interface
+	Metric      *metric.Collection
+	Queue       string
+	Rate        []Rate
+	Redigo      redigo.Interface
+	Sepkey      string
+	Store       store.Interface
+	Timer       *timer.Timer
+	Wakeup      time.Duration
+	Worker      string
 }
 
 type Engine struct {
+	// agi is the optional interval after which the effective priority of
+	// unclaimed tasks grows by 1.
+	agi time.Duration
 	bal balancer.Interface
+	bck Backoff
 	// cac is the local lookup table for tasks that have been chosen to be
 	// processed without assigning direct ownership to this particular worker
 	// process. An example of necessary mappings we need to track for workers are
 	// all tasks defining the delivery method "all".
 	cac map[objectid.ID]*local
+	cei time.Duration
 	cln time.Duration
+	// con are the optional rules limiting the amount of matching tasks owned by
+	// workers at once.
+	con []Concurrency
+	// cpb are the optional Task.Meta selectors of all tasks that this worker is
+	// capable of processing.
+	cpb []*task.Meta
+	// ded is the optional window during which completed tasks keep preventing
+	// the creation of tasks defining the same dedupe key.
+	ded time.Duration
+	// dlq expresses whether halted tasks get moved into the dead-letter queue.
+	dlq bool
 	exp time.Duration
+	// hrt is the timeout after which workers that did not send any heartbeat
+	// are not considered to be alive anymore.
+	hrt time.Duration
+	// lab are the optional labels that this worker announces within the worker
+	// registry.
+	lab map[string]string
 	loc locker.Interface
 	log logger.Interface
 	met *metric.Collection
+	// mny is the local lookup table for tasks defining the delivery method "mny"
+	// that this worker is processing, or completed already. Such tasks are not
+	// owned by any worker, the same way broadcasted tasks are not.
+	mny map[objectid.ID]*local
+	// mut guards the local lookup table and the local point in time, since the
+	// process local state is not protected by the global lock anymore.
+	mut sync.Mutex
 	// pnt is the local point in time at which this worker became operational.
 	// Further, this pointer will move forward with every broadcasted task that
 	// got completed locally. This pointer will be used to e.g. decide whether to
-	// process broadcasted tasks declared with method "all".
+	// process broadcasted tasks declared with method "all". Workers restarting
+	// with the same identifier recover this pointer from the worker registry.
 	pnt time.Time
 	que string
+	// rec expresses whether this worker recovered its progress of processing
+	// broadcasted tasks from the worker registry already.
+	rec bool
+	// rte are the optional rules limiting the throughput of matching tasks
+	// being claimed by workers.
+	rte []Rate
 	red redigo.Interface
 	sep string
+	// sta is the point in time at which this worker became operational, as
+	// announced within the worker registry.
+	sta time.Time
+	sto store.Interface
 	tim *timer.Timer
+	wak time.Duration
 	// wrk is the identifier of this worker process.
 	wrk string
 }
 
 func New(config Config) *Engine {
+	if config.Backoff.Base != 0 && config.Backoff.Max == 0 {
+		config.Backoff.Max = Week
+	}
 	if config.Balancer == nil {
 		config.Balancer = balancer.Default()
 	}
+	if config.Ceiling == 0 {
+		config.Ceiling = Ceiling
+	}
 	if config.Cleanup == 0 {
 		config.Cleanup = Week
 	}
 	if config.Expiry == 0 {
 		config.Expiry = Expiry
 	}
+	if config.Heartbeat == 0 {
+		config.Heartbeat = Heartbeat
+	}
 	if config.Logger == nil {
 		config.Logger = logger.Default()
 	}
@@ -84,11 +175,17 @@ func New(config Config) *Engine {
 	if config.Queue == "" {
 		config.Queue = "default"
 	}
-	if config.Redigo == nil {
+	if config.Store == nil && config.Redigo == nil {
 		config.Redigo = redigo.Default()
 	}
+	if config.Store == nil {
+		config.Store = store.NewRedis(store.RedisConfig{
+			Locker: config.Locker,
+			Redigo: config.Redigo,
+		})
+	}
 	if config.Locker == nil {
-		config.Locker = defLoc(config.Redigo.Listen())
+		config.Locker = config.Store.Locker()
 	}
 	if config.Sepkey == "" {
 		config.Sepkey = ":"
@@ -96,23 +193,40 @@ func New(config Config) *Engine {
 	if config.Timer == nil {
 		config.Timer = timer.New()
 	}
+	if config.Wakeup == 0 {
+		config.Wakeup = Wakeup
+	}
 	if config.Worker == "" {
 		config.Worker = uuid.New().String()
 	}
 
 	e := &Engine{
+		agi: config.Aging,
 		bal: config.Balancer,
+		bck: config.Backoff,
 		cac: map[objectid.ID]*local{},
+		cei: config.Ceiling,
 		cln: config.Cleanup,
+		con: config.Concurrency,
+		cpb: config.Capability,
+		ded: config.Dedupe,
+		dlq: config.Deadletter,
 		exp: config.Expiry,
+		hrt: config.Heartbeat,
+		lab: config.Labels,
 		loc: config.Locker,
 		log: config.Logger,
 		met: config.Metric,
+		mny: map[objectid.ID]*local{},
 		pnt: config.Timer.Engine(),
 		que: config.Queue,
+		rte: config.Rate,
 		red: config.Redigo,
 		sep: config.Sepkey,
+		sta: config.Timer.Engine(),
+		sto: config.Store,
 		tim: config.Timer,
+		wak: config.Wakeup,
 		wrk: config.Worker,
 	}
 
@@ -126,15 +240,3 @@ func (e *Engine) lerror(err error) {
 		"stack", tracer.Stack(err),
 	)
 }
-
-func defLoc(add string) locker.Interface {
-	return locker.New(locker.Config{
-		Brk: breakr.New(breakr.Config{
-			Failure: breakr.Failure{
-				Budget: 30,
-				Cooler: 1 * time.Second,
-			},
-		}),
-		Poo: pool.NewSinglePoolWithAddress(add),
-	})
-}
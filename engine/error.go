@@ -22,6 +22,23 @@ func IsLabelValue(err error) bool {
 	return errors.Is(err, labelValueError)
 }
 
+var searchCountError = &tracer.Error{
+	Kind: "searchCountError",
+}
+
+func IsSearchCount(err error) bool {
+	return errors.Is(err, searchCountError)
+}
+
+var taskBatchError = &tracer.Error{
+	Kind: "taskBatchError",
+	Desc: "When creating tasks in batches, all tasks must be valid. No task of the batch got created. The index of every invalid task is reported alongside the reason for it being invalid.",
+}
+
+func IsTaskBatch(err error) bool {
+	return errors.Is(err, taskBatchError)
+}
+
 var taskCoreError = &tracer.Error{
 	Kind: "taskCoreError",
 }
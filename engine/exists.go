@@ -54,33 +54,25 @@ func (e *Engine) exists(tas *task.Task) (bool, error) {
 	}
 
 	// Checking for existing tasks implies certain read operations on the task
-	// queue. For consistency reasons we need to ensure that only one process at
-	// a time can read information from the queue. Otherwise worker behaviour
-	// would be inconsistent and the integrity of the queue could not be
-	// guaranteed.
+	// queue. Reading all tasks happens atomically within the underlying storage,
+	// which is why we do not need to acquire the global lock here. Tasks are
+	// resolved using the label indexes if possible, and only otherwise by
+	// scanning the whole queue.
+	var idx bool
+	var lis []*task.Task
 	{
-		err := e.loc.Acquire()
+		lis, idx, err = e.indexed(tas)
 		if err != nil {
 			return false, tracer.Mask(err)
 		}
-
-		defer func() {
-			err := e.loc.Release()
-			if err != nil {
-				e.lerror(tracer.Mask(err))
-			}
-		}()
 	}
 
-	var lis []*task.Task
-	{
-		lis, err = e.searchAll()
+	if !idx {
+		lis, err = e.searchLis()
 		if err != nil {
 			return false, tracer.Mask(err)
 		}
-	}
 
-	{
 		e.met.Task.Inactive.Set(float64(len(lis)))
 	}
 
@@ -3,6 +3,7 @@ package engine
 import (
 	"time"
 
+	"github.com/xh3b4sd/objectid"
 	"github.com/xh3b4sd/rescue/task"
 	"github.com/xh3b4sd/tracer"
 )
@@ -34,10 +35,11 @@ func (e *Engine) expire() error {
 	var err error
 
 	// Expiring task ownership implies certain write operations on the task
-	// queue such as updating the owner information. Due to such write
-	// operations we need to ensure that only one process at a time can write
-	// information back to the queue. Otherwise worker behaviour would be
-	// inconsistent and the integrity of the queue could not be guaranteed.
+	// queue such as updating the owner information. Due to the balancing
+	// calculations below we need to ensure that only one process at a time can
+	// expire tasks. Note that workers searching, extending and deleting tasks do
+	// not acquire the lock, which is why every write operation below only
+	// succeeds if the modified task did not change concurrently.
 	{
 		err := e.loc.Acquire()
 		if err != nil {
@@ -52,6 +54,15 @@ func (e *Engine) expire() error {
 		}()
 	}
 
+	// Queues that existed before the label indexes got introduced are indexed
+	// once, so that Engine.Exists and Engine.Lister can make use of them.
+	{
+		err = e.reindex()
+		if err != nil {
+			return tracer.Mask(err)
+		}
+	}
+
 	var lis []*task.Task
 	{
 		lis, err = e.searchAll()
@@ -60,10 +71,47 @@ func (e *Engine) expire() error {
 		}
 	}
 
+	// Queues that existed before task templates got stored separately are
+	// migrated once, so that only runnable tasks remain to be looked at.
+	{
+		lis, err = e.migrate(lis)
+		if err != nil {
+			return tracer.Mask(err)
+		}
+	}
+
 	{
 		e.met.Task.Inactive.Set(float64(len(lis)))
 	}
 
+	if e.dlq {
+		dlq, err := e.sto.Lister(e.dlqKey())
+		if err != nil {
+			return tracer.Mask(err)
+		}
+
+		e.met.Task.Deadletter.Set(float64(len(dlq)))
+	}
+
+	// Dedupe records of tasks that got removed without being completed, e.g.
+	// due to missed deadlines, would prevent duplicates forever, and so we remove
+	// them here together with all records whose dedupe window passed.
+	{
+		err = e.collect(e.tim.Expire())
+		if err != nil {
+			return tracer.Mask(err)
+		}
+	}
+
+	// Records of workers that stopped sending heartbeats a long time ago are
+	// removed from the worker registry, so that it does not grow forever.
+	{
+		err = e.forget(e.tim.Expire())
+		if err != nil {
+			return tracer.Mask(err)
+		}
+	}
+
 	if len(lis) == 0 {
 		return nil
 	}
@@ -71,10 +119,12 @@ func (e *Engine) expire() error {
 	var rem []int
 	for i, x := range lis {
 		// Skip all scheduled task templates for further processing. Any task
-		// template defining Task.Cron is meant to trigger time based task
-		// scheduling for child tasks originating from that template. The template
-		// itself is not meant to be processed by workers.
-		if x.Cron != nil {
+		// template defining Task.Cron with @every is meant to trigger time based
+		// task scheduling for child tasks originating from that template. The
+		// template itself is not meant to be processed by workers. Deferred tasks
+		// defining @defer are processed by workers, which is why their ownership
+		// must expire like the ownership of any other task.
+		if x.Cron != nil && x.Cron.Exi().Aevery() {
 			rem = append(rem, i)
 			continue
 		}
@@ -95,6 +145,40 @@ func (e *Engine) expire() error {
 			continue
 		}
 
+		// Move all tasks with an active circuit breaker into the dead-letter
+		// queue, if enabled. Any task hitting their defined maximum amount of
+		// execution attempts is effectively on hold until their cycles counts are
+		// reset. Tasks that are still owned by a worker are moved once their
+		// ownership got revoked. The task might have been modified concurrently,
+		// in which case we try again during the next call.
+		if e.dlq && halted(x) && x.Core.Get().Worker() == "" {
+			err = e.letter(x)
+			if IsTaskOutdated(err) {
+				continue
+			} else if err != nil {
+				return tracer.Mask(err)
+			}
+
+			rem = append(rem, i)
+			continue
+		}
+
+		// Remove all tasks that did not get processed before their deadline
+		// passed. Tasks that are still owned by a worker are removed once their
+		// ownership got revoked.
+		if missed(x, e.tim.Expire()) && x.Core.Get().Worker() == "" {
+			del, err := e.overdue(x)
+			if err != nil {
+				return tracer.Mask(err)
+			}
+
+			if del {
+				rem = append(rem, i)
+			}
+
+			continue
+		}
+
 		// Derive this task's creation timestamp from its object ID.
 		var tim time.Time
 		{
@@ -107,22 +191,35 @@ func (e *Engine) expire() error {
 		// keeping in order to prevent unnecessary state bloat, we just get rid of
 		// it eventually. The assumption here right now is that tasks to be
 		// processed by all workers within the network are either already processed,
-		// or not relevant anymore beyond 1 week of creation.
-		if e.tim.Search().Sub(tim) > e.cln {
+		// or not relevant anymore beyond 1 week of creation. Deferred tasks are
+		// meant to be executed repeatedly and are therefore never cleaned up,
+		// unlike retried tasks, which only define tick+1 in Task.Cron.
+		if (x.Cron == nil || retried(x)) && e.tim.Search().Sub(tim) > e.cln {
 			// Remove the irrelevant task from memory, if any.
 			{
+				e.mut.Lock()
 				delete(e.cac, x.Core.Get().Object())
+				delete(e.mny, x.Core.Get().Object())
+				e.mut.Unlock()
 			}
 
 			// Remove the irrelevant task from the underlying queue.
 			{
 				k := e.Keyfmt()
-				s := x.Core.Get().Object().Float()
+				o := x.Core.Get().Object()
+				v := task.ToString(x)
 
-				err = e.red.Sorted().Delete().Score(k, s)
+				del, err := e.sto.Delete(k, o, v)
 				if err != nil {
 					return tracer.Mask(err)
 				}
+
+				if del {
+					err = e.deindex(x)
+					if err != nil {
+						return tracer.Mask(err)
+					}
+				}
 			}
 
 			{
@@ -146,6 +243,12 @@ func (e *Engine) expire() error {
 		return nil
 	}
 
+	var rev bool
+
+	// Tasks revoked below are not owned by any worker anymore, which is why
+	// they must not be revoked again when balancing the remaining tasks.
+	don := map[objectid.ID]bool{}
+
 	cur := map[string]int{}
 	for _, l := range lis {
 		cur[l.Core.Get().Worker()]++
@@ -177,35 +280,42 @@ func (e *Engine) expire() error {
 			continue
 		}
 
+		var upd bool
 		{
-			x.Core.Prg().Expiry()
-			x.Core.Prg().Worker()
-			x.Core.Set().Cycles(x.Core.Get().Cycles() + 1)
-		}
-
-		{
-			k := e.Keyfmt()
-			v := task.ToString(x)
-			s := x.Core.Get().Object().Float()
-
-			_, err := e.red.Sorted().Update().Value(k, v, s)
+			upd, err = e.revoke(x, wrk, now)
 			if err != nil {
 				return tracer.Mask(err)
 			}
 		}
 
+		// The task might have been extended or deleted by its owner meanwhile,
+		// in which case there is nothing to expire anymore.
+		if !upd {
+			continue
+		}
+
 		{
 			e.met.Task.Expired.Inc()
 		}
 
 		{
 			cur[wrk]--
+			don[x.Core.Get().Object()] = true
+			rev = true
+		}
+	}
+
+	var wrk []string
+	{
+		wrk, err = e.workers(cur, lis)
+		if err != nil {
+			return tracer.Mask(err)
 		}
 	}
 
 	var des map[string]int
 	{
-		des = e.bal.Opt(ensure(keys(cur), e.wrk), sum(cur))
+		des = e.bal.Opt(wrk, sum(cur))
 	}
 
 	var dev map[string]int
@@ -214,6 +324,10 @@ func (e *Engine) expire() error {
 	}
 
 	for _, x := range lis {
+		if don[x.Core.Get().Object()] {
+			continue
+		}
+
 		// We are looking for tasks which have an owner that is supposed to
 		// revoke their ownership. So if there is no revocation indicated
 		// for the current owner we ignore the task and move on to find
@@ -240,35 +354,73 @@ func (e *Engine) expire() error {
 			continue
 		}
 
+		var upd bool
 		{
-			x.Core.Prg().Expiry()
-			x.Core.Prg().Worker()
-			x.Core.Set().Cycles(x.Core.Get().Cycles() + 1)
-		}
-
-		{
-			k := e.Keyfmt()
-			v := task.ToString(x)
-			s := x.Core.Get().Object().Float()
-
-			_, err := e.red.Sorted().Update().Value(k, v, s)
+			upd, err = e.revoke(x, wrk, now)
 			if err != nil {
 				return tracer.Mask(err)
 			}
 		}
 
+		if !upd {
+			continue
+		}
+
 		{
 			e.met.Task.Expired.Inc()
 		}
 
 		{
 			dev[wrk]--
+			rev = true
 		}
 	}
 
+	// Tasks that got revoked can be claimed by other workers again.
+	if rev {
+		e.notify()
+	}
+
 	if sum(dev) != 0 {
 		return tracer.Mask(taskNotRevokedError)
 	}
 
 	return nil
 }
+
+// revoke removes the ownership of the given task, given that the stored task is
+// still owned by wrk and expired at the given point in time.
+func (e *Engine) revoke(tas *task.Task, wrk string, now time.Time) (bool, error) {
+	var err error
+
+	fun := func(t *task.Task) bool {
+		if t.Core.Get().Worker() != wrk || t.Core.Get().Expiry().After(now) {
+			return false
+		}
+
+		{
+			t.Core.Prg().Expiry()
+			t.Core.Prg().Worker()
+			t.Core.Set().Cycles(t.Core.Get().Cycles() + 1)
+		}
+
+		{
+			e.backoff(t, now)
+		}
+
+		return true
+	}
+
+	var upd bool
+	{
+		k := e.Keyfmt()
+		o := tas.Core.Get().Object()
+
+		upd, err = e.modify(k, o, fun)
+		if err != nil {
+			return false, tracer.Mask(err)
+		}
+	}
+
+	return upd, nil
+}
@@ -29,6 +29,8 @@ func (e *Engine) Extend(tas *task.Task) error {
 }
 
 func (e *Engine) extend(tas *task.Task) error {
+	var err error
+
 	{
 		if tas == nil {
 			return tracer.Maskf(taskEmptyError, "Task must not be empty")
@@ -38,41 +40,35 @@ func (e *Engine) extend(tas *task.Task) error {
 		}
 	}
 
-	// Extending task expiry implies certain write operations on the task queue
-	// such as updating the expiry information. Due to such write operations we
-	// need to ensure that only one process at a time can write information back
-	// to the queue. Otherwise worker behaviour would be inconsistent and the
-	// integrity of the queue could not be guaranteed.
-	{
-		err := e.loc.Acquire()
-		if err != nil {
-			return tracer.Mask(err)
-		}
-
-		defer func() {
-			err := e.loc.Release()
-			if err != nil {
-				e.lerror(tracer.Mask(err))
-			}
-		}()
+	// Broadcasted tasks are not owned by any worker within the underlying
+	// system. Their expiry is only tracked locally.
+	if e.extendLocal(tas) {
+		return nil
 	}
 
-	var cur *task.Task
+	// Extending task expiry implies certain write operations on the task queue
+	// such as updating the expiry information. Workers do not acquire the global
+	// lock for doing so. Instead, the expiry is only updated if the stored task
+	// did not change since we verified its ownership below.
+	var jsn string
 	{
 		k := e.Keyfmt()
-		s := tas.Core.Get().Object().Float()
+		o := tas.Core.Get().Object()
 
-		str, err := e.red.Sorted().Search().Score(k, s, s)
+		jsn, err = e.sto.Search(k, o)
 		if err != nil {
 			return tracer.Mask(err)
 		}
+	}
 
-		if len(str) != 1 {
-			e.met.Task.NotFound.Inc()
-			return tracer.Mask(taskNotFoundError)
-		}
+	if jsn == "" {
+		e.met.Task.NotFound.Inc()
+		return tracer.Mask(taskNotFoundError)
+	}
 
-		cur = task.FromString(str[0])
+	var cur *task.Task
+	{
+		cur = task.FromString(jsn)
 	}
 
 	// Tasks can only be extended by workers owning that task.
@@ -88,18 +84,39 @@ func (e *Engine) extend(tas *task.Task) error {
 	}
 
 	{
-		cur.Core.Set().Expiry(e.tim.Extend().Add(e.exp))
+		cur.Core.Set().Expiry(e.tim.Extend().Add(e.expiry(cur)))
 	}
 
 	{
 		k := e.Keyfmt()
+		o := cur.Core.Get().Object()
 		v := task.ToString(cur)
-		s := cur.Core.Get().Object().Float()
 
-		_, err := e.red.Sorted().Update().Value(k, v, s)
+		upd, err := e.sto.Update(k, o, jsn, v)
 		if err != nil {
 			return tracer.Mask(err)
 		}
+
+		// The task might have been deleted meanwhile, in which case there is
+		// nothing left to extend.
+		if !upd {
+			jsn, err := e.sto.Search(k, o)
+			if err != nil {
+				return tracer.Mask(err)
+			}
+
+			if jsn == "" {
+				e.met.Task.NotFound.Inc()
+				return tracer.Mask(taskNotFoundError)
+			}
+		}
+
+		// The task might have expired and got claimed by another worker
+		// meanwhile, in which case this worker does not own the task anymore.
+		if !upd {
+			e.met.Task.Outdated.Inc()
+			return tracer.Maskf(taskOutdatedError, "%s", tas.Core.Map().Object())
+		}
 	}
 
 	{
@@ -108,3 +125,26 @@ func (e *Engine) extend(tas *task.Task) error {
 
 	return nil
 }
+
+// extendLocal allows the local extension of any broadcasted or multicasted
+// task that this worker is currently processing. The returned bool indicates
+// whether the given task got extended locally.
+func (e *Engine) extendLocal(tas *task.Task) bool {
+	e.mut.Lock()
+	defer e.mut.Unlock()
+
+	var loc *local
+	{
+		loc = e.tracked(tas)
+	}
+
+	if loc == nil || loc.don {
+		return false
+	}
+
+	{
+		loc.exp = e.tim.Extend().Add(e.expiry(tas))
+	}
+
+	return true
+}
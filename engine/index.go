@@ -0,0 +1,254 @@
+package engine
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/xh3b4sd/objectid"
+	"github.com/xh3b4sd/rescue/task"
+	"github.com/xh3b4sd/tracer"
+)
+
+// index adds the object ID of the given task to the index sets of all label
+// pairs defined in Task.Meta. Task.Meta never changes once a task got created,
+// which is why tasks are only ever indexed before they get created, and
+// deindexed after they got deleted. That way every existing task can be found
+// using the label indexes.
+func (e *Engine) index(tas *task.Task) error {
+	if tas.Meta == nil {
+		return nil
+	}
+
+	for k, v := range *tas.Meta {
+		err := e.sto.Attach(e.idxKey(), k)
+		if err != nil {
+			return tracer.Mask(err)
+		}
+
+		err = e.sto.Attach(e.idxPair(k, v), tas.Core.Map().Object())
+		if err != nil {
+			return tracer.Mask(err)
+		}
+	}
+
+	return nil
+}
+
+// deindex removes the object ID of the given task from the index sets of all
+// label pairs defined in Task.Meta. The recorded completions of broadcasted
+// tasks are removed as well.
+func (e *Engine) deindex(tas *task.Task) error {
+	err := e.unacknowledge(tas)
+	if err != nil {
+		return tracer.Mask(err)
+	}
+
+	if tas.Meta == nil {
+		return nil
+	}
+
+	for k, v := range *tas.Meta {
+		err := e.sto.Detach(e.idxPair(k, v), tas.Core.Map().Object())
+		if err != nil {
+			return tracer.Mask(err)
+		}
+	}
+
+	return nil
+}
+
+// indexMany indexes all of the given tasks. Should any of them fail to be
+// indexed, the tasks indexed already are removed from the label indexes again.
+func (e *Engine) indexMany(lis []*task.Task) error {
+	for i, x := range lis {
+		err := e.index(x)
+		if err != nil {
+			e.deindexMany(lis[:i+1])
+			return tracer.Mask(err)
+		}
+	}
+
+	return nil
+}
+
+// deindexMany removes all of the given tasks from the label indexes, e.g. once
+// they could not be created after all. Failures are only logged, since the
+// label indexes tolerate references to tasks that do not exist.
+func (e *Engine) deindexMany(lis []*task.Task) {
+	for _, x := range lis {
+		err := e.deindex(x)
+		if err != nil {
+			e.lerror(tracer.Mask(err))
+		}
+	}
+}
+
+// reindex indexes all tasks and task templates of this queue, unless the label
+// indexes are complete already.
+func (e *Engine) reindex() error {
+	var err error
+
+	var ver bool
+	{
+		ver, err = e.migrated(verIndex)
+		if err != nil {
+			return tracer.Mask(err)
+		}
+	}
+
+	if ver {
+		return nil
+	}
+
+	var lis []*task.Task
+	{
+		lis, err = e.searchLis()
+		if err != nil {
+			return tracer.Mask(err)
+		}
+	}
+
+	for _, x := range lis {
+		err = e.index(x)
+		if err != nil {
+			return tracer.Mask(err)
+		}
+	}
+
+	{
+		err = e.sto.Attach(e.verKey(), verIndex)
+		if err != nil {
+			return tracer.Mask(err)
+		}
+	}
+
+	return nil
+}
+
+// indexed returns all tasks matching the Task.Meta label pairs of the given
+// task using the label indexes. The returned bool is false if the label
+// indexes cannot be used, in which case the whole queue has to be scanned.
+// That is the case for incomplete label indexes, and for tasks not defining any
+// label pair in Task.Meta other than wildcard values. Note that the returned
+// tasks must still be verified using Task.Has, because the label indexes do
+// only cover Task.Meta.
+func (e *Engine) indexed(tas *task.Task) ([]*task.Task, bool, error) {
+	var err error
+
+	if tas.Meta == nil {
+		return nil, false, nil
+	}
+
+	var ver bool
+	{
+		ver, err = e.migrated(verIndex)
+		if err != nil {
+			return nil, false, tracer.Mask(err)
+		}
+	}
+
+	if !ver {
+		return nil, false, nil
+	}
+
+	var reg []string
+	{
+		reg, err = e.sto.Member(e.idxKey())
+		if err != nil {
+			return nil, false, tracer.Mask(err)
+		}
+	}
+
+	var ids map[string]bool
+	for k, v := range *tas.Meta {
+		// Wildcard values cannot be resolved using the label indexes. The label
+		// pair is verified by the caller using Task.Has instead.
+		if k == "*" || v == "*" {
+			continue
+		}
+
+		// Label keys are matched by substring, which is why we have to look at all
+		// indexed label keys containing the given label key. See matcher.Has for
+		// more information.
+		var key string
+		{
+			key = strings.TrimSuffix(strings.TrimPrefix(k, "*"), "*")
+		}
+
+		uni := map[string]bool{}
+		for _, x := range reg {
+			if len(key) < 3 || !strings.Contains(x, key) {
+				continue
+			}
+
+			var mem []string
+			{
+				mem, err = e.sto.Member(e.idxPair(x, v))
+				if err != nil {
+					return nil, false, tracer.Mask(err)
+				}
+			}
+
+			for _, y := range mem {
+				if ids == nil || ids[y] {
+					uni[y] = true
+				}
+			}
+		}
+
+		{
+			ids = uni
+		}
+
+		if len(ids) == 0 {
+			return nil, true, nil
+		}
+	}
+
+	if ids == nil {
+		return nil, false, nil
+	}
+
+	var oid []objectid.ID
+	for k := range ids {
+		oid = append(oid, objectid.ID(k))
+	}
+
+	sort.Slice(oid, func(i, j int) bool {
+		return oid[i].Float() < oid[j].Float()
+	})
+
+	var lis []*task.Task
+	for _, x := range oid {
+		var jsn string
+		{
+			_, jsn, err = e.lookup(x)
+			if err != nil {
+				return nil, false, tracer.Mask(err)
+			}
+		}
+
+		// The index might reference tasks that got deleted meanwhile.
+		if jsn == "" {
+			continue
+		}
+
+		lis = append(lis, task.FromString(jsn))
+	}
+
+	return lis, true, nil
+}
+
+// idxKey returns the key of the set containing all label keys of all tasks
+// ever indexed within this queue.
+func (e *Engine) idxKey() string {
+	return fmt.Sprintf("%s%sindex", e.Keyfmt(), e.sep)
+}
+
+// idxPair returns the key of the set containing the object IDs of all tasks
+// defining the given label pair in Task.Meta. Label keys and values are quoted
+// in order to prevent ambiguous keys.
+func (e *Engine) idxPair(k string, v string) string {
+	return fmt.Sprintf("%s%sindex%s%q=%q", e.Keyfmt(), e.sep, e.sep, k, v)
+}
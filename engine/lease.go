@@ -0,0 +1,104 @@
+package engine
+
+import (
+	"context"
+	"time"
+
+	"github.com/xh3b4sd/rescue/task"
+	"github.com/xh3b4sd/rescue/ticker"
+	"github.com/xh3b4sd/tracer"
+)
+
+const (
+	// Renew is the fraction of Config.Expiry after which leases renew the
+	// expiry of their tasks. A fraction of 3 means that a lease gets two more
+	// attempts to renew a task, if renewing failed once.
+	Renew = 3
+)
+
+func (e *Engine) Lease(ctx context.Context, tas *task.Task) (context.Context, context.CancelFunc) {
+	ctx, can := context.WithCancelCause(ctx)
+
+	// The caller keeps using the given task, e.g. in order to delete it once it
+	// got processed, which is why we renew the task based on a copy of it.
+	don := make(chan struct{})
+	go func() {
+		defer close(don)
+		e.lease(ctx, can, task.FromString(task.ToString(tas)))
+	}()
+
+	// Stopping the lease waits for any renewal in flight, so that the caller can
+	// delete the task right away, without its stored state changing
+	// concurrently.
+	return ctx, func() {
+		can(context.Canceled)
+		<-don
+	}
+}
+
+// lease renews the expiry of the given task until the given context is done.
+// The context is cancelled with the reason of failing, as soon as ownership of
+// the task got lost, or as soon as renewing the task failed for too long, so
+// that the task expires before the next attempt.
+func (e *Engine) lease(ctx context.Context, can context.CancelCauseFunc, tas *task.Task) {
+	var exp time.Duration
+	var itv time.Duration
+	{
+		exp = e.expiry(tas)
+		itv = exp / Renew
+	}
+
+	var las time.Time
+	{
+		las = e.tim.Extend()
+	}
+
+	for {
+		aft, sto := e.tim.After(itv)
+
+		select {
+		case <-ctx.Done():
+			sto()
+			return
+		case <-aft:
+		}
+
+		err := e.Extend(tas)
+		if IsTaskNotFound(err) || IsTaskOutdated(err) {
+			can(tracer.Mask(err))
+			return
+		} else if err != nil {
+			e.lerror(tracer.Mask(err))
+		} else {
+			las = e.tim.Extend()
+		}
+
+		// The task expires before the next attempt to renew it, which is why the
+		// caller must stop processing right away.
+		if !e.tim.Extend().Add(itv).Before(las.Add(exp)) {
+			can(tracer.Mask(err))
+			return
+		}
+	}
+}
+
+// expiry returns the time window that workers have in order to process the
+// given task once they took ownership of it. Tasks may define their own expiry
+// using task.rescue.io/lease, which is capped at the configured ceiling. All
+// other tasks expire according to the configured default expiry.
+func (e *Engine) expiry(tas *task.Task) time.Duration {
+	if !tas.Core.Exi().Lease() {
+		return e.exp
+	}
+
+	var dur time.Duration
+	{
+		dur = ticker.New(tas.Core.Get().Lease()).Duration()
+	}
+
+	if dur == 0 {
+		return e.exp
+	}
+
+	return min(dur, e.cei)
+}
@@ -0,0 +1,348 @@
+package engine
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/xh3b4sd/logger"
+	"github.com/xh3b4sd/objectid"
+	"github.com/xh3b4sd/rescue/store"
+	"github.com/xh3b4sd/rescue/task"
+	"github.com/xh3b4sd/rescue/timer"
+)
+
+func Test_Engine_Lease_Expiry(t *testing.T) {
+	testCases := []struct {
+		cor *task.Core
+		exp time.Duration
+	}{
+		// Case 000, ensures that the default expiry applies without lease.
+		{
+			cor: &task.Core{},
+			exp: 30 * time.Second,
+		},
+		// Case 001
+		{
+			cor: &task.Core{task.Lease: "5 minutes"},
+			exp: 5 * time.Minute,
+		},
+		// Case 002, ensures that leases are capped at the configured ceiling.
+		{
+			cor: &task.Core{task.Lease: "3 hours"},
+			exp: time.Hour,
+		},
+		// Case 003, ensures that invalid leases fall back to the default expiry.
+		{
+			cor: &task.Core{task.Lease: "foo"},
+			exp: 30 * time.Second,
+		},
+	}
+
+	for i, tc := range testCases {
+		t.Run(fmt.Sprintf("%03d", i), func(t *testing.T) {
+			var e *Engine
+			{
+				e = &Engine{cei: time.Hour, exp: 30 * time.Second}
+			}
+
+			exp := e.expiry(&task.Task{Core: tc.cor})
+			if exp != tc.exp {
+				t.Fatal("expected", tc.exp, "got", exp)
+			}
+		})
+	}
+}
+
+func Test_Engine_Lease_Renew(t *testing.T) {
+	var err error
+
+	var clk *clock
+	var wai *waiter
+	var e *Engine
+	{
+		clk, wai, e = leaEng(store.NewMemory())
+	}
+
+	var tas *task.Task
+	{
+		tas = musTas(t, e)
+	}
+
+	var ctx context.Context
+	{
+		var can context.CancelFunc
+		ctx, can = e.Lease(context.Background(), tas)
+		defer can()
+	}
+
+	// Leases wait for a third of the task's expiry before renewing it.
+	{
+		dur := <-wai.dur
+		if dur != 10*time.Second {
+			t.Fatal("expected", 10*time.Second, "got", dur)
+		}
+	}
+
+	for i := 0; i < 3; i++ {
+		{
+			clk.add(10 * time.Second)
+			wai.tic <- clk.get()
+		}
+
+		// Waiting again implies that the previous renewal completed.
+		{
+			dur := <-wai.dur
+			if dur != 10*time.Second {
+				t.Fatal("expected", 10*time.Second, "got", dur)
+			}
+		}
+
+		{
+			lis, err := e.Lister(All())
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			exp := clk.get().Add(30 * time.Second)
+			if !lis[0].Core.Get().Expiry().Equal(exp) {
+				t.Fatal("expected", exp, "got", lis[0].Core.Get().Expiry())
+			}
+		}
+	}
+
+	{
+		if ctx.Err() != nil {
+			t.Fatal("expected", nil, "got", context.Cause(ctx))
+		}
+	}
+
+	{
+		err = e.Delete(tas)
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+}
+
+func Test_Engine_Lease_Outdated(t *testing.T) {
+	var err error
+
+	var wai *waiter
+	var e *Engine
+	{
+		_, wai, e = leaEng(store.NewMemory())
+	}
+
+	var tas *task.Task
+	{
+		tas = musTas(t, e)
+	}
+
+	var ctx context.Context
+	var can context.CancelFunc
+	{
+		ctx, can = e.Lease(context.Background(), tas)
+		defer can()
+	}
+
+	{
+		<-wai.dur
+	}
+
+	// Releasing the task revokes this worker's ownership, which the lease
+	// notices with its next renewal.
+	{
+		err = e.Release(tas, ReleaseOptions{})
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	{
+		wai.tic <- time.Time{}
+	}
+
+	{
+		<-ctx.Done()
+	}
+
+	{
+		if !IsTaskOutdated(context.Cause(ctx)) {
+			t.Fatal("expected", "taskOutdatedError", "got", context.Cause(ctx))
+		}
+	}
+
+	// Cancelled leases do not wait for any further renewal.
+	select {
+	case dur := <-wai.dur:
+		t.Fatal("expected", "no renewal", "got", dur)
+	default:
+	}
+}
+
+func Test_Engine_Lease_Stop(t *testing.T) {
+	var err error
+
+	var blk *blockUpdate
+	{
+		blk = &blockUpdate{
+			Interface: store.NewMemory(),
+			ent:       make(chan struct{}, 1),
+			rel:       make(chan struct{}),
+		}
+	}
+
+	var wai *waiter
+	var e *Engine
+	{
+		_, wai, e = leaEng(blk)
+	}
+
+	var tas *task.Task
+	{
+		tas = musTas(t, e)
+	}
+
+	var can context.CancelFunc
+	{
+		_, can = e.Lease(context.Background(), tas)
+	}
+
+	{
+		<-wai.dur
+		blk.blk.Store(true)
+		wai.tic <- time.Time{}
+		<-blk.ent
+	}
+
+	var don chan struct{}
+	{
+		don = make(chan struct{})
+	}
+
+	go func() {
+		defer close(don)
+		can()
+	}()
+
+	// Stopping the lease must wait for the renewal in flight.
+	select {
+	case <-don:
+		t.Fatal("expected", "blocked stop", "got", "stopped lease")
+	case <-time.After(50 * time.Millisecond):
+	}
+
+	{
+		blk.blk.Store(false)
+		close(blk.rel)
+		<-don
+	}
+
+	{
+		err = e.Delete(tas)
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+}
+
+// blockUpdate blocks updates once blk is set, until rel gets closed. Every
+// blocked update is announced via ent.
+type blockUpdate struct {
+	store.Interface
+	blk atomic.Bool
+	ent chan struct{}
+	rel chan struct{}
+}
+
+func (b *blockUpdate) Update(key string, oid objectid.ID, cur string, val string) (bool, error) {
+	if b.blk.Load() {
+		b.ent <- struct{}{}
+		<-b.rel
+	}
+
+	return b.Interface.Update(key, oid, cur, val)
+}
+
+// clock provides the current time to the engine under test, which tests may
+// move forward concurrently.
+type clock struct {
+	mut sync.Mutex
+	now time.Time
+}
+
+func (c *clock) add(dur time.Duration) {
+	c.mut.Lock()
+	defer c.mut.Unlock()
+	c.now = c.now.Add(dur)
+}
+
+func (c *clock) get() time.Time {
+	c.mut.Lock()
+	defer c.mut.Unlock()
+	return c.now
+}
+
+// waiter announces every duration that the engine under test waits for via
+// dur, and lets the engine stop waiting once tests send to tic.
+type waiter struct {
+	dur chan time.Duration
+	tic chan time.Time
+}
+
+func (w *waiter) wait(dur time.Duration) (<-chan time.Time, func()) {
+	w.dur <- dur
+	return w.tic, func() {}
+}
+
+func leaEng(sto store.Interface) (*clock, *waiter, *Engine) {
+	var clk *clock
+	{
+		clk = &clock{now: time.Date(2023, 10, 20, 0, 0, 0, 0, time.UTC)}
+	}
+
+	var wai *waiter
+	{
+		wai = &waiter{
+			dur: make(chan time.Duration, 10),
+			tic: make(chan time.Time),
+		}
+	}
+
+	var tim *timer.Timer
+	{
+		tim = timer.New()
+		tim.Setter(clk.get)
+		tim.Waiter(wai.wait)
+	}
+
+	var e *Engine
+	{
+		e = New(Config{
+			Logger: logger.Fake(),
+			Store:  sto,
+			Timer:  tim,
+			Worker: "eon",
+		})
+	}
+
+	return clk, wai, e
+}
+
+func musTas(t *testing.T, e *Engine) *task.Task {
+	err := e.Create(&task.Task{Meta: &task.Meta{"test.api.io/key": "foo"}})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tas, err := e.Search()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return tas
+}
@@ -1,5 +1,9 @@
 package engine
 
 func (e *Engine) Listen() string {
+	if e.red == nil {
+		return ""
+	}
+
 	return e.red.Listen()
 }
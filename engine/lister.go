@@ -32,62 +32,33 @@ func (e *Engine) Lister(tas *task.Task) ([]*task.Task, error) {
 func (e *Engine) lister(tas *task.Task) ([]*task.Task, error) {
 	var err error
 
-	// We verify the given task labels to ensure that no core metadata specific to
-	// the rescue internals are provided. That is to not let arbitrary processes
-	// purposfully list tasks by ID because that ability could be abused to take
-	// ownership from worker processes that may not be aware of the corruption.
-	{
-		if tas == nil {
-			return nil, tracer.Maskf(taskEmptyError, "Task must not be empty")
-		}
-		if tas.Core != nil {
-			return nil, tracer.Maskf(taskCoreError, "Task.Core must be empty")
-		}
-	}
-
-	{
-		if tas.Emp() {
-			return nil, tracer.Maskf(taskMetaEmptyError, "at least one of [Task.Cron Task.Gate Task.Host Task.Meta Task.Root] must be configured")
-		}
-	}
-
 	{
-		if tas.Meta != nil && tas.Meta.Has(Res()) {
-			return nil, tracer.Maskf(labelReservedError, "Task.Meta must not contain reserved scheme rescue.io")
-		}
-		if tas.Root != nil && tas.Root.Has(Res()) {
-			return nil, tracer.Maskf(labelReservedError, "Task.Root must not contain reserved scheme rescue.io")
+		err = verLis(tas)
+		if err != nil {
+			return nil, tracer.Mask(err)
 		}
 	}
 
 	// Listing all existing tasks implies certain read operations on the task
-	// queue. For consistency reasons we need to ensure that only one process at
-	// a time can read information from the queue. Otherwise worker behaviour
-	// would be inconsistent and the integrity of the queue could not be
-	// guaranteed.
+	// queue. Reading all tasks happens atomically within the underlying storage,
+	// which is why we do not need to acquire the global lock here. Tasks are
+	// resolved using the label indexes if possible, and only otherwise by
+	// scanning the whole queue.
+	var idx bool
+	var lis []*task.Task
 	{
-		err := e.loc.Acquire()
+		lis, idx, err = e.indexed(tas)
 		if err != nil {
 			return nil, tracer.Mask(err)
 		}
-
-		defer func() {
-			err := e.loc.Release()
-			if err != nil {
-				e.lerror(tracer.Mask(err))
-			}
-		}()
 	}
 
-	var lis []*task.Task
-	{
-		lis, err = e.searchAll()
+	if !idx {
+		lis, err = e.searchLis()
 		if err != nil {
 			return nil, tracer.Mask(err)
 		}
-	}
 
-	{
 		e.met.Task.Inactive.Set(float64(len(lis)))
 	}
 
@@ -100,3 +71,35 @@ func (e *Engine) lister(tas *task.Task) ([]*task.Task, error) {
 
 	return fil, nil
 }
+
+func verLis(tas *task.Task) error {
+	// We verify the given task labels to ensure that no core metadata specific to
+	// the rescue internals are provided. That is to not let arbitrary processes
+	// purposfully list tasks by ID because that ability could be abused to take
+	// ownership from worker processes that may not be aware of the corruption.
+	{
+		if tas == nil {
+			return tracer.Maskf(taskEmptyError, "Task must not be empty")
+		}
+		if tas.Core != nil {
+			return tracer.Maskf(taskCoreError, "Task.Core must be empty")
+		}
+	}
+
+	{
+		if tas.Emp() {
+			return tracer.Maskf(taskMetaEmptyError, "at least one of [Task.Cron Task.Gate Task.Host Task.Meta Task.Root] must be configured")
+		}
+	}
+
+	{
+		if tas.Meta != nil && tas.Meta.Has(Res()) {
+			return tracer.Maskf(labelReservedError, "Task.Meta must not contain reserved scheme rescue.io")
+		}
+		if tas.Root != nil && tas.Root.Has(Res()) {
+			return tracer.Maskf(labelReservedError, "Task.Root must not contain reserved scheme rescue.io")
+		}
+	}
+
+	return nil
+}
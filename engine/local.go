@@ -1,8 +1,26 @@
 package engine
 
-import "time"
+import (
+	"time"
+
+	"github.com/xh3b4sd/rescue/task"
+)
 
 type local struct {
 	exp time.Time
 	don bool
 }
+
+// tracked returns the local copy of the given task, if the given task defines
+// a delivery method that is tracked within any of the local lookup tables.
+// tracked must only be called while holding the engine's mutex.
+func (e *Engine) tracked(tas *task.Task) *local {
+	switch tas.Node.Get(task.Method) {
+	case task.MthdAll:
+		return e.cac[tas.Core.Get().Object()]
+	case task.MthdMny:
+		return e.mny[tas.Core.Get().Object()]
+	}
+
+	return nil
+}
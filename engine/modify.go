@@ -0,0 +1,54 @@
+package engine
+
+import (
+	"github.com/xh3b4sd/objectid"
+	"github.com/xh3b4sd/rescue/task"
+	"github.com/xh3b4sd/tracer"
+)
+
+// modify applies fun to the most recent state of the task identified by oid
+// and writes the modified task back to the sorted set under key. The write
+// does only succeed if the stored task did not change since it got read.
+// Otherwise the task is read again and fun is applied again, for at most Retry
+// attempts. The returned bool is false if the task does not exist, or if fun
+// returns false, which indicates that there is nothing to modify.
+func (e *Engine) modify(key string, oid objectid.ID, fun func(tas *task.Task) bool) (bool, error) {
+	var err error
+
+	for i := 0; i < Retry; i++ {
+		var jsn string
+		{
+			jsn, err = e.sto.Search(key, oid)
+			if err != nil {
+				return false, tracer.Mask(err)
+			}
+		}
+
+		if jsn == "" {
+			return false, nil
+		}
+
+		var tas *task.Task
+		{
+			tas = task.FromString(jsn)
+		}
+
+		if !fun(tas) {
+			return false, nil
+		}
+
+		var upd bool
+		{
+			upd, err = e.sto.Update(key, oid, jsn, task.ToString(tas))
+			if err != nil {
+				return false, tracer.Mask(err)
+			}
+		}
+
+		if upd {
+			return true, nil
+		}
+	}
+
+	return false, tracer.Maskf(taskOutdatedError, "%s changed concurrently", oid)
+}
@@ -0,0 +1,214 @@
+package engine
+
+import (
+	"strings"
+	"time"
+
+	"github.com/xh3b4sd/objectid"
+	"github.com/xh3b4sd/rescue/task"
+	"github.com/xh3b4sd/tracer"
+)
+
+// multicast returns up to n tasks defining the delivery method "mny" that list
+// this worker as one of their targets, and that this worker did not complete
+// yet. Such tasks are not owned by any worker. Instead, every target worker
+// tracks the tasks that it is processing within its own local lookup table,
+// the same way broadcasted tasks are tracked.
+func (e *Engine) multicast(lis []*task.Task, n int) []*task.Task {
+	e.mut.Lock()
+	defer e.mut.Unlock()
+
+	var now time.Time
+	{
+		now = e.tim.Search()
+	}
+
+	// Forget about all tasks that got removed from the queue meanwhile, e.g.
+	// because every target worker completed them. Tasks that are still being
+	// processed are kept, since the given list may be outdated already.
+	{
+		exi := map[objectid.ID]bool{}
+		for _, x := range lis {
+			exi[x.Core.Get().Object()] = true
+		}
+
+		for k, v := range e.mny {
+			if !exi[k] && (v.don || !v.exp.After(now)) {
+				delete(e.mny, k)
+			}
+		}
+	}
+
+	var mny []*task.Task
+	for _, x := range lis {
+		if len(mny) == n {
+			break
+		}
+
+		// Skip any task that does not define the task delivery method "mny".
+		if x.Node.Get(task.Method) != task.MthdMny {
+			continue
+		}
+
+		// Skip any task that is not addressed to this worker, or that this worker
+		// completed already.
+		if !contains(targets(x), e.wrk) || contains(acknowledged(x), e.wrk) {
+			continue
+		}
+
+		// Skip any task that this worker is not capable of processing.
+		if !e.accepts(x) {
+			continue
+		}
+
+		// Skip any task that missed its deadline.
+		if missed(x, now) {
+			continue
+		}
+
+		var loc *local
+		{
+			loc = e.mny[x.Core.Get().Object()]
+		}
+
+		// Skip any task that we completed locally, or that we are already
+		// processing within its specified time of expiry. The tasks we are
+		// skipping here are either still being processed, or failed, in which
+		// case we will pick them up again after local expiry.
+		if loc != nil && (loc.don || loc.exp.After(now)) {
+			continue
+		}
+
+		{
+			e.mny[x.Core.Get().Object()] = &local{exp: now.Add(e.expiry(x))}
+		}
+
+		mny = append(mny, x)
+	}
+
+	return mny
+}
+
+// deleteMany records that this worker completed the given task defining the
+// delivery method "mny". The task is removed from the queue once every target
+// worker completed it.
+func (e *Engine) deleteMany(tas *task.Task) error {
+	var err error
+
+	var oid objectid.ID
+	{
+		oid = tas.Core.Get().Object()
+	}
+
+	var now time.Time
+	{
+		now = e.tim.Delete()
+	}
+
+	var don bool
+	for i := 0; i < Retry && !don; i++ {
+		var key string
+		var jsn string
+		{
+			key, jsn, err = e.lookup(oid)
+			if err != nil {
+				return tracer.Mask(err)
+			}
+		}
+
+		if jsn == "" {
+			e.met.Task.NotFound.Inc()
+			return tracer.Maskf(taskNotFoundError, "%s", tas.Core.Map().Object())
+		}
+
+		var cur *task.Task
+		{
+			cur = task.FromString(jsn)
+		}
+
+		// Only target workers that did not complete the task yet can complete
+		// it.
+		if !contains(targets(cur), e.wrk) || contains(acknowledged(cur), e.wrk) {
+			e.met.Task.Outdated.Inc()
+			return tracer.Maskf(taskOutdatedError, "%s", tas.Core.Map().Object())
+		}
+
+		var com []string
+		{
+			com = append(acknowledged(cur), e.wrk)
+		}
+
+		// As long as any target worker did not complete the task yet, we only
+		// record our own completion. Other target workers may complete the same
+		// task concurrently, in which case we try again based on the most recent
+		// state of the task.
+		if len(remove(targets(cur), com...)) != 0 {
+			{
+				cur.Core.Set().Complete(strings.Join(com, ","))
+			}
+
+			{
+				don, err = e.sto.Update(key, oid, jsn, task.ToString(cur))
+				if err != nil {
+					return tracer.Mask(err)
+				}
+			}
+
+			continue
+		}
+
+		{
+			don, err = e.sto.Delete(key, oid, jsn)
+			if err != nil {
+				return tracer.Mask(err)
+			}
+		}
+
+		if !don {
+			continue
+		}
+
+		{
+			err = e.deindex(cur)
+			if err != nil {
+				return tracer.Mask(err)
+			}
+		}
+
+		// Completed tasks defining a dedupe key keep preventing duplicates for
+		// the configured dedupe window.
+		{
+			err = e.settle(cur, now)
+			if err != nil {
+				return tracer.Mask(err)
+			}
+		}
+	}
+
+	if !don {
+		e.met.Task.Outdated.Inc()
+		return tracer.Maskf(taskOutdatedError, "%s changed concurrently", oid)
+	}
+
+	// Since this worker did its part in processing the multicasted task, we can
+	// mark this task's local copy as done.
+	{
+		e.mut.Lock()
+		e.mny[oid] = &local{don: true}
+		e.mut.Unlock()
+	}
+
+	return nil
+}
+
+// acknowledged returns the workers that completed the given task defining the
+// delivery method "mny".
+func acknowledged(tas *task.Task) []string {
+	return split(tas.Core.Get().Complete())
+}
+
+// targets returns the workers that the given task defining the delivery method
+// "mny" is addressed to.
+func targets(tas *task.Task) []string {
+	return split(tas.Node.Get(task.Worker))
+}
@@ -0,0 +1,24 @@
+package engine
+
+import (
+	"fmt"
+
+	"github.com/xh3b4sd/tracer"
+)
+
+// notify wakes up all workers blocked in Engine.SearchWait, because tasks
+// might have become claimable. Notifications are best effort, which is why
+// failing to send them is only logged. Workers missing a notification search
+// again after Config.Wakeup at the latest.
+func (e *Engine) notify() {
+	err := e.sto.Notify(e.ntfKey())
+	if err != nil {
+		e.lerror(tracer.Mask(err))
+	}
+}
+
+// ntfKey returns the key that notifications about changes of this queue are
+// sent for.
+func (e *Engine) ntfKey() string {
+	return fmt.Sprintf("%s%snotify", e.Keyfmt(), e.sep)
+}
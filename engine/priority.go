@@ -0,0 +1,42 @@
+package engine
+
+import (
+	"sort"
+	"time"
+
+	"github.com/xh3b4sd/rescue/task"
+)
+
+// priority returns the effective priority of the given task at the given point
+// in time. The effective priority is the priority defined in Task.Core, which
+// grows by 1 for every configured aging interval that passed since the task got
+// created. That way tasks with a low priority do not starve forever behind a
+// steady stream of tasks with a high priority.
+func (e *Engine) priority(tas *task.Task, now time.Time) int64 {
+	var pri int64
+	{
+		pri = tas.Core.Get().Priority()
+	}
+
+	if e.agi > 0 {
+		age := now.Sub(tas.Core.Get().Object().Time())
+		if age > 0 {
+			pri += int64(age / e.agi)
+		}
+	}
+
+	return pri
+}
+
+// order returns the given tasks ordered by their effective priority, highest
+// first. Tasks of the same effective priority remain ordered by their object
+// IDs, so that they are claimed in the order of their creation.
+func (e *Engine) order(lis []*task.Task, now time.Time) []*task.Task {
+	ord := append([]*task.Task{}, lis...)
+
+	sort.SliceStable(ord, func(i, j int) bool {
+		return e.priority(ord[i], now) > e.priority(ord[j], now)
+	})
+
+	return ord
+}
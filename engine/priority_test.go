@@ -0,0 +1,95 @@
+package engine
+
+import (
+	"fmt"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/xh3b4sd/objectid"
+	"github.com/xh3b4sd/rescue/task"
+)
+
+func Test_Engine_Priority_Order(t *testing.T) {
+	testCases := []struct {
+		agi time.Duration
+		pri []int64
+		now string
+		ord []int
+	}{
+		// Case 000, ensures that tasks without priority remain in FIFO order.
+		{
+			pri: []int64{0, 0, 0},
+			now: "2023-10-20T00:10:00Z",
+			ord: []int{0, 1, 2},
+		},
+		// Case 001
+		{
+			pri: []int64{0, 2, 1},
+			now: "2023-10-20T00:10:00Z",
+			ord: []int{1, 2, 0},
+		},
+		// Case 002, ensures FIFO order within the same priority.
+		{
+			pri: []int64{1, -1, 1, 0},
+			now: "2023-10-20T00:10:00Z",
+			ord: []int{0, 2, 3, 1},
+		},
+		// Case 003, ensures that old tasks catch up with younger tasks of higher
+		// priority.
+		{
+			agi: time.Minute,
+			pri: []int64{0, 0, 1},
+			now: "2023-10-20T00:10:00Z",
+			ord: []int{0, 1, 2},
+		},
+		// Case 004
+		{
+			agi: time.Hour,
+			pri: []int64{0, 0, 1},
+			now: "2023-10-20T00:10:00Z",
+			ord: []int{2, 0, 1},
+		},
+	}
+
+	for i, tc := range testCases {
+		t.Run(fmt.Sprintf("%03d", i), func(t *testing.T) {
+			var e *Engine
+			{
+				e = &Engine{agi: tc.agi}
+			}
+
+			var lis []*task.Task
+			for j, x := range tc.pri {
+				tas := &task.Task{Core: &task.Core{}}
+				tas.Core.Set().Object(objectid.Random(objectid.Time(musTim("2023-10-20T00:00:00Z").Add(time.Duration(j) * time.Minute))))
+				tas.Core.Set().Priority(x)
+
+				lis = append(lis, tas)
+			}
+
+			var ord []int
+			for _, x := range e.order(lis, musTim(tc.now)) {
+				for j, y := range lis {
+					if x == y {
+						ord = append(ord, j)
+					}
+				}
+			}
+
+			if !reflect.DeepEqual(ord, tc.ord) {
+				t.Fatalf("\n\n%s\n", cmp.Diff(tc.ord, ord))
+			}
+		})
+	}
+}
+
+func musTim(str string) time.Time {
+	tim, err := time.Parse(time.RFC3339, str)
+	if err != nil {
+		panic(err)
+	}
+
+	return tim
+}
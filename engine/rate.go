@@ -0,0 +1,195 @@
+package engine
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/xh3b4sd/objectid"
+	"github.com/xh3b4sd/rescue/store"
+	"github.com/xh3b4sd/rescue/task"
+	"github.com/xh3b4sd/tracer"
+)
+
+// Rate describes a queue level rule limiting the throughput of tasks being
+// claimed by workers across the whole network, e.g. at most 100 tasks per
+// minute. The rule applies to all tasks matching the given Task.Meta labels.
+// Every rule is a token bucket holding up to Limit tokens, which refills at a
+// rate of Limit tokens per Window. Claiming a matching task takes a token from
+// the bucket. The state of every bucket is kept in the underlying storage, so
+// that all workers of the same queue share it. Every worker of the same queue
+// must be configured with the same rules. Note that tasks defining the delivery
+// method "all" or "mny" are not subject to any rule.
+type Rate struct {
+	// Limit is the maximum amount of matching tasks claimed per Window.
+	Limit int
+	// Meta is the label selector of the tasks that the rule applies to.
+	Meta *task.Meta
+	// Window is the time interval within which Limit tokens get refilled.
+	Window time.Duration
+}
+
+// bucket is the state of a single token bucket, as read at the beginning of a
+// claim.
+type bucket struct {
+	// ele is the stored element of this bucket. Element.Val is only valid once
+	// tokens got taken from this bucket.
+	ele store.Element
+	// now is the point in time that the amount of tokens got refilled for.
+	now time.Time
+	// thr expresses whether any task got throttled by this bucket.
+	thr bool
+	// tok is the amount of tokens available in this bucket.
+	tok float64
+	// use expresses whether any token got taken from this bucket.
+	use bool
+}
+
+// label returns the label selector of this rule in a stable format.
+func (r Rate) label() string {
+	return Concurrency{Meta: r.Meta}.label()
+}
+
+// buckets returns the current state of the token buckets of all configured
+// rate limits, refilled up to the given point in time.
+func (e *Engine) buckets(now time.Time) ([]*bucket, error) {
+	var bkt []*bucket
+
+	for _, x := range e.rte {
+		b, err := e.bucket(x, now)
+		if err != nil {
+			return nil, tracer.Mask(err)
+		}
+
+		bkt = append(bkt, b)
+	}
+
+	return bkt, nil
+}
+
+// bucket returns the current state of the token bucket of the given rate
+// limit, refilled up to the given point in time.
+func (e *Engine) bucket(rte Rate, now time.Time) (*bucket, error) {
+	var err error
+
+	var key string
+	var oid objectid.ID
+	{
+		key = e.rteKey(rte)
+		oid = objectid.System()
+	}
+
+	var jsn string
+	{
+		jsn, err = e.sto.Search(key, oid)
+		if err != nil {
+			return nil, tracer.Mask(err)
+		}
+	}
+
+	// The very first claim of any limited task within this queue creates the
+	// token bucket with all of its tokens available. Workers doing so
+	// concurrently agree on the same token bucket.
+	if jsn == "" {
+		jsn = fmt.Sprintf("%d,%d", rte.Limit, now.UnixNano())
+
+		err = e.sto.Create(key, oid, jsn)
+		if store.IsElementExists(err) {
+			return e.bucket(rte, now)
+		} else if err != nil {
+			return nil, tracer.Mask(err)
+		}
+	}
+
+	var tok float64
+	var tim time.Time
+	{
+		tok, tim, err = parBkt(jsn)
+		if err != nil {
+			return nil, tracer.Mask(err)
+		}
+	}
+
+	if rte.Window > 0 && now.After(tim) {
+		tok += float64(rte.Limit) * float64(now.Sub(tim)) / float64(rte.Window)
+	}
+
+	if tok > float64(rte.Limit) {
+		tok = float64(rte.Limit)
+	}
+
+	// Workers may disagree about the current time. The token bucket must never
+	// go back in time, so that no tokens are refilled twice.
+	if tim.After(now) {
+		now = tim
+	}
+
+	return &bucket{ele: store.Element{Cur: jsn, Key: key, Oid: oid}, now: now, tok: tok}, nil
+}
+
+// permit expresses whether the given task can be claimed without exceeding any
+// of the configured rate limits. Every token bucket throttling any task is
+// counted once per claim, no matter how many tasks it throttled.
+func (e *Engine) permit(tas *task.Task, bkt []*bucket) bool {
+	for i, x := range e.rte {
+		if !limits(x.Meta, tas) || bkt[i].tok >= 1 {
+			continue
+		}
+
+		if !bkt[i].thr {
+			e.met.Task.Throttled.Inc()
+			bkt[i].thr = true
+		}
+
+		return false
+	}
+
+	return true
+}
+
+// consume takes a token for the given task from every token bucket whose rate
+// limit applies to the given task.
+func (e *Engine) consume(tas *task.Task, bkt []*bucket) {
+	for i, x := range e.rte {
+		if !limits(x.Meta, tas) {
+			continue
+		}
+
+		{
+			bkt[i].tok--
+			bkt[i].use = true
+		}
+
+		{
+			bkt[i].ele.Val = fmt.Sprintf("%s,%d", strconv.FormatFloat(bkt[i].tok, 'f', -1, 64), bkt[i].now.UnixNano())
+		}
+	}
+}
+
+// rteKey returns the key of the sorted set containing the token bucket of the
+// given rate limit. The key is derived from the rule's full label selector, so
+// that the order of rules does not matter, and so that different rules never
+// share the same token bucket.
+func (e *Engine) rteKey(rte Rate) string {
+	return fmt.Sprintf("%s%srate%s%q", e.Keyfmt(), e.sep, e.sep, rte.label())
+}
+
+// parBkt parses the stored state of a token bucket, which is the amount of
+// available tokens and the unix nano timestamp of the last update, separated
+// by a comma.
+func parBkt(str string) (float64, time.Time, error) {
+	lhs, rhs, _ := strings.Cut(str, ",")
+
+	tok, err := strconv.ParseFloat(lhs, 64)
+	if err != nil {
+		return 0, time.Time{}, tracer.Mask(err)
+	}
+
+	nan, err := strconv.ParseInt(rhs, 10, 64)
+	if err != nil {
+		return 0, time.Time{}, tracer.Mask(err)
+	}
+
+	return tok, time.Unix(0, nan).UTC(), nil
+}
@@ -0,0 +1,425 @@
+package engine
+
+import (
+	"encoding/json"
+	"fmt"
+	"hash/fnv"
+	"sort"
+	"strconv"
+	"time"
+
+	"github.com/xh3b4sd/objectid"
+	"github.com/xh3b4sd/rescue/store"
+	"github.com/xh3b4sd/rescue/task"
+	"github.com/xh3b4sd/tracer"
+)
+
+// Member is the record that every worker keeps within the worker registry of
+// its queue by sending heartbeats using Engine.Heartbeat.
+type Member struct {
+	// Capability are the optional Task.Meta selectors of all tasks that the
+	// worker is capable of processing.
+	Capability []*task.Meta `json:"capability,omitempty"`
+	// Heartbeat is the point in time of the most recent heartbeat.
+	Heartbeat time.Time `json:"heartbeat"`
+	// Labels are the optional labels that the worker got configured with.
+	Labels map[string]string `json:"labels,omitempty"`
+	// Progress is the point in time after which the worker processes
+	// broadcasted tasks defining the delivery method "all". Workers restarting
+	// with the same identifier resume processing broadcasted tasks from here.
+	Progress time.Time `json:"progress"`
+	// Start is the point in time at which the worker became operational.
+	Start time.Time `json:"start"`
+	// Worker is the identifier of the worker process.
+	Worker string `json:"worker"`
+}
+
+func (e *Engine) Heartbeat() error {
+	var err error
+
+	e.met.Engine.Heartbeat.Cal.Inc()
+
+	o := func() error {
+		err = e.heartbeat()
+		if err != nil {
+			return tracer.Mask(err)
+		}
+
+		return nil
+	}
+
+	err = e.met.Engine.Heartbeat.Dur.Sin(o)
+	if err != nil {
+		e.met.Engine.Heartbeat.Err.Inc()
+		return tracer.Mask(err)
+	}
+
+	return nil
+}
+
+func (e *Engine) Registry() ([]*Member, error) {
+	var err error
+	var lis []*Member
+
+	e.met.Engine.Registry.Cal.Inc()
+
+	o := func() error {
+		lis, err = e.registry()
+		if err != nil {
+			return tracer.Mask(err)
+		}
+
+		return nil
+	}
+
+	err = e.met.Engine.Registry.Dur.Sin(o)
+	if err != nil {
+		e.met.Engine.Registry.Err.Inc()
+		return nil, tracer.Mask(err)
+	}
+
+	return lis, nil
+}
+
+func (e *Engine) heartbeat() error {
+	var err error
+
+	var oid objectid.ID
+	{
+		oid = member(e.wrk)
+	}
+
+	// Every worker is the only one writing its own record, but other workers may
+	// remove it concurrently once it got stale. So we simply try again until our
+	// record got written.
+	for i := 0; i < Retry; i++ {
+		var cur string
+		{
+			cur, err = e.sto.Search(e.regKey(), oid)
+			if err != nil {
+				return tracer.Mask(err)
+			}
+		}
+
+		{
+			err = e.recover(cur)
+			if err != nil {
+				return tracer.Mask(err)
+			}
+		}
+
+		var val string
+		{
+			val, err = e.profile()
+			if err != nil {
+				return tracer.Mask(err)
+			}
+		}
+
+		if cur == "" {
+			err = e.sto.Create(e.regKey(), oid, val)
+			if store.IsElementExists(err) {
+				continue
+			} else if err != nil {
+				return tracer.Mask(err)
+			}
+
+			return nil
+		}
+
+		var upd bool
+		{
+			upd, err = e.sto.Update(e.regKey(), oid, cur, val)
+			if err != nil {
+				return tracer.Mask(err)
+			}
+		}
+
+		if upd {
+			return nil
+		}
+	}
+
+	return tracer.Maskf(taskOutdatedError, "%s changed concurrently", oid)
+}
+
+func (e *Engine) registry() ([]*Member, error) {
+	var err error
+
+	var lis []*Member
+	{
+		lis, err = e.members()
+		if err != nil {
+			return nil, tracer.Mask(err)
+		}
+	}
+
+	var now time.Time
+	{
+		now = e.tim.Heartbeat()
+	}
+
+	var liv []*Member
+	for _, x := range lis {
+		if e.alive(x, now) {
+			liv = append(liv, x)
+		}
+	}
+
+	sort.Slice(liv, func(i, j int) bool {
+		return liv[i].Worker < liv[j].Worker
+	})
+
+	return liv, nil
+}
+
+// profile returns the current record of this worker within the worker
+// registry.
+func (e *Engine) profile() (string, error) {
+	e.mut.Lock()
+	defer e.mut.Unlock()
+
+	var mem *Member
+	{
+		mem = &Member{
+			Capability: e.cpb,
+			Heartbeat:  e.tim.Heartbeat(),
+			Labels:     e.lab,
+			Progress:   e.pnt,
+			Start:      e.sta,
+			Worker:     e.wrk,
+		}
+	}
+
+	byt, err := json.Marshal(mem)
+	if err != nil {
+		return "", tracer.Mask(err)
+	}
+
+	return string(byt), nil
+}
+
+// resume recovers the progress of this worker from the worker registry, unless
+// it got recovered already. resume is called before claiming tasks, so that
+// workers restarting with the same identifier do not depend on sending their
+// first heartbeat before processing broadcasted tasks again.
+func (e *Engine) resume() error {
+	var rec bool
+	{
+		e.mut.Lock()
+		rec = e.rec
+		e.mut.Unlock()
+	}
+
+	if rec {
+		return nil
+	}
+
+	cur, err := e.sto.Search(e.regKey(), member(e.wrk))
+	if err != nil {
+		return tracer.Mask(err)
+	}
+
+	err = e.recover(cur)
+	if err != nil {
+		return tracer.Mask(err)
+	}
+
+	return nil
+}
+
+// recover resumes processing broadcasted tasks from the progress that the
+// given record of this worker within the worker registry defines, if any. The
+// given record may have been written by a previous process using the same
+// worker identifier, e.g. before a restart. Broadcasted tasks that such a
+// previous process completed already are not processed again, because every
+// completion is recorded within a set kept next to the task. Records of other
+// workers, whose identifiers map to the same object ID, are ignored. recover
+// only applies the very first record that this process reads.
+func (e *Engine) recover(cur string) error {
+	e.mut.Lock()
+	defer e.mut.Unlock()
+
+	if e.rec {
+		return nil
+	}
+
+	if cur != "" {
+		var mem Member
+
+		err := json.Unmarshal([]byte(cur), &mem)
+		if err != nil {
+			return tracer.Mask(err)
+		}
+
+		if mem.Worker == e.wrk && !mem.Progress.IsZero() && mem.Progress.Before(e.pnt) {
+			e.pnt = mem.Progress
+		}
+	}
+
+	{
+		e.rec = true
+	}
+
+	return nil
+}
+
+// alive expresses whether the given worker sent its most recent heartbeat
+// within the configured heartbeat timeout.
+func (e *Engine) alive(mem *Member, now time.Time) bool {
+	return mem.Heartbeat.Add(e.hrt).After(now)
+}
+
+// members returns the records of all workers within the worker registry, may
+// they be alive or not.
+func (e *Engine) members() ([]*Member, error) {
+	var err error
+
+	var str []string
+	{
+		str, err = e.sto.Lister(e.regKey())
+		if err != nil {
+			return nil, tracer.Mask(err)
+		}
+	}
+
+	var lis []*Member
+	for _, x := range str {
+		var mem Member
+
+		err = json.Unmarshal([]byte(x), &mem)
+		if err != nil {
+			return nil, tracer.Mask(err)
+		}
+
+		lis = append(lis, &mem)
+	}
+
+	return lis, nil
+}
+
+// workers returns the workers that the given current distribution of tasks
+// has to be balanced between. Workers that owned tasks according to the given
+// distribution are part of it, unless the worker registry knows about them
+// being dead. Workers alive according to the worker registry are part of it as
+// well, even if they do not own any task, but only if they could own any of
+// the given tasks. Otherwise they would be allotted tasks that they never
+// claim. This worker is always part of it.
+func (e *Engine) workers(cur map[string]int, lis []*task.Task) ([]string, error) {
+	var err error
+
+	var mem []*Member
+	{
+		mem, err = e.members()
+		if err != nil {
+			return nil, tracer.Mask(err)
+		}
+	}
+
+	var now time.Time
+	{
+		now = e.tim.Heartbeat()
+	}
+
+	ded := map[string]bool{}
+	liv := map[string]*Member{}
+	for _, x := range mem {
+		if e.alive(x, now) {
+			liv[x.Worker] = x
+		} else {
+			ded[x.Worker] = true
+		}
+	}
+
+	var wrk []string
+	for _, x := range keys(cur) {
+		if !ded[x] {
+			wrk = append(wrk, x)
+		}
+	}
+
+	for k, v := range liv {
+		if !contains(wrk, k) && claimable(v, lis) {
+			wrk = append(wrk, k)
+		}
+	}
+
+	return ensure(wrk, e.wrk), nil
+}
+
+// claimable expresses whether the given worker could own any of the given
+// tasks, given their delivery methods and the worker's capabilities. Tasks
+// defining the delivery method "uni" can only be owned by their target worker.
+// Tasks defining the delivery method "all" or "mny" are never owned by any
+// worker.
+func claimable(mem *Member, lis []*task.Task) bool {
+	for _, x := range lis {
+		if !supports(mem.Capability, x) {
+			continue
+		}
+
+		if x.Node.Get(task.Method) == task.MthdAny {
+			return true
+		}
+
+		if x.Node.Get(task.Method) == task.MthdUni && x.Node.Get(task.Worker) == mem.Worker {
+			return true
+		}
+	}
+
+	return false
+}
+
+// forget removes the records of all workers that did not send any heartbeat
+// within the configured retention period. forget must only be called while
+// holding the global lock.
+func (e *Engine) forget(now time.Time) error {
+	var err error
+
+	var str []string
+	{
+		str, err = e.sto.Lister(e.regKey())
+		if err != nil {
+			return tracer.Mask(err)
+		}
+	}
+
+	for _, x := range str {
+		var mem Member
+
+		err = json.Unmarshal([]byte(x), &mem)
+		if err != nil {
+			return tracer.Mask(err)
+		}
+
+		if now.Sub(mem.Heartbeat) <= e.cln {
+			continue
+		}
+
+		{
+			_, err = e.sto.Delete(e.regKey(), member(mem.Worker), x)
+			if err != nil {
+				return tracer.Mask(err)
+			}
+		}
+	}
+
+	return nil
+}
+
+// regKey returns the key of the sorted set containing the records of all
+// workers that sent heartbeats for this queue.
+func (e *Engine) regKey() string {
+	return fmt.Sprintf("%s%sregistry", e.Keyfmt(), e.sep)
+}
+
+// member returns the object ID identifying the record of the given worker
+// within the worker registry. The object ID is derived from the worker's
+// identifier, and limited to 53 bits, so that it can be represented exactly
+// as the score of a sorted set.
+func member(wrk string) objectid.ID {
+	h := fnv.New64a()
+	h.Write([]byte(wrk))
+
+	return objectid.ID(strconv.FormatUint(h.Sum64()>>11+1, 10))
+}
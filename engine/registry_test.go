@@ -0,0 +1,68 @@
+package engine
+
+import (
+	"encoding/json"
+	"fmt"
+	"testing"
+	"time"
+)
+
+func Test_Engine_Registry_Recover(t *testing.T) {
+	var pnt time.Time
+	{
+		pnt = time.Date(2023, 10, 20, 0, 0, 0, 0, time.UTC)
+	}
+
+	testCases := []struct {
+		mem *Member
+		pnt time.Time
+	}{
+		// Case 000, ensures that the progress of this worker gets recovered.
+		{
+			mem: &Member{Progress: pnt.Add(-time.Minute), Worker: "eon"},
+			pnt: pnt.Add(-time.Minute),
+		},
+		// Case 001, ensures that progress is never moved forward.
+		{
+			mem: &Member{Progress: pnt.Add(time.Minute), Worker: "eon"},
+			pnt: pnt,
+		},
+		// Case 002, ensures that records without progress are ignored.
+		{
+			mem: &Member{Worker: "eon"},
+			pnt: pnt,
+		},
+		// Case 003, ensures that records of other workers, whose identifiers map
+		// to the same object ID, are ignored.
+		{
+			mem: &Member{Progress: pnt.Add(-time.Minute), Worker: "etw"},
+			pnt: pnt,
+		},
+	}
+
+	for i, tc := range testCases {
+		t.Run(fmt.Sprintf("%03d", i), func(t *testing.T) {
+			var e *Engine
+			{
+				e = &Engine{pnt: pnt, wrk: "eon"}
+			}
+
+			byt, err := json.Marshal(tc.mem)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			err = e.recover(string(byt))
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if !e.pnt.Equal(tc.pnt) {
+				t.Fatal("expected", tc.pnt, "got", e.pnt)
+			}
+			if !e.rec {
+				t.Fatal("expected", true, "got", false)
+			}
+		})
+	}
+}
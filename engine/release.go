@@ -0,0 +1,178 @@
+package engine
+
+import (
+	"time"
+
+	"github.com/xh3b4sd/rescue/task"
+	"github.com/xh3b4sd/rescue/ticker"
+	"github.com/xh3b4sd/tracer"
+)
+
+// ReleaseOptions describes how a task is handed back to the queue using
+// Engine.Release.
+type ReleaseOptions struct {
+	// Defer is the optional time interval expression, e.g. "1 minute", after
+	// which the released task may be claimed again. See task.Adefer for the
+	// supported format. The retry delay only applies to the next execution.
+	Defer string
+	// Reason is the optional failure reason recorded in Task.Core, so that the
+	// next owner of the task can learn why the previous attempt did not
+	// succeed.
+	Reason string
+}
+
+func (e *Engine) Release(tas *task.Task, opt ReleaseOptions) error {
+	var err error
+
+	e.met.Engine.Release.Cal.Inc()
+
+	o := func() error {
+		err = e.release(tas, opt)
+		if err != nil {
+			return tracer.Mask(err)
+		}
+
+		return nil
+	}
+
+	err = e.met.Engine.Release.Dur.Sin(o)
+	if err != nil {
+		e.met.Engine.Release.Err.Inc()
+		return tracer.Mask(err)
+	}
+
+	return nil
+}
+
+func (e *Engine) release(tas *task.Task, opt ReleaseOptions) error {
+	var err error
+
+	{
+		if tas == nil {
+			return tracer.Maskf(taskEmptyError, "Task must not be empty")
+		}
+		if tas.Core.Emp() {
+			return tracer.Maskf(taskCoreError, "Task.Core must not be empty")
+		}
+	}
+
+	var now time.Time
+	{
+		now = e.tim.Release()
+	}
+
+	// Released tasks may only be claimed again once the given retry delay
+	// passed. We reuse the mechanism of deferred tasks for that purpose, which
+	// defines tick+1 as the earliest point in time of the next execution.
+	var tp1 time.Time
+	if opt.Defer != "" {
+		var dur time.Duration
+		{
+			dur = ticker.New(opt.Defer, now).Duration()
+		}
+
+		if dur == 0 {
+			return tracer.Maskf(taskCronError, "ReleaseOptions.Defer format must be valid, got %q", opt.Defer)
+		}
+
+		{
+			tp1 = now.Add(dur)
+		}
+	}
+
+	// Broadcasted tasks are not owned by any worker within the underlying
+	// system. They are only released locally.
+	if e.releaseLocal(tas, now, tp1) {
+		return nil
+	}
+
+	// Releasing tasks implies certain write operations on the task queue such
+	// as removing the owner information. Workers do not acquire the global lock
+	// for doing so. Instead, the task is only released if the stored task is
+	// still owned by the calling worker.
+	fun := func(t *task.Task) bool {
+		if !t.Core.Exi().Worker() || t.Core.Get().Worker() != tas.Core.Get().Worker() {
+			return false
+		}
+
+		{
+			t.Core.Prg().Expiry()
+			t.Core.Prg().Worker()
+			t.Core.Set().Cycles(t.Core.Get().Cycles() + 1)
+		}
+
+		if opt.Reason != "" {
+			t.Core.Set().Reason(opt.Reason)
+		}
+
+		// Released tasks without explicit retry delay are retried according to
+		// the configured backoff policy, if any.
+		if tp1.IsZero() {
+			e.backoff(t, now)
+		}
+
+		// Only tick+1 is set, without @defer, so that the retry delay only
+		// applies to the next execution, and so that released tasks do not turn
+		// into deferred tasks.
+		if !tp1.IsZero() {
+			if t.Cron == nil {
+				t.Cron = &task.Cron{}
+			}
+
+			{
+				t.Cron.Set().TickP1(tp1)
+			}
+		}
+
+		return true
+	}
+
+	var upd bool
+	{
+		k := e.Keyfmt()
+		o := tas.Core.Get().Object()
+
+		upd, err = e.modify(k, o, fun)
+		if err != nil {
+			return tracer.Mask(err)
+		}
+	}
+
+	if !upd {
+		e.met.Task.Outdated.Inc()
+		return tracer.Maskf(taskOutdatedError, "%s", tas.Core.Map().Object())
+	}
+
+	// Released tasks can be claimed again right away, unless they got deferred.
+	if tp1.IsZero() && e.bck.Base == 0 {
+		e.notify()
+	}
+
+	return nil
+}
+
+// releaseLocal allows the local release of any broadcasted or multicasted task
+// that this worker is currently processing, so that it can be processed again
+// once the given point in time passed, or right away if tp1 is zero. The
+// returned bool indicates whether the given task got released locally.
+func (e *Engine) releaseLocal(tas *task.Task, now time.Time, tp1 time.Time) bool {
+	e.mut.Lock()
+	defer e.mut.Unlock()
+
+	var loc *local
+	{
+		loc = e.tracked(tas)
+	}
+
+	if loc == nil || loc.don {
+		return false
+	}
+
+	if tp1.IsZero() {
+		loc.exp = now
+	} else {
+		loc.exp = tp1
+	}
+
+	return true
+}
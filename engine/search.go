@@ -1,8 +1,10 @@
 package engine
 
 import (
+	"context"
 	"time"
 
+	"github.com/xh3b4sd/rescue/store"
 	"github.com/xh3b4sd/rescue/task"
 	"github.com/xh3b4sd/tracer"
 )
@@ -31,126 +33,209 @@ func (e *Engine) Search() (*task.Task, error) {
 	return tas, nil
 }
 
-func (e *Engine) search() (*task.Task, error) {
+func (e *Engine) SearchN(n int) ([]*task.Task, error) {
 	var err error
+	var lis []*task.Task
 
-	// Searching for new tasks implies certain write operations on the task
-	// queue such as updating the owner information. Due to such write
-	// operations we need to ensure that only one process at a time can write
-	// information back to the queue. Otherwise worker behaviour would be
-	// inconsistent and the integrity of the queue could not be guaranteed.
-	{
-		err := e.loc.Acquire()
+	e.met.Engine.SearchN.Cal.Inc()
+
+	o := func() error {
+		lis, err = e.searchN(n)
 		if err != nil {
-			return nil, tracer.Mask(err)
+			return tracer.Mask(err)
 		}
 
-		defer func() {
-			err := e.loc.Release()
-			if err != nil {
-				e.lerror(tracer.Mask(err))
-			}
-		}()
+		return nil
 	}
 
-	var lis []*task.Task
-	{
-		lis, err = e.searchAll()
+	err = e.met.Engine.SearchN.Dur.Sin(o)
+	if err != nil {
+		e.met.Engine.SearchN.Err.Inc()
+		return nil, tracer.Mask(err)
+	}
+
+	return lis, nil
+}
+
+func (e *Engine) SearchWait(ctx context.Context) (*task.Task, error) {
+	var err error
+	var tas *task.Task
+
+	e.met.Engine.SearchWait.Cal.Inc()
+
+	o := func() error {
+		tas, err = e.searchWait(ctx)
 		if err != nil {
-			return nil, tracer.Mask(err)
+			return tracer.Mask(err)
 		}
+
+		return nil
 	}
 
-	{
-		e.met.Task.Inactive.Set(float64(len(lis)))
+	err = e.met.Engine.SearchWait.Dur.Sin(o)
+	if err != nil {
+		e.met.Engine.SearchWait.Err.Inc()
+		return nil, tracer.Mask(err)
 	}
 
-	if len(lis) == 0 {
-		e.met.Task.NotFound.Inc()
-		return nil, tracer.Mask(taskNotFoundError)
+	return tas, nil
+}
+
+func (e *Engine) search() (*task.Task, error) {
+	lis, err := e.searchN(1)
+	if err != nil {
+		return nil, tracer.Mask(err)
 	}
 
-	// Search for any task that defines the task delivery method "all". Such tasks
-	// are meant to be processed by every worker within the network. We prioritize
-	// such tasks and return them first, if we find them.
-	for _, x := range lis {
-		// Skip all scheduled task templates for further processing. Any task
-		// template defining Task.Cron is meant to trigger time based task
-		// scheduling for child tasks originating from that template. The template
-		// itself is not meant to be processed by workers.
-		if x.Cron != nil {
-			continue
+	return lis[0], nil
+}
+
+func (e *Engine) searchN(n int) ([]*task.Task, error) {
+	if n < 1 {
+		return nil, tracer.Maskf(searchCountError, "n must be positive, got %d", n)
+	}
+
+	{
+		err := e.resume()
+		if err != nil {
+			return nil, tracer.Mask(err)
 		}
+	}
 
-		// Skip all trigger task templates for further processing. Any task
-		// template defining Task.Gate is meant to trigger event based task
-		// scheduling for child tasks originating from that template. The template
-		// itself is not meant to be processed by workers. Note that we are checking
-		// whether Task.Gate has not any value "trigger", which means that if
-		// Task.Gate is not empty, then its values can only either be "waiting" or
-		// "deleted", which defines the trigger templates. Scheduled tasks defining
-		// any value "trigger" in Task.Gate are the very tasks that workers should
-		// process, because completion of those processed trigger tasks is what
-		// causes the trigger template to create the gated task that is being onhold
-		// until all triggers completed.
-		if x.Gate != nil && !x.Gate.Has(Tri()) {
-			continue
+	// Searching for new tasks implies certain write operations on the task
+	// queue such as updating the owner information. Workers do not acquire the
+	// global lock for doing so. Instead, ownership is claimed using
+	// compare-and-update, which guarantees that only one worker can claim any
+	// given task. Any worker losing the race for a task searches again based on
+	// the most recent state of the queue.
+	for i := 0; i < Retry; i++ {
+		lis, err := e.claim(n)
+		if err != nil {
+			return nil, tracer.Mask(err)
 		}
 
-		// Skip any task that does not define the task delivery method "all".
-		if x.Node.Get(task.Method) != task.MthdAll {
-			continue
+		if len(lis) != 0 {
+			return lis, nil
 		}
+	}
 
-		var loc *local
-		{
-			loc = e.cac[x.Core.Get().Object()]
+	e.met.Task.NotFound.Inc()
+	return nil, tracer.Mask(taskNotFoundError)
+}
+
+// searchWait searches for a task until one got claimed, or until the given
+// context is done. Workers are woken up as soon as tasks might have become
+// claimable, e.g. because new tasks got created, or because task ownership
+// expired. The queue is searched again after Config.Wakeup at the latest, in
+// case any notification got lost.
+func (e *Engine) searchWait(ctx context.Context) (*task.Task, error) {
+	// We start watching for notifications before searching, so that we cannot
+	// miss any change of the queue happening between searching and waiting.
+	ntf, cls, err := e.sto.Watch(e.ntfKey())
+	if err != nil {
+		return nil, tracer.Mask(err)
+	}
+
+	defer cls()
+
+	for {
+		tas, err := e.search()
+		if err == nil {
+			return tas, nil
+		} else if !IsTaskNotFound(err) {
+			return nil, tracer.Mask(err)
 		}
 
-		// Skip any task from our local copy that we already processed.
-		if loc != nil && loc.don {
-			continue
+		tim := time.NewTimer(e.wak)
+
+		select {
+		case <-ctx.Done():
+			tim.Stop()
+			return nil, tracer.Mask(ctx.Err())
+		case <-ntf:
+			tim.Stop()
+		case <-tim.C:
 		}
+	}
+}
 
-		// Derive this task's creation timestamp from its object ID.
-		var tim time.Time
-		{
-			tim = x.Core.Get().Object().Time()
+// claim tries to assign ownership of up to n tasks to this worker. The
+// returned list is empty without error, if any of the chosen tasks got
+// modified concurrently, e.g. because another worker claimed it first. Either
+// all of the chosen tasks get claimed, or none of them.
+func (e *Engine) claim(n int) ([]*task.Task, error) {
+	var err error
+
+	// The version of all claims subject to concurrency rules must be read
+	// before the task queue, so that the in-flight counts below cannot be older
+	// than the version that our claim is based on.
+	var ver store.Element
+	if len(e.con) != 0 {
+		ver, err = e.version()
+		if err != nil {
+			return nil, tracer.Mask(err)
 		}
+	}
 
-		// Skip any task that got created before this worker started to participate
-		// within the network. Engine.pnt is the earliest point in time at which the
-		// worker process came online, or the latest point in time of having
-		// processed the oldest task broadcasted througout the network. If that
-		// pointer is equal to, or after the creation time of the current task that
-		// we do not track in our local cache already, then our rule is to not
-		// process it. And so we skip the task that got created before the current
-		// worker came online, and move on to the next task.
-		if loc == nil && !e.pnt.Before(tim) {
-			continue
+	// The token buckets of all rate limits are read up front as well, since
+	// claiming limited tasks takes tokens from them within the same atomic write
+	// operation that assigns task ownership.
+	var bkt []*bucket
+	if len(e.rte) != 0 {
+		bkt, err = e.buckets(e.tim.Search())
+		if err != nil {
+			return nil, tracer.Mask(err)
 		}
+	}
 
-		var now time.Time
-		{
-			now = e.tim.Search()
+	var lis []*task.Task
+	{
+		lis, err = e.searchAll()
+		if err != nil {
+			return nil, tracer.Mask(err)
 		}
+	}
 
-		// Skip any task that we are already processing within its specified time of
-		// expiry. The tasks we are skipping here are either still being processed,
-		// or failed, in which case we will pick them up again after local expiry.
-		if loc != nil && loc.exp.After(now) {
-			continue
+	{
+		e.met.Task.Inactive.Set(float64(len(lis)))
+	}
+
+	// Count the owned tasks matching every concurrency rule, before any task
+	// gets filtered below. Tasks may still be in flight even though they would
+	// not be claimable anymore, e.g. once they missed their deadline.
+	var inf []int
+	{
+		inf = e.inflight(lis)
+	}
+
+	if len(lis) == 0 {
+		e.met.Task.NotFound.Inc()
+		return nil, tracer.Mask(taskNotFoundError)
+	}
+
+	// Search for any task that defines the task delivery method "all". Such tasks
+	// are meant to be processed by every worker within the network. We prioritize
+	// such tasks and return them first, if we find them.
+	{
+		all, err := e.broadcast(lis, n)
+		if err != nil {
+			return nil, tracer.Mask(err)
 		}
 
-		// Remember the broadcasted task that this worker is processing right now
-		// without assigning worker ownership within the underlying system. Also
-		// remember the current expiry of this broadcasted task, so that we can
-		// expire it locally and retry if necessary.
-		{
-			e.cac[x.Core.Get().Object()] = &local{exp: now.Add(e.exp)}
+		if len(all) != 0 {
+			return all, nil
 		}
+	}
 
-		return x, nil
+	// Search for any task that defines the task delivery method "mny" and that
+	// is addressed to this worker. Such tasks are meant to be processed by every
+	// one of their target workers, which is why we return them the same way as
+	// broadcasted tasks.
+	{
+		mny := e.multicast(lis, n)
+		if len(mny) != 0 {
+			return mny, nil
+		}
 	}
 
 	// Filter all tasks that have Task.Cron, Task.Gate or Task.Root defined.
@@ -164,14 +249,15 @@ func (e *Engine) search() (*task.Task, error) {
 		// Remove all tasks with an active circuit breaker. Any task hitting their
 		// defined maximum amount of execution attempts is effectively on hold until
 		// their cycles counts are reset.
-		if x.Core.Exi().Cancel() && x.Core.Get().Cycles() >= x.Core.Get().Cancel() {
+		if halted(x) {
 			rem = append(rem, i)
 			continue
 		}
 
-		// Remove all broadcasted tasks for further processing. Any task defining
-		// delivery method "all" must have been addressed already above.
-		if x.Node.Get(task.Method) == task.MthdAll {
+		// Remove all broadcasted and multicasted tasks for further processing. Any
+		// task defining delivery method "all" or "mny" must have been addressed
+		// already above.
+		if x.Node.Get(task.Method) == task.MthdAll || x.Node.Get(task.Method) == task.MthdMny {
 			rem = append(rem, i)
 			continue
 		}
@@ -202,8 +288,18 @@ func (e *Engine) search() (*task.Task, error) {
 		// Remove all deferred tasks that define their next execution to be in the
 		// future. Any task defining Task.Cron and tick+1 is meant to be executed
 		// after the specified time. So if the specified time is pointing to the
-		// future still, we ignore it here.
-		if x.Cron != nil && x.Cron.Exi().Adefer() && x.Cron.Exi().TickP1() && x.Cron.Get().TickP1().After(now) {
+		// future still, we ignore it here. Note that tasks retried according to
+		// the configured backoff policy, or the retry delay requested by their
+		// previous owner, define tick+1 without @defer.
+		if x.Cron != nil && x.Cron.Exi().TickP1() && x.Cron.Get().TickP1().After(now) {
+			rem = append(rem, i)
+			continue
+		}
+
+		// Remove all tasks that missed their deadline. Those tasks are not worth
+		// being processed anymore and are removed from the queue by
+		// Engine.Expire.
+		if missed(x, now) {
 			rem = append(rem, i)
 			continue
 		}
@@ -255,16 +351,22 @@ func (e *Engine) search() (*task.Task, error) {
 			// that is represented by task y.
 			{
 				k := e.Keyfmt()
-				s := x.Core.Get().Object().Float()
+				o := x.Core.Get().Object()
+				v := task.ToString(x)
 
-				err = e.red.Sorted().Delete().Score(k, s)
+				del, err := e.sto.Delete(k, o, v)
 				if err != nil {
 					return nil, tracer.Mask(err)
 				}
-			}
 
-			{
-				e.met.Task.Obsolete.Inc()
+				if del {
+					err = e.deindex(x)
+					if err != nil {
+						return nil, tracer.Mask(err)
+					}
+
+					e.met.Task.Obsolete.Inc()
+				}
 			}
 
 			{
@@ -284,24 +386,40 @@ func (e *Engine) search() (*task.Task, error) {
 		lis = lis[:len(lis)-1]
 	}
 
+	// Only look at the tasks that this worker is capable of processing, if
+	// capabilities are configured. That way the balancer below only accounts
+	// for the workers owning tasks that this worker could process as well, and
+	// for the live workers capable of processing any of those tasks.
+	{
+		lis = e.capable(lis)
+	}
+
 	if len(lis) == 0 {
 		e.met.Task.NotFound.Inc()
 		return nil, tracer.Mask(taskNotFoundError)
 	}
 
 	// Calculate the balanced ownership that workers can claim.
+	var dev int
 	cur := map[string]int{}
 	{
 		for _, l := range lis {
 			cur[l.Core.Get().Worker()]++
 		}
 
+		var wrk []string
+		{
+			wrk, err = e.workers(cur, lis)
+			if err != nil {
+				return nil, tracer.Mask(err)
+			}
+		}
+
 		var des map[string]int
 		{
-			des = e.bal.Opt(ensure(keys(cur), e.wrk), sum(cur))
+			des = e.bal.Opt(wrk, sum(cur))
 		}
 
-		var dev int
 		{
 			dev = des[e.wrk] - cur[e.wrk]
 		}
@@ -312,9 +430,26 @@ func (e *Engine) search() (*task.Task, error) {
 		}
 	}
 
-	var tas *task.Task
+	// We never claim more tasks than the balancer allows this worker to own on
+	// top of the tasks it owns already.
+	var lim int
+	{
+		lim = min(n, dev)
+	}
+
+	// Tasks with a higher priority are claimed first, while tasks of the same
+	// priority are claimed in the order of their creation.
+	{
+		lis = e.order(lis, e.tim.Search())
+	}
+
+	var cla []*task.Task
 
 	for _, x := range lis {
+		if len(cla) == lim {
+			break
+		}
+
 		// We are looking for tasks which do not yet have an owner. So if there is
 		// an owner assigned we ignore the task and move on to find another one.
 		if x.Core.Get().Worker() != "" {
@@ -327,56 +462,220 @@ func (e *Engine) search() (*task.Task, error) {
 		// Note that we want to give tasks priority that are specifically addressed
 		// to a particular worker. Tasks that can be processed by anyone are of
 		// secondary importance in our system.
-		if x.Node.Get(task.Method) == task.MthdUni && x.Node.Get(task.Worker) == e.wrk {
-			tas = x
-			break
+		if x.Node.Get(task.Method) == task.MthdUni && x.Node.Get(task.Worker) == e.wrk && e.permit(x, bkt) && e.admit(x, inf) {
+			e.consume(x, bkt)
+			cla = append(cla, x)
 		}
 	}
 
-	if tas == nil {
-		for _, x := range lis {
-			// We are looking for tasks which do not yet have an owner. So if there is
-			// an owner assigned we ignore the task and move on to find another one.
-			if x.Core.Get().Worker() != "" {
-				continue
-			}
+	for _, x := range lis {
+		if len(cla) == lim {
+			break
+		}
 
-			// The current task is not assigned to any worker. If this task's delivery
-			// method is now set to "any", then we simply take it and assign it to this
-			// current worker.
-			if x.Node.Get(task.Method) == task.MthdAny {
-				tas = x
-				break
-			}
+		// We are looking for tasks which do not yet have an owner. So if there is
+		// an owner assigned we ignore the task and move on to find another one.
+		if x.Core.Get().Worker() != "" {
+			continue
 		}
+
+		// The current task is not assigned to any worker. If this task's delivery
+		// method is now set to "any", then we simply take it and assign it to this
+		// current worker. Tasks are only claimed as long as no concurrency rule
+		// matching them reached its limit, and no rate limit matching them ran
+		// out of tokens. Throttled tasks are skipped in favour of other tasks.
+		if x.Node.Get(task.Method) == task.MthdAny && e.permit(x, bkt) && e.admit(x, inf) {
+			e.consume(x, bkt)
+			cla = append(cla, x)
+		}
+	}
+
+	{
+		e.saturate(inf)
 	}
 
-	if tas == nil {
+	if len(cla) == 0 {
 		e.met.Task.NotFound.Inc()
 		return nil, tracer.Mask(taskNotFoundError)
 	}
 
-	{
-		tas.Core.Set().Expiry(e.tim.Search().Add(e.exp))
-		tas.Core.Set().Worker(e.wrk)
+	var ele []store.Element
+	for _, x := range cla {
+		var str string
+		{
+			str = task.ToString(x)
+		}
+
+		// The retry delay of tasks retried before passed once they can be
+		// claimed again, which is why their Task.Cron is removed here.
+		if retried(x) {
+			x.Cron = nil
+		}
+
+		{
+			x.Core.Set().Expiry(e.tim.Search().Add(e.expiry(x)))
+			x.Core.Set().Worker(e.wrk)
+		}
+
+		ele = append(ele, store.Element{Cur: str, Key: e.Keyfmt(), Oid: x.Core.Get().Object(), Val: task.ToString(x)})
 	}
 
-	{
-		k := e.Keyfmt()
-		v := task.ToString(tas)
-		s := tas.Core.Get().Object().Float()
+	// Claiming any limited task increments the version of all claims subject to
+	// concurrency rules, so that concurrent claims of other workers fail if they
+	// are based on the same in-flight counts.
+	for _, x := range cla {
+		if e.limited(x) {
+			ele = append(ele, ver)
+			break
+		}
+	}
 
-		_, err := e.red.Sorted().Update().Value(k, v, s)
+	// Claiming any task subject to rate limits updates the token buckets that
+	// the tokens got taken from. Concurrent claims of other workers taking tokens
+	// from the same token buckets fail accordingly.
+	for _, x := range bkt {
+		if x.use {
+			ele = append(ele, x.ele)
+		}
+	}
+
+	{
+		swp, err := e.sto.Swap(ele)
 		if err != nil {
 			return nil, tracer.Mask(err)
 		}
+
+		// Any of the tasks we chose might have been claimed by another worker
+		// meanwhile, in which case we have to search again.
+		if !swp {
+			return nil, nil
+		}
 	}
 
 	{
-		e.met.Task.Parallel.Set(float64(cur[tas.Core.Get().Worker()] + 1))
+		e.met.Task.Parallel.Set(float64(cur[e.wrk] + len(cla)))
 	}
 
-	return tas, nil
+	return cla, nil
+}
+
+// broadcast returns up to n tasks defining the delivery method "all" that this
+// worker is supposed to process next, if any. The local lookup table is guarded by
+// the engine's mutex, since multiple goroutines may search for tasks
+// concurrently.
+func (e *Engine) broadcast(lis []*task.Task, n int) ([]*task.Task, error) {
+	e.mut.Lock()
+	defer e.mut.Unlock()
+
+	var all []*task.Task
+	for _, x := range lis {
+		if len(all) == n {
+			break
+		}
+
+		// Skip all scheduled task templates for further processing. Any task
+		// template defining Task.Cron is meant to trigger time based task
+		// scheduling for child tasks originating from that template. The template
+		// itself is not meant to be processed by workers.
+		if x.Cron != nil {
+			continue
+		}
+
+		// Skip all trigger task templates for further processing. Any task
+		// template defining Task.Gate is meant to trigger event based task
+		// scheduling for child tasks originating from that template. The template
+		// itself is not meant to be processed by workers. Note that we are checking
+		// whether Task.Gate has not any value "trigger", which means that if
+		// Task.Gate is not empty, then its values can only either be "waiting" or
+		// "deleted", which defines the trigger templates. Scheduled tasks defining
+		// any value "trigger" in Task.Gate are the very tasks that workers should
+		// process, because completion of those processed trigger tasks is what
+		// causes the trigger template to create the gated task that is being onhold
+		// until all triggers completed.
+		if x.Gate != nil && !x.Gate.Has(Tri()) {
+			continue
+		}
+
+		// Skip any task that does not define the task delivery method "all".
+		if x.Node.Get(task.Method) != task.MthdAll {
+			continue
+		}
+
+		// Skip any task that this worker is not capable of processing.
+		if !e.accepts(x) {
+			continue
+		}
+
+		var loc *local
+		{
+			loc = e.cac[x.Core.Get().Object()]
+		}
+
+		// Skip any task from our local copy that we already processed.
+		if loc != nil && loc.don {
+			continue
+		}
+
+		// Derive this task's creation timestamp from its object ID.
+		var tim time.Time
+		{
+			tim = x.Core.Get().Object().Time()
+		}
+
+		// Skip any task that got created before this worker started to participate
+		// within the network. Engine.pnt is the earliest point in time at which the
+		// worker process came online, or the latest point in time of having
+		// processed the oldest task broadcasted througout the network. If that
+		// pointer is equal to, or after the creation time of the current task that
+		// we do not track in our local cache already, then our rule is to not
+		// process it. And so we skip the task that got created before the current
+		// worker came online, and move on to the next task.
+		if loc == nil && !e.pnt.Before(tim) {
+			continue
+		}
+
+		// Skip any task that we completed already, even if we do not track it
+		// locally anymore, e.g. because this worker restarted meanwhile.
+		if loc == nil {
+			com, err := e.completes(x)
+			if err != nil {
+				return nil, tracer.Mask(err)
+			}
+
+			if com {
+				continue
+			}
+		}
+
+		var now time.Time
+		{
+			now = e.tim.Search()
+		}
+
+		// Skip any task that we are already processing within its specified time of
+		// expiry. The tasks we are skipping here are either still being processed,
+		// or failed, in which case we will pick them up again after local expiry.
+		if loc != nil && loc.exp.After(now) {
+			continue
+		}
+
+		// Skip any task that missed its deadline.
+		if missed(x, now) {
+			continue
+		}
+
+		// Remember the broadcasted task that this worker is processing right now
+		// without assigning worker ownership within the underlying system. Also
+		// remember the current expiry of this broadcasted task, so that we can
+		// expire it locally and retry if necessary.
+		{
+			e.cac[x.Core.Get().Object()] = &local{exp: now.Add(e.expiry(x))}
+		}
+
+		all = append(all, x)
+	}
+
+	return all, nil
 }
 
 func (e *Engine) searchAll() ([]*task.Task, error) {
@@ -386,7 +685,7 @@ func (e *Engine) searchAll() ([]*task.Task, error) {
 	{
 		k := e.Keyfmt()
 
-		str, err = e.red.Sorted().Search().Order(k, 0, -1)
+		str, err = e.sto.Lister(k)
 		if err != nil {
 			return nil, tracer.Mask(err)
 		}
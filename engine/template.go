@@ -0,0 +1,305 @@
+package engine
+
+import (
+	"fmt"
+	"sort"
+
+	"github.com/xh3b4sd/objectid"
+	"github.com/xh3b4sd/rescue/store"
+	"github.com/xh3b4sd/rescue/task"
+	"github.com/xh3b4sd/tracer"
+)
+
+func (e *Engine) Template(tas *task.Task) ([]*task.Task, error) {
+	var err error
+	var lis []*task.Task
+
+	e.met.Engine.Template.Cal.Inc()
+
+	o := func() error {
+		lis, err = e.template(tas)
+		if err != nil {
+			return tracer.Mask(err)
+		}
+
+		return nil
+	}
+
+	err = e.met.Engine.Template.Dur.Sin(o)
+	if err != nil {
+		e.met.Engine.Template.Err.Inc()
+		return nil, tracer.Mask(err)
+	}
+
+	return lis, nil
+}
+
+func (e *Engine) template(tas *task.Task) ([]*task.Task, error) {
+	var err error
+
+	{
+		err = verLis(tas)
+		if err != nil {
+			return nil, tracer.Mask(err)
+		}
+	}
+
+	// Listing task templates only requires to read the sorted set of task
+	// templates, unless the label indexes can be used to resolve the given
+	// label set.
+	var idx bool
+	var lis []*task.Task
+	{
+		lis, idx, err = e.indexed(tas)
+		if err != nil {
+			return nil, tracer.Mask(err)
+		}
+	}
+
+	if !idx {
+		lis, err = e.searchTpl()
+		if err != nil {
+			return nil, tracer.Mask(err)
+		}
+	}
+
+	var fil []*task.Task
+	for _, x := range lis {
+		if isTpl(x) && x.Has(tas) {
+			fil = append(fil, x)
+		}
+	}
+
+	return fil, nil
+}
+
+// lookup returns the task identified by the given object ID, together with the
+// key of the sorted set that the task was found in. Runnable tasks are looked
+// up first, and task templates second. lookup returns empty strings if the
+// task does not exist.
+func (e *Engine) lookup(oid objectid.ID) (string, string, error) {
+	for _, k := range []string{e.Keyfmt(), e.tplKey()} {
+		jsn, err := e.sto.Search(k, oid)
+		if err != nil {
+			return "", "", tracer.Mask(err)
+		}
+
+		if jsn != "" {
+			return k, jsn, nil
+		}
+	}
+
+	return "", "", nil
+}
+
+// migrate moves all task templates of the given list from the sorted set of
+// runnable tasks into the sorted set of task templates, unless this queue got
+// migrated already. The given list must contain all runnable tasks and the
+// returned list contains all runnable tasks that remained. migrate must only
+// be called while holding the global lock.
+func (e *Engine) migrate(lis []*task.Task) ([]*task.Task, error) {
+	var err error
+
+	var ver bool
+	{
+		ver, err = e.migrated(verTemplate)
+		if err != nil {
+			return nil, tracer.Mask(err)
+		}
+	}
+
+	if ver {
+		return lis, nil
+	}
+
+	var com bool
+	var run []*task.Task
+	{
+		com = true
+	}
+
+	for _, x := range lis {
+		if !isTpl(x) {
+			run = append(run, x)
+			continue
+		}
+
+		o := x.Core.Get().Object()
+		v := task.ToString(x)
+
+		// Creating the copy fails if a previous migration did not complete. The
+		// existing copy is the one that got modified since, and so we only remove
+		// the outdated task template from the sorted set of runnable tasks.
+		err = e.sto.Create(e.tplKey(), o, v)
+		if store.IsElementExists(err) {
+			_, err = e.sto.Delete(e.Keyfmt(), o)
+			if err != nil {
+				return nil, tracer.Mask(err)
+			}
+
+			continue
+		} else if err != nil {
+			return nil, tracer.Mask(err)
+		}
+
+		var del bool
+		{
+			del, err = e.sto.Delete(e.Keyfmt(), o, v)
+			if err != nil {
+				return nil, tracer.Mask(err)
+			}
+		}
+
+		// The task template might have been modified concurrently, in which case
+		// our copy is outdated. We remove the copy again and retry the migration
+		// during the next call.
+		if !del {
+			_, err = e.sto.Delete(e.tplKey(), o, v)
+			if err != nil {
+				return nil, tracer.Mask(err)
+			}
+
+			com = false
+		}
+	}
+
+	if com {
+		err = e.sto.Attach(e.verKey(), verTemplate)
+		if err != nil {
+			return nil, tracer.Mask(err)
+		}
+	}
+
+	return run, nil
+}
+
+// modifyTpl applies fun to the task template identified by oid, the same way
+// modify does. Task templates of queues that did not get migrated yet are
+// modified within the sorted set of runnable tasks.
+func (e *Engine) modifyTpl(oid objectid.ID, fun func(tas *task.Task) bool) (bool, error) {
+	for _, k := range []string{e.tplKey(), e.Keyfmt()} {
+		upd, err := e.modify(k, oid, fun)
+		if err != nil {
+			return false, tracer.Mask(err)
+		}
+
+		if upd {
+			return true, nil
+		}
+	}
+
+	return false, nil
+}
+
+// searchLis returns all runnable tasks and task templates ordered by their
+// object IDs.
+func (e *Engine) searchLis() ([]*task.Task, error) {
+	var err error
+
+	var lis []*task.Task
+	{
+		lis, err = e.searchAll()
+		if err != nil {
+			return nil, tracer.Mask(err)
+		}
+	}
+
+	var tpl []*task.Task
+	{
+		tpl, err = e.searchTpl()
+		if err != nil {
+			return nil, tracer.Mask(err)
+		}
+	}
+
+	return merge(tpl, lis), nil
+}
+
+// searchTpl returns all task templates ordered by their object IDs. Task
+// templates of queues that did not get migrated yet are also searched within
+// the sorted set of runnable tasks.
+func (e *Engine) searchTpl() ([]*task.Task, error) {
+	var err error
+
+	var str []string
+	{
+		str, err = e.sto.Lister(e.tplKey())
+		if err != nil {
+			return nil, tracer.Mask(err)
+		}
+	}
+
+	var tpl []*task.Task
+	for _, s := range str {
+		tpl = append(tpl, task.FromString(s))
+	}
+
+	var ver bool
+	{
+		ver, err = e.migrated(verTemplate)
+		if err != nil {
+			return nil, tracer.Mask(err)
+		}
+	}
+
+	if ver {
+		return tpl, nil
+	}
+
+	var lis []*task.Task
+	{
+		lis, err = e.searchAll()
+		if err != nil {
+			return nil, tracer.Mask(err)
+		}
+	}
+
+	var old []*task.Task
+	for _, x := range lis {
+		if isTpl(x) {
+			old = append(old, x)
+		}
+	}
+
+	return merge(tpl, old), nil
+}
+
+// tplKey returns the key of the sorted set containing all task templates of
+// this queue.
+func (e *Engine) tplKey() string {
+	return fmt.Sprintf("%s%stemplate", e.Keyfmt(), e.sep)
+}
+
+// isTpl expresses whether the given task is a task template. Task templates
+// define Task.Cron using @every, or Task.Gate without any reserved value
+// "trigger". Task templates are never processed by workers themselves.
+// Instead, task templates cause the creation of scheduled tasks, which are
+// then processed by workers.
+func isTpl(tas *task.Task) bool {
+	crn := tas.Cron != nil && tas.Cron.Exi().Aevery()
+	gat := tas.Gate != nil && !tas.Gate.Has(Tri())
+
+	return crn || gat
+}
+
+// merge returns the tasks of both given lists ordered by their object IDs.
+// Tasks found in both lists are only returned once.
+func merge(one []*task.Task, two []*task.Task) []*task.Task {
+	var lis []*task.Task
+
+	see := map[objectid.ID]bool{}
+	for _, x := range append(append([]*task.Task{}, one...), two...) {
+		if see[x.Core.Get().Object()] {
+			continue
+		}
+
+		see[x.Core.Get().Object()] = true
+		lis = append(lis, x)
+	}
+
+	sort.SliceStable(lis, func(i, j int) bool {
+		return lis[i].Core.Get().Object().Float() < lis[j].Core.Get().Object().Float()
+	})
+
+	return lis
+}
@@ -37,9 +37,10 @@ func (e *Engine) ticker() error {
 
 	// Emitting scheduled tasks implies certain write operations on the task queue
 	// such as adding a new task to a sorted set in redis. Due to such write
-	// operations we need to ensure that only one process at a time can write
-	// information back to the queue. Otherwise worker behaviour would be
-	// inconsistent and the integrity of the queue could not be guaranteed.
+	// operations we need to ensure that only one process at a time can emit
+	// scheduled tasks. Note that workers deleting scheduled tasks may update
+	// task templates concurrently without acquiring the lock, which is why task
+	// templates are modified based on their most recent state below.
 	{
 		err := e.loc.Acquire()
 		if err != nil {
@@ -62,17 +63,34 @@ func (e *Engine) ticker() error {
 		}
 	}
 
+	// Queues that existed before task templates got stored separately are
+	// migrated once, so that task templates can be looked at in isolation.
+	{
+		lis, err = e.migrate(lis)
+		if err != nil {
+			return tracer.Mask(err)
+		}
+	}
+
 	{
 		e.met.Task.Inactive.Set(float64(len(lis)))
 	}
 
-	if len(lis) == 0 {
+	var tpl []*task.Task
+	{
+		tpl, err = e.searchTpl()
+		if err != nil {
+			return tracer.Mask(err)
+		}
+	}
+
+	if len(tpl) == 0 {
 		return nil
 	}
 
 	// Update Task.Cron for task templates that completed their most recent
 	// reconciliation.
-	for i, x := range lis {
+	for _, x := range tpl {
 		// We are looking for tasks which have a schedule. So if there is no
 		// schedule defined, then we ignore the task and move on to find another
 		// one.
@@ -106,13 +124,7 @@ func (e *Engine) ticker() error {
 		}
 
 		var exi bool
-		for j, y := range lis {
-			// Skip the task we are processing right now. Here x and y are equal in
-			// case i and j are the same.
-			if i == j {
-				continue
-			}
-
+		for _, y := range lis {
 			if y.Root == nil {
 				continue
 			}
@@ -138,17 +150,16 @@ func (e *Engine) ticker() error {
 		// reconciled. So now we can bring the task template's past tick back into
 		// sync, since its most recent reconciliation at the previously defined
 		// tick-1 got successfully processed.
-		{
-			x.Cron.Set().TickM1(tic.TickM1())
+		fun := func(t *task.Task) bool {
+			t.Cron.Set().TickM1(tic.TickM1())
+			return true
 		}
 
 		// Update the task template defining Task.Cron.
 		{
-			k := e.Keyfmt()
-			v := task.ToString(x)
-			s := x.Core.Get().Object().Float()
+			o := x.Core.Get().Object()
 
-			_, err := e.red.Sorted().Update().Value(k, v, s)
+			_, err := e.modifyTpl(o, fun)
 			if err != nil {
 				return tracer.Mask(err)
 			}
@@ -156,7 +167,7 @@ func (e *Engine) ticker() error {
 	}
 
 	// Create tasks that are due for scheduling.
-	for i, x := range lis {
+	for _, x := range tpl {
 		// We are looking for task templates which have a schedule. So if there is
 		// no schedule defined, then we ignore the task and move on to find another
 		// one.
@@ -177,13 +188,8 @@ func (e *Engine) ticker() error {
 		}
 
 		var exi bool
-		for j, y := range lis {
-			// Skip the task we are processing right now. Here x and y are equal in
-			// case i and j are the same.
-			if i == j {
-				continue
-			}
-
+		var lin bool
+		for _, y := range lis {
 			if y.Root == nil {
 				continue
 			}
@@ -201,18 +207,27 @@ func (e *Engine) ticker() error {
 					// we delete the lingering task to replace it with the new one we are
 					// about to create below.
 					{
-						k := e.Keyfmt()
-						s := y.Core.Get().Object().Float()
-
-						err = e.red.Sorted().Delete().Score(k, s)
+						del, err := e.linger(y)
 						if err != nil {
 							return tracer.Mask(err)
 						}
+
+						if !del {
+							lin = true
+						}
 					}
 				}
 			}
 		}
 
+		// The lingering task could not be deleted, because it kept changing
+		// concurrently. We must not schedule another task next to it, and so we
+		// try again during the next call, without moving the template's ticks
+		// forward.
+		if lin {
+			continue
+		}
+
 		// We are looking for a scheduled task that has been reconciled. The final
 		// stage of reconciliation is task deletion. So if the scheduled task does
 		// still exist, then we cannot schedule another one. Regardless, we have to
@@ -254,16 +269,26 @@ func (e *Engine) ticker() error {
 				t.Node.Set(task.Method, task.MthdAny)
 			}
 
+			{
+				err = e.index(t)
+				if err != nil {
+					return tracer.Mask(err)
+				}
+			}
+
 			{
 				k := e.Keyfmt()
 				v := task.ToString(t)
-				s := oid.Float()
 
-				err = e.red.Sorted().Create().Score(k, v, s)
+				err = e.sto.Create(k, oid, v)
 				if err != nil {
 					return tracer.Mask(err)
 				}
 			}
+
+			{
+				e.notify()
+			}
 		}
 
 		// Update the task template defining Task.Cron using an up to date ticker
@@ -288,23 +313,25 @@ func (e *Engine) ticker() error {
 		// means there is no completion or acknowledgement for scheduled tasks if
 		// they are delivered to all workers. We just fire at-least-once, on
 		// schedule, and leave the rest to the workers.
-		if x.Node.Get(task.Method) == task.MthdAll {
-			x.Cron.Set().TickM1(tic.TickM1())
-		}
+		fun := func(t *task.Task) bool {
+			if t.Node.Get(task.Method) == task.MthdAll {
+				t.Cron.Set().TickM1(tic.TickM1())
+			}
 
-		// We found a scheduled task that got scheduled just now based on its next
-		// tick definition. Since the task got just scheduled, we move tick+1
-		// forward based on the currently up to date calculation.
-		{
-			x.Cron.Set().TickP1(tic.TickP1())
+			// We found a scheduled task that got scheduled just now based on its
+			// next tick definition. Since the task got just scheduled, we move
+			// tick+1 forward based on the currently up to date calculation.
+			{
+				t.Cron.Set().TickP1(tic.TickP1())
+			}
+
+			return true
 		}
 
 		{
-			k := e.Keyfmt()
-			v := task.ToString(x)
-			s := x.Core.Get().Object().Float()
+			o := x.Core.Get().Object()
 
-			_, err := e.red.Sorted().Update().Value(k, v, s)
+			_, err := e.modifyTpl(o, fun)
 			if err != nil {
 				return tracer.Mask(err)
 			}
@@ -313,3 +340,53 @@ func (e *Engine) ticker() error {
 
 	return nil
 }
+
+// linger deletes the given scheduled task, which became redundant at the new
+// interval of its task template. The stored task may have changed since it
+// got read, e.g. because target workers recorded their completion, in which
+// case the task is read again before trying once more. The returned bool
+// indicates whether the task is gone.
+func (e *Engine) linger(tas *task.Task) (bool, error) {
+	var err error
+
+	var key string
+	var oid objectid.ID
+	var jsn string
+	{
+		key = e.Keyfmt()
+		oid = tas.Core.Get().Object()
+		jsn = task.ToString(tas)
+	}
+
+	for i := 0; i < Retry; i++ {
+		var del bool
+		{
+			del, err = e.sto.Delete(key, oid, jsn)
+			if err != nil {
+				return false, tracer.Mask(err)
+			}
+		}
+
+		if del {
+			err = e.deindex(task.FromString(jsn))
+			if err != nil {
+				return false, tracer.Mask(err)
+			}
+
+			return true, nil
+		}
+
+		{
+			jsn, err = e.sto.Search(key, oid)
+			if err != nil {
+				return false, tracer.Mask(err)
+			}
+		}
+
+		if jsn == "" {
+			return true, nil
+		}
+	}
+
+	return false, nil
+}
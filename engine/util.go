@@ -1,6 +1,7 @@
 package engine
 
 import (
+	"strings"
 	"time"
 
 	"github.com/xh3b4sd/objectid"
@@ -72,3 +73,17 @@ func sum(m map[string]int) int {
 
 	return sum
 }
+
+// split returns the non-empty elements of the given comma separated list.
+func split(str string) []string {
+	var lis []string
+
+	for _, x := range strings.Split(str, ",") {
+		x = strings.TrimSpace(x)
+		if x != "" {
+			lis = append(lis, x)
+		}
+	}
+
+	return lis
+}
@@ -0,0 +1,38 @@
+package engine
+
+import (
+	"fmt"
+
+	"github.com/xh3b4sd/tracer"
+)
+
+// Every queue tracks the migrations applied to its underlying storage within
+// its version set, so that queues created by earlier versions of the engine
+// can be upgraded transparently.
+const (
+	// verIndex marks the label indexes of a queue to be complete. Queues
+	// lacking this version are indexed once by Engine.Expire, and until then
+	// Engine.Exists and Engine.Lister scan the whole queue.
+	verIndex = "index/1"
+	// verTemplate marks all task templates of a queue to be stored within their
+	// own sorted set. Queues lacking this version are migrated once by
+	// Engine.Expire or Engine.Ticker, and until then task templates are looked
+	// up within both sorted sets.
+	verTemplate = "template/1"
+)
+
+// migrated expresses whether the given version got applied to this queue.
+func (e *Engine) migrated(ver string) (bool, error) {
+	mem, err := e.sto.Member(e.verKey())
+	if err != nil {
+		return false, tracer.Mask(err)
+	}
+
+	return contains(mem, ver), nil
+}
+
+// verKey returns the key of the set containing all versions applied to this
+// queue.
+func (e *Engine) verKey() string {
+	return fmt.Sprintf("%s%sversion", e.Keyfmt(), e.sep)
+}
@@ -4,6 +4,7 @@ import (
 	"github.com/xh3b4sd/redigo"
 	"github.com/xh3b4sd/redigo/locker"
 	"github.com/xh3b4sd/rescue/engine"
+	"github.com/xh3b4sd/rescue/store"
 )
 
 func Default() Interface {
@@ -18,3 +19,12 @@ func Fake() Interface {
 		Redigo: redigo.Fake(),
 	})
 }
+
+// Memory returns a fully functional engine managing its queue in memory. All
+// tasks are lost once the process terminates. Memory is meant for single
+// process usage and for testing purposes.
+func Memory() Interface {
+	return engine.New(engine.Config{
+		Store: store.NewMemory(),
+	})
+}
@@ -11,3 +11,7 @@ func Test_Factory_Interface_Default(t *testing.T) {
 func Test_Factory_Interface_Fake(t *testing.T) {
 	var _ Interface = Fake()
 }
+
+func Test_Factory_Interface_Memory(t *testing.T) {
+	var _ Interface = Memory()
+}
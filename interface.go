@@ -1,17 +1,41 @@
 package rescue
 
 import (
+	"context"
+
+	"github.com/xh3b4sd/rescue/engine"
 	"github.com/xh3b4sd/rescue/task"
 )
 
 type Interface interface {
+	// Completed returns the identifiers of all workers that completed the given
+	// task defining the delivery method "all" or "mny". Every worker completing
+	// such a task using Delete records its completion, so that producers can
+	// tell which workers processed it.
+	//
+	//     inp[0] the object ID of the task to inspect
+	//
+	Completed(tas *task.Task) ([]string, error)
+
 	// Create submits a new task to the system. Anyone can create any task any
 	// time. The task producer must just have an understanding of what consumers
 	// within the system are capable of. Task.Meta and Task.Root of a queued task
 	// must always match with a consumer in order to be processed. Scheduled tasks
-	// may be created using Task.Cron.
+	// may be created using Task.Cron. Tasks defining a dedupe key are not
+	// created again while their duplicate exists, in which case Task.Core is
+	// assigned the object ID of the existing task. The same applies to tasks
+	// defining a coalescing selector, which supersede older tasks that are not
+	// yet owned by any worker.
 	Create(tas *task.Task) error
 
+	// CreateMany submits all of the given tasks to the system, the same way
+	// Create does for a single task. All tasks are validated before any of them
+	// gets written. If any task is invalid, then no task gets created at all,
+	// and the returned error reports the index of every invalid task. Valid
+	// batches are written atomically, so that either all tasks get created, or
+	// none of them.
+	CreateMany(tas []*task.Task) error
+
 	// Cycles allows the Task.Core.Cycles label to be reset to 0, given its
 	// associated object ID. Resetting this cycles counter is only allowed for
 	// tasks that define Task.Core.Cancel as a circuit breaker in order to get
@@ -21,6 +45,14 @@ type Interface interface {
 	//
 	Cycles(tas *task.Task) error
 
+	// Deadletter fetches all tasks within the dead-letter queue that match the
+	// given metadata. Tasks hitting their maximum amount of execution attempts,
+	// as defined by the circuit breaker label Task.Core.Cancel, are moved into
+	// the dead-letter queue by Expire, given that the dead-letter queue is
+	// enabled. Dead-lettered tasks are neither processed nor listed by Lister,
+	// until they get redriven using Redrive, or removed using PurgeDeadletter.
+	Deadletter(tas *task.Task) ([]*task.Task, error)
+
 	// Delete removes an existing task from the system. Tasks can only be deleted
 	// by the workers that own the task they have been assigned to. Task ownership
 	// cannot be cherry-picked. Deleting an expired task causes an error on the
@@ -64,10 +96,30 @@ type Interface interface {
 	// because resource management is then designed to be eventually reconciled.
 	Extend(tas *task.Task) error
 
+	// Heartbeat announces the calling worker within the worker registry of the
+	// queue, together with its start time and its configured labels. Workers
+	// should call Heartbeat continuously, well within the configured heartbeat
+	// timeout. The balancer distributes tasks between all workers that are
+	// alive according to the worker registry, even if they do not own any task
+	// yet, while workers that stopped sending heartbeats are not accounted for
+	// anymore.
+	Heartbeat() error
+
 	// Keyfmt returns the formatted key for this engine's queue, the underlying
-	// sorted set in Redis.
+	// sorted set within the configured storage, e.g. Redis.
 	Keyfmt() string
 
+	// Lease renews the expiry of the given task in the background, so that
+	// workers processing long running tasks do not have to call Extend
+	// themselves. The expiry is renewed at a fraction of the configured expiry.
+	// The returned context is cancelled as soon as ownership of the task got
+	// lost, or as soon as renewing the task failed for too long, so that
+	// workers can stop processing tasks that they do not own anymore. The
+	// reason for cancellation is available via context.Cause. The returned
+	// function stops renewing the task and must be called once the task got
+	// processed, before calling Delete.
+	Lease(ctx context.Context, tas *task.Task) (context.Context, context.CancelFunc)
+
 	// Listen returns the TCP address in the form of host:port which the
 	// underlying redis client is connected to.
 	Listen() string
@@ -78,9 +130,69 @@ type Interface interface {
 	// underlying system design.
 	Lister(tas *task.Task) ([]*task.Task, error)
 
-	// Search provides the calling worker with an available task.
+	// PurgeDeadletter removes the given task from the dead-letter queue for
+	// good.
+	//
+	//     inp[0] the object ID of the task to remove
+	//
+	PurgeDeadletter(tas *task.Task) error
+
+	// Redrive moves the given task from the dead-letter queue back into the
+	// task queue, so that it can be processed again. The cycles count of the
+	// task is reset, so that the task gets another full set of execution
+	// attempts before its circuit breaker halts it again. A deadline that the
+	// task missed is removed.
+	//
+	//     inp[0] the object ID of the task to redrive
+	//
+	Redrive(tas *task.Task) error
+
+	// Registry returns all workers of the queue that sent a heartbeat within the
+	// configured heartbeat timeout.
+	Registry() ([]*engine.Member, error)
+
+	// Release can be called by workers owning a task in order to hand that task
+	// back to the queue without completing it. Ownership is revoked right away
+	// and Task.Core.Cycles is incremented, the same way Expire would do once the
+	// task's expiry passed. An optional failure reason is recorded in
+	// Task.Core for the next owner to inspect. An optional retry delay defers
+	// the next execution of the task using tick+1 in Task.Cron. Without retry
+	// delay, the configured backoff policy applies, if any.
+	Release(tas *task.Task, opt engine.ReleaseOptions) error
+
+	// Search provides the calling worker with an available task. Tasks defining
+	// a higher priority in Task.Core are provided first, while tasks of the same
+	// priority are provided in the order of their creation. Tasks matching any
+	// configured concurrency rule that reached its limit are not provided, and
+	// neither are tasks matching any configured rate limit that got exhausted.
+	// Workers configured with capabilities are only provided with tasks matching
+	// any of their capabilities.
 	Search() (*task.Task, error)
 
+	// SearchN provides the calling worker with up to n available tasks, claimed
+	// within a single pass over the queue. SearchN respects the desired task
+	// distribution of the configured balancer the same way Search does. Either
+	// all of the returned tasks got claimed by the calling worker, or none of
+	// them. Tasks defining the delivery method "all" or "mny" are returned on
+	// their own, without claiming any other task.
+	SearchN(n int) ([]*task.Task, error)
+
+	// SearchWait provides the calling worker with an available task the same way
+	// Search does, but blocks until a task got claimed, or until the given
+	// context is done. Blocked workers are notified as soon as tasks might have
+	// become claimable, e.g. because new tasks got created, or because task
+	// ownership expired. In case notifications get lost, the queue is searched
+	// again periodically.
+	SearchWait(ctx context.Context) (*task.Task, error)
+
+	// Template fetches all existing task templates that match the given
+	// metadata. Task templates define Task.Cron using @every, or Task.Gate
+	// without any reserved value "trigger". Task templates are stored separately
+	// from the tasks that workers process, so that Search does not have to skip
+	// them, and Ticker does not have to look at runnable tasks in order to find
+	// schedulable templates. Note that Lister returns task templates as well.
+	Template(tas *task.Task) ([]*task.Task, error)
+
 	// Engine.Ticker is an optional background process that every worker can
 	// continously execute in order to emit scheduled tasks based on any task
 	// template defining Task.Cron. Ticker goes through the full list of available
@@ -6,15 +6,26 @@ type Collection struct {
 }
 
 type CollectionEngine struct {
-	Create *CollectionEngineCollector
-	Cycles *CollectionEngineCollector
-	Delete *CollectionEngineCollector
-	Exists *CollectionEngineCollector
-	Expire *CollectionEngineCollector
-	Extend *CollectionEngineCollector
-	Lister *CollectionEngineCollector
-	Search *CollectionEngineCollector
-	Ticker *CollectionEngineCollector
+	Completed       *CollectionEngineCollector
+	Create          *CollectionEngineCollector
+	CreateMany      *CollectionEngineCollector
+	Cycles          *CollectionEngineCollector
+	Deadletter      *CollectionEngineCollector
+	Delete          *CollectionEngineCollector
+	Exists          *CollectionEngineCollector
+	Expire          *CollectionEngineCollector
+	Extend          *CollectionEngineCollector
+	Heartbeat       *CollectionEngineCollector
+	Lister          *CollectionEngineCollector
+	PurgeDeadletter *CollectionEngineCollector
+	Redrive         *CollectionEngineCollector
+	Registry        *CollectionEngineCollector
+	Release         *CollectionEngineCollector
+	Search          *CollectionEngineCollector
+	SearchN         *CollectionEngineCollector
+	SearchWait      *CollectionEngineCollector
+	Template        *CollectionEngineCollector
+	Ticker          *CollectionEngineCollector
 }
 
 type CollectionEngineCollector struct {
@@ -24,24 +35,42 @@ type CollectionEngineCollector struct {
 }
 
 type CollectionTask struct {
-	Expired  Interface
-	Extended Interface
-	Inactive Interface
-	NotFound Interface
-	Obsolete Interface
-	Outdated Interface
-	Parallel Interface
+	Coalesce   Interface
+	Deadletter Interface
+	Deadline   Interface
+	Dedupe     Interface
+	Expired    Interface
+	Extended   Interface
+	Inactive   Interface
+	NotFound   Interface
+	Obsolete   Interface
+	Outdated   Interface
+	Parallel   Interface
+	Saturation *Vector
+	Throttled  Interface
 }
 
 func (c *Collection) Reset() {
+	c.Engine.Completed.Cal.Res()
+	c.Engine.Completed.Dur.Res()
+	c.Engine.Completed.Err.Res()
+
 	c.Engine.Create.Cal.Res()
 	c.Engine.Create.Dur.Res()
 	c.Engine.Create.Err.Res()
 
+	c.Engine.CreateMany.Cal.Res()
+	c.Engine.CreateMany.Dur.Res()
+	c.Engine.CreateMany.Err.Res()
+
 	c.Engine.Cycles.Cal.Res()
 	c.Engine.Cycles.Dur.Res()
 	c.Engine.Cycles.Err.Res()
 
+	c.Engine.Deadletter.Cal.Res()
+	c.Engine.Deadletter.Dur.Res()
+	c.Engine.Deadletter.Err.Res()
+
 	c.Engine.Delete.Cal.Res()
 	c.Engine.Delete.Dur.Res()
 	c.Engine.Delete.Err.Res()
@@ -58,18 +87,54 @@ func (c *Collection) Reset() {
 	c.Engine.Extend.Dur.Res()
 	c.Engine.Extend.Err.Res()
 
+	c.Engine.Heartbeat.Cal.Res()
+	c.Engine.Heartbeat.Dur.Res()
+	c.Engine.Heartbeat.Err.Res()
+
 	c.Engine.Lister.Cal.Res()
 	c.Engine.Lister.Dur.Res()
 	c.Engine.Lister.Err.Res()
 
+	c.Engine.PurgeDeadletter.Cal.Res()
+	c.Engine.PurgeDeadletter.Dur.Res()
+	c.Engine.PurgeDeadletter.Err.Res()
+
+	c.Engine.Redrive.Cal.Res()
+	c.Engine.Redrive.Dur.Res()
+	c.Engine.Redrive.Err.Res()
+
+	c.Engine.Registry.Cal.Res()
+	c.Engine.Registry.Dur.Res()
+	c.Engine.Registry.Err.Res()
+
+	c.Engine.Release.Cal.Res()
+	c.Engine.Release.Dur.Res()
+	c.Engine.Release.Err.Res()
+
 	c.Engine.Search.Cal.Res()
 	c.Engine.Search.Dur.Res()
 	c.Engine.Search.Err.Res()
 
+	c.Engine.SearchN.Cal.Res()
+	c.Engine.SearchN.Dur.Res()
+	c.Engine.SearchN.Err.Res()
+
+	c.Engine.SearchWait.Cal.Res()
+	c.Engine.SearchWait.Dur.Res()
+	c.Engine.SearchWait.Err.Res()
+
+	c.Engine.Template.Cal.Res()
+	c.Engine.Template.Dur.Res()
+	c.Engine.Template.Err.Res()
+
 	c.Engine.Ticker.Cal.Res()
 	c.Engine.Ticker.Dur.Res()
 	c.Engine.Ticker.Err.Res()
 
+	c.Task.Coalesce.Res()
+	c.Task.Deadletter.Res()
+	c.Task.Deadline.Res()
+	c.Task.Dedupe.Res()
 	c.Task.Expired.Res()
 	c.Task.Extended.Res()
 	c.Task.Inactive.Res()
@@ -77,4 +142,6 @@ func (c *Collection) Reset() {
 	c.Task.Obsolete.Res()
 	c.Task.Outdated.Res()
 	c.Task.Parallel.Res()
+	c.Task.Saturation.Res()
+	c.Task.Throttled.Res()
 }
@@ -5,60 +5,121 @@ import "github.com/prometheus/client_golang/prometheus"
 func Default() *Collection {
 	c := &Collection{
 		Engine: &CollectionEngine{
+			Completed: &CollectionEngineCollector{
+				Cal: &Metric{d: prometheus.NewDesc("rescue_engine_completed_call_total" /***************/, "the number of times a call to Engine.Completed was made", nil, nil)},
+				Dur: &Metric{d: prometheus.NewDesc("rescue_engine_completed_duration_seconds" /*********/, "the number of seconds a call to Engine.Completed took", nil, nil)},
+				Err: &Metric{d: prometheus.NewDesc("rescue_engine_completed_error_total" /**************/, "the number of errors a call to Engine.Completed produced", nil, nil)},
+			},
 			Create: &CollectionEngineCollector{
-				Cal: &Metric{d: prometheus.NewDesc("rescue_engine_create_call_total" /*********/, "the number of times a call to Engine.Create was made", nil, nil)},
-				Dur: &Metric{d: prometheus.NewDesc("rescue_engine_create_duration_seconds" /***/, "the number of seconds a call to Engine.Create took", nil, nil)},
-				Err: &Metric{d: prometheus.NewDesc("rescue_engine_create_error_total" /********/, "the number of errors a call to Engine.Create produced", nil, nil)},
+				Cal: &Metric{d: prometheus.NewDesc("rescue_engine_create_call_total" /******************/, "the number of times a call to Engine.Create was made", nil, nil)},
+				Dur: &Metric{d: prometheus.NewDesc("rescue_engine_create_duration_seconds" /************/, "the number of seconds a call to Engine.Create took", nil, nil)},
+				Err: &Metric{d: prometheus.NewDesc("rescue_engine_create_error_total" /*****************/, "the number of errors a call to Engine.Create produced", nil, nil)},
+			},
+			CreateMany: &CollectionEngineCollector{
+				Cal: &Metric{d: prometheus.NewDesc("rescue_engine_create_many_call_total" /*************/, "the number of times a call to Engine.CreateMany was made", nil, nil)},
+				Dur: &Metric{d: prometheus.NewDesc("rescue_engine_create_many_duration_seconds" /*******/, "the number of seconds a call to Engine.CreateMany took", nil, nil)},
+				Err: &Metric{d: prometheus.NewDesc("rescue_engine_create_many_error_total" /************/, "the number of errors a call to Engine.CreateMany produced", nil, nil)},
 			},
 			Cycles: &CollectionEngineCollector{
-				Cal: &Metric{d: prometheus.NewDesc("rescue_engine_cycles_call_total" /*********/, "the number of times a call to Engine.Cycles was made", nil, nil)},
-				Dur: &Metric{d: prometheus.NewDesc("rescue_engine_cycles_duration_seconds" /***/, "the number of seconds a call to Engine.Cycles took", nil, nil)},
-				Err: &Metric{d: prometheus.NewDesc("rescue_engine_cycles_error_total" /********/, "the number of errors a call to Engine.Cycles produced", nil, nil)},
+				Cal: &Metric{d: prometheus.NewDesc("rescue_engine_cycles_call_total" /******************/, "the number of times a call to Engine.Cycles was made", nil, nil)},
+				Dur: &Metric{d: prometheus.NewDesc("rescue_engine_cycles_duration_seconds" /************/, "the number of seconds a call to Engine.Cycles took", nil, nil)},
+				Err: &Metric{d: prometheus.NewDesc("rescue_engine_cycles_error_total" /*****************/, "the number of errors a call to Engine.Cycles produced", nil, nil)},
+			},
+			Deadletter: &CollectionEngineCollector{
+				Cal: &Metric{d: prometheus.NewDesc("rescue_engine_deadletter_call_total" /**************/, "the number of times a call to Engine.Deadletter was made", nil, nil)},
+				Dur: &Metric{d: prometheus.NewDesc("rescue_engine_deadletter_duration_seconds" /********/, "the number of seconds a call to Engine.Deadletter took", nil, nil)},
+				Err: &Metric{d: prometheus.NewDesc("rescue_engine_deadletter_error_total" /*************/, "the number of errors a call to Engine.Deadletter produced", nil, nil)},
 			},
 			Delete: &CollectionEngineCollector{
-				Cal: &Metric{d: prometheus.NewDesc("rescue_engine_delete_call_total" /*********/, "the number of times a call to Engine.Delete was made", nil, nil)},
-				Dur: &Metric{d: prometheus.NewDesc("rescue_engine_delete_duration_seconds" /***/, "the number of seconds a call to Engine.Delete took", nil, nil)},
-				Err: &Metric{d: prometheus.NewDesc("rescue_engine_delete_error_total" /********/, "the number of errors a call to Engine.Delete produced", nil, nil)},
+				Cal: &Metric{d: prometheus.NewDesc("rescue_engine_delete_call_total" /******************/, "the number of times a call to Engine.Delete was made", nil, nil)},
+				Dur: &Metric{d: prometheus.NewDesc("rescue_engine_delete_duration_seconds" /************/, "the number of seconds a call to Engine.Delete took", nil, nil)},
+				Err: &Metric{d: prometheus.NewDesc("rescue_engine_delete_error_total" /*****************/, "the number of errors a call to Engine.Delete produced", nil, nil)},
 			},
 			Exists: &CollectionEngineCollector{
-				Cal: &Metric{d: prometheus.NewDesc("rescue_engine_exists_call_total" /*********/, "the number of times a call to Engine.Exists was made", nil, nil)},
-				Dur: &Metric{d: prometheus.NewDesc("rescue_engine_exists_duration_seconds" /***/, "the number of seconds a call to Engine.Exists took", nil, nil)},
-				Err: &Metric{d: prometheus.NewDesc("rescue_engine_exists_error_total" /********/, "the number of errors a call to Engine.Exists produced", nil, nil)},
+				Cal: &Metric{d: prometheus.NewDesc("rescue_engine_exists_call_total" /******************/, "the number of times a call to Engine.Exists was made", nil, nil)},
+				Dur: &Metric{d: prometheus.NewDesc("rescue_engine_exists_duration_seconds" /************/, "the number of seconds a call to Engine.Exists took", nil, nil)},
+				Err: &Metric{d: prometheus.NewDesc("rescue_engine_exists_error_total" /*****************/, "the number of errors a call to Engine.Exists produced", nil, nil)},
 			},
 			Expire: &CollectionEngineCollector{
-				Cal: &Metric{d: prometheus.NewDesc("rescue_engine_expire_call_total" /*********/, "the number of times a call to Engine.Expire was made", nil, nil)},
-				Dur: &Metric{d: prometheus.NewDesc("rescue_engine_expire_duration_seconds" /***/, "the number of seconds a call to Engine.Expire took", nil, nil)},
-				Err: &Metric{d: prometheus.NewDesc("rescue_engine_expire_error_total" /********/, "the number of errors a call to Engine.Expire produced", nil, nil)},
+				Cal: &Metric{d: prometheus.NewDesc("rescue_engine_expire_call_total" /******************/, "the number of times a call to Engine.Expire was made", nil, nil)},
+				Dur: &Metric{d: prometheus.NewDesc("rescue_engine_expire_duration_seconds" /************/, "the number of seconds a call to Engine.Expire took", nil, nil)},
+				Err: &Metric{d: prometheus.NewDesc("rescue_engine_expire_error_total" /*****************/, "the number of errors a call to Engine.Expire produced", nil, nil)},
 			},
 			Extend: &CollectionEngineCollector{
-				Cal: &Metric{d: prometheus.NewDesc("rescue_engine_extend_call_total" /*********/, "the number of times a call to Engine.Extend was made", nil, nil)},
-				Dur: &Metric{d: prometheus.NewDesc("rescue_engine_extend_duration_seconds" /***/, "the number of seconds a call to Engine.Extend took", nil, nil)},
-				Err: &Metric{d: prometheus.NewDesc("rescue_engine_extend_error_total" /********/, "the number of errors a call to Engine.Extend produced", nil, nil)},
+				Cal: &Metric{d: prometheus.NewDesc("rescue_engine_extend_call_total" /******************/, "the number of times a call to Engine.Extend was made", nil, nil)},
+				Dur: &Metric{d: prometheus.NewDesc("rescue_engine_extend_duration_seconds" /************/, "the number of seconds a call to Engine.Extend took", nil, nil)},
+				Err: &Metric{d: prometheus.NewDesc("rescue_engine_extend_error_total" /*****************/, "the number of errors a call to Engine.Extend produced", nil, nil)},
+			},
+			Heartbeat: &CollectionEngineCollector{
+				Cal: &Metric{d: prometheus.NewDesc("rescue_engine_heartbeat_call_total" /***************/, "the number of times a call to Engine.Heartbeat was made", nil, nil)},
+				Dur: &Metric{d: prometheus.NewDesc("rescue_engine_heartbeat_duration_seconds" /*********/, "the number of seconds a call to Engine.Heartbeat took", nil, nil)},
+				Err: &Metric{d: prometheus.NewDesc("rescue_engine_heartbeat_error_total" /**************/, "the number of errors a call to Engine.Heartbeat produced", nil, nil)},
 			},
 			Lister: &CollectionEngineCollector{
-				Cal: &Metric{d: prometheus.NewDesc("rescue_engine_lister_call_total" /*********/, "the number of times a call to Engine.Lister was made", nil, nil)},
-				Dur: &Metric{d: prometheus.NewDesc("rescue_engine_lister_duration_seconds" /***/, "the number of seconds a call to Engine.Lister took", nil, nil)},
-				Err: &Metric{d: prometheus.NewDesc("rescue_engine_lister_error_total" /********/, "the number of errors a call to Engine.Lister produced", nil, nil)},
+				Cal: &Metric{d: prometheus.NewDesc("rescue_engine_lister_call_total" /******************/, "the number of times a call to Engine.Lister was made", nil, nil)},
+				Dur: &Metric{d: prometheus.NewDesc("rescue_engine_lister_duration_seconds" /************/, "the number of seconds a call to Engine.Lister took", nil, nil)},
+				Err: &Metric{d: prometheus.NewDesc("rescue_engine_lister_error_total" /*****************/, "the number of errors a call to Engine.Lister produced", nil, nil)},
+			},
+			PurgeDeadletter: &CollectionEngineCollector{
+				Cal: &Metric{d: prometheus.NewDesc("rescue_engine_purge_deadletter_call_total" /********/, "the number of times a call to Engine.PurgeDeadletter was made", nil, nil)},
+				Dur: &Metric{d: prometheus.NewDesc("rescue_engine_purge_deadletter_duration_seconds" /**/, "the number of seconds a call to Engine.PurgeDeadletter took", nil, nil)},
+				Err: &Metric{d: prometheus.NewDesc("rescue_engine_purge_deadletter_error_total" /*******/, "the number of errors a call to Engine.PurgeDeadletter produced", nil, nil)},
+			},
+			Redrive: &CollectionEngineCollector{
+				Cal: &Metric{d: prometheus.NewDesc("rescue_engine_redrive_call_total" /*****************/, "the number of times a call to Engine.Redrive was made", nil, nil)},
+				Dur: &Metric{d: prometheus.NewDesc("rescue_engine_redrive_duration_seconds" /***********/, "the number of seconds a call to Engine.Redrive took", nil, nil)},
+				Err: &Metric{d: prometheus.NewDesc("rescue_engine_redrive_error_total" /****************/, "the number of errors a call to Engine.Redrive produced", nil, nil)},
+			},
+			Registry: &CollectionEngineCollector{
+				Cal: &Metric{d: prometheus.NewDesc("rescue_engine_registry_call_total" /****************/, "the number of times a call to Engine.Registry was made", nil, nil)},
+				Dur: &Metric{d: prometheus.NewDesc("rescue_engine_registry_duration_seconds" /**********/, "the number of seconds a call to Engine.Registry took", nil, nil)},
+				Err: &Metric{d: prometheus.NewDesc("rescue_engine_registry_error_total" /***************/, "the number of errors a call to Engine.Registry produced", nil, nil)},
+			},
+			Release: &CollectionEngineCollector{
+				Cal: &Metric{d: prometheus.NewDesc("rescue_engine_release_call_total" /*****************/, "the number of times a call to Engine.Release was made", nil, nil)},
+				Dur: &Metric{d: prometheus.NewDesc("rescue_engine_release_duration_seconds" /***********/, "the number of seconds a call to Engine.Release took", nil, nil)},
+				Err: &Metric{d: prometheus.NewDesc("rescue_engine_release_error_total" /****************/, "the number of errors a call to Engine.Release produced", nil, nil)},
 			},
 			Search: &CollectionEngineCollector{
-				Cal: &Metric{d: prometheus.NewDesc("rescue_engine_search_call_total" /*********/, "the number of times a call to Engine.Search was made", nil, nil)},
-				Dur: &Metric{d: prometheus.NewDesc("rescue_engine_search_duration_seconds" /***/, "the number of seconds a call to Engine.Search took", nil, nil)},
-				Err: &Metric{d: prometheus.NewDesc("rescue_engine_search_error_total" /********/, "the number of errors a call to Engine.Search produced", nil, nil)},
+				Cal: &Metric{d: prometheus.NewDesc("rescue_engine_search_call_total" /******************/, "the number of times a call to Engine.Search was made", nil, nil)},
+				Dur: &Metric{d: prometheus.NewDesc("rescue_engine_search_duration_seconds" /************/, "the number of seconds a call to Engine.Search took", nil, nil)},
+				Err: &Metric{d: prometheus.NewDesc("rescue_engine_search_error_total" /*****************/, "the number of errors a call to Engine.Search produced", nil, nil)},
+			},
+			SearchN: &CollectionEngineCollector{
+				Cal: &Metric{d: prometheus.NewDesc("rescue_engine_search_n_call_total" /****************/, "the number of times a call to Engine.SearchN was made", nil, nil)},
+				Dur: &Metric{d: prometheus.NewDesc("rescue_engine_search_n_duration_seconds" /**********/, "the number of seconds a call to Engine.SearchN took", nil, nil)},
+				Err: &Metric{d: prometheus.NewDesc("rescue_engine_search_n_error_total" /***************/, "the number of errors a call to Engine.SearchN produced", nil, nil)},
+			},
+			SearchWait: &CollectionEngineCollector{
+				Cal: &Metric{d: prometheus.NewDesc("rescue_engine_search_wait_call_total" /*************/, "the number of times a call to Engine.SearchWait was made", nil, nil)},
+				Dur: &Metric{d: prometheus.NewDesc("rescue_engine_search_wait_duration_seconds" /*******/, "the number of seconds a call to Engine.SearchWait took", nil, nil)},
+				Err: &Metric{d: prometheus.NewDesc("rescue_engine_search_wait_error_total" /************/, "the number of errors a call to Engine.SearchWait produced", nil, nil)},
+			},
+			Template: &CollectionEngineCollector{
+				Cal: &Metric{d: prometheus.NewDesc("rescue_engine_template_call_total" /****************/, "the number of times a call to Engine.Template was made", nil, nil)},
+				Dur: &Metric{d: prometheus.NewDesc("rescue_engine_template_duration_seconds" /**********/, "the number of seconds a call to Engine.Template took", nil, nil)},
+				Err: &Metric{d: prometheus.NewDesc("rescue_engine_template_error_total" /***************/, "the number of errors a call to Engine.Template produced", nil, nil)},
 			},
 			Ticker: &CollectionEngineCollector{
-				Cal: &Metric{d: prometheus.NewDesc("rescue_engine_ticker_call_total" /*********/, "the number of times a call to Engine.Ticker was made", nil, nil)},
-				Dur: &Metric{d: prometheus.NewDesc("rescue_engine_ticker_duration_seconds" /***/, "the number of seconds a call to Engine.Ticker took", nil, nil)},
-				Err: &Metric{d: prometheus.NewDesc("rescue_engine_ticker_error_total" /********/, "the number of errors a call to Engine.Ticker produced", nil, nil)},
+				Cal: &Metric{d: prometheus.NewDesc("rescue_engine_ticker_call_total" /******************/, "the number of times a call to Engine.Ticker was made", nil, nil)},
+				Dur: &Metric{d: prometheus.NewDesc("rescue_engine_ticker_duration_seconds" /************/, "the number of seconds a call to Engine.Ticker took", nil, nil)},
+				Err: &Metric{d: prometheus.NewDesc("rescue_engine_ticker_error_total" /*****************/, "the number of errors a call to Engine.Ticker produced", nil, nil)},
 			},
 		},
 		Task: &CollectionTask{
-			Expired:  &Metric{d: prometheus.NewDesc("rescue_task_expired_total" /****/, "the number of times a task was expired during a call to Engine.Expire", nil, nil)},
-			Extended: &Metric{d: prometheus.NewDesc("rescue_task_extended_total" /***/, "the number of times a task was extended during a call to Engine.Extend", nil, nil)},
-			Inactive: &Metric{d: prometheus.NewDesc("rescue_task_inactive_total" /***/, "the number of tasks found idle in the queue during a call to Engine.*", nil, nil)},
-			NotFound: &Metric{d: prometheus.NewDesc("rescue_task_notfound_total" /***/, "the number of times a task could not be found during a call to Engine.*", nil, nil)},
-			Obsolete: &Metric{d: prometheus.NewDesc("rescue_task_obsolete_total" /***/, "the number of times a nested task was removed during a call to Engine.Search", nil, nil)},
-			Outdated: &Metric{d: prometheus.NewDesc("rescue_task_outdated_total" /***/, "the number of times a task has changed internally during a call to Engine.*", nil, nil)},
-			Parallel: &Metric{d: prometheus.NewDesc("rescue_task_parallel_total" /***/, "the number of tasks claimed concurrently by a single worker during a call to Engine.Search", nil, nil)},
+			Coalesce:   &Metric{d: prometheus.NewDesc("rescue_task_coalesced_total" /********/, "the number of times a task was superseded by a newer task during a call to Engine.Create", nil, nil)},
+			Deadletter: &Metric{d: prometheus.NewDesc("rescue_task_deadletter_total" /*******/, "the number of tasks found dead-lettered in the queue during a call to Engine.*", nil, nil)},
+			Deadline:   &Metric{d: prometheus.NewDesc("rescue_task_deadline_missed_total" /**/, "the number of times a task missed its deadline during a call to Engine.Expire", nil, nil)},
+			Dedupe:     &Metric{d: prometheus.NewDesc("rescue_task_dedupe_total" /***********/, "the number of times a duplicated task was not created during a call to Engine.Create", nil, nil)},
+			Expired:    &Metric{d: prometheus.NewDesc("rescue_task_expired_total" /**********/, "the number of times a task was expired during a call to Engine.Expire", nil, nil)},
+			Extended:   &Metric{d: prometheus.NewDesc("rescue_task_extended_total" /*********/, "the number of times a task was extended during a call to Engine.Extend", nil, nil)},
+			Inactive:   &Metric{d: prometheus.NewDesc("rescue_task_inactive_total" /*********/, "the number of tasks found idle in the queue during a call to Engine.*", nil, nil)},
+			NotFound:   &Metric{d: prometheus.NewDesc("rescue_task_notfound_total" /*********/, "the number of times a task could not be found during a call to Engine.*", nil, nil)},
+			Obsolete:   &Metric{d: prometheus.NewDesc("rescue_task_obsolete_total" /*********/, "the number of times a nested task was removed during a call to Engine.Search", nil, nil)},
+			Outdated:   &Metric{d: prometheus.NewDesc("rescue_task_outdated_total" /*********/, "the number of times a task has changed internally during a call to Engine.*", nil, nil)},
+			Parallel:   &Metric{d: prometheus.NewDesc("rescue_task_parallel_total" /*********/, "the number of tasks claimed concurrently by a single worker during a call to Engine.Search", nil, nil)},
+			Saturation: &Vector{d: prometheus.NewDesc("rescue_task_saturation_ratio" /*******/, "the ratio of in-flight tasks to the limit of a concurrency rule during a call to Engine.Search", []string{"rule"}, nil)},
+			Throttled:  &Metric{d: prometheus.NewDesc("rescue_task_throttled_total" /********/, "the number of times a rate limit prevented tasks from being claimed during a call to Engine.Search", nil, nil)},
 		},
 	}
 
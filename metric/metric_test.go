@@ -79,3 +79,29 @@ func Test_Metric(t *testing.T) {
 		}
 	}
 }
+
+func Test_Metric_Vector(t *testing.T) {
+	m := Default()
+
+	{
+		m.Task.Saturation.Set("foo", 0.5)
+	}
+
+	{
+		i := m.Task.Saturation.Get()["foo"]
+		if i != 0.5 {
+			t.Fatal("i must be 0.5")
+		}
+	}
+
+	{
+		m.Reset()
+	}
+
+	{
+		l := len(m.Task.Saturation.Get())
+		if l != 0 {
+			t.Fatal("l must be 0")
+		}
+	}
+}
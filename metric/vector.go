@@ -0,0 +1,49 @@
+package metric
+
+import (
+	"sync"
+
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+// Vector is a metric partitioned by the value of a single label, e.g. one
+// gauge per configured rule.
+type Vector struct {
+	d *prometheus.Desc
+	m sync.Mutex
+	v map[string]float64
+}
+
+func (v *Vector) Des() *prometheus.Desc {
+	return v.d
+}
+
+func (v *Vector) Get() map[string]float64 {
+	v.m.Lock()
+	defer v.m.Unlock()
+
+	cop := map[string]float64{}
+	for k, x := range v.v {
+		cop[k] = x
+	}
+
+	return cop
+}
+
+func (v *Vector) Res() {
+	v.m.Lock()
+	defer v.m.Unlock()
+
+	v.v = map[string]float64{}
+}
+
+func (v *Vector) Set(lab string, i float64) {
+	v.m.Lock()
+	defer v.m.Unlock()
+
+	if v.v == nil {
+		v.v = map[string]float64{}
+	}
+
+	v.v[lab] = i
+}
@@ -0,0 +1,24 @@
+package store
+
+import (
+	"errors"
+
+	"github.com/xh3b4sd/tracer"
+)
+
+var elementExistsError = &tracer.Error{
+	Kind: "elementExistsError",
+	Desc: "When creating elements, the object ID of the new element must be unique within the sorted set. The given object ID is already used by another element.",
+}
+
+func IsElementExists(err error) bool {
+	return errors.Is(err, elementExistsError)
+}
+
+var invalidConfigError = &tracer.Error{
+	Kind: "invalidConfigError",
+}
+
+func IsInvalidConfig(err error) bool {
+	return errors.Is(err, invalidConfigError)
+}
@@ -0,0 +1,9 @@
+package store
+
+import "github.com/xh3b4sd/redigo"
+
+func Default() Interface {
+	return NewRedis(RedisConfig{
+		Redigo: redigo.Default(),
+	})
+}
@@ -0,0 +1,783 @@
+package store
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"io"
+	"os"
+	"path/filepath"
+	"sync"
+
+	"github.com/xh3b4sd/objectid"
+	"github.com/xh3b4sd/redigo/locker"
+	"github.com/xh3b4sd/tracer"
+)
+
+const (
+	// Compact is the default number of log records after which the log of a
+	// file store may be compacted.
+	Compact = 1000
+)
+
+const (
+	actAttach = "attach"
+	actBatch  = "batch"
+	actCreate = "create"
+	actDelete = "delete"
+	actDetach = "detach"
+	actUpdate = "update"
+)
+
+type FileConfig struct {
+	// Compact is the minimum number of log records before the append-only log
+	// gets rewritten. The log is only rewritten if more than half of its
+	// records became obsolete. Defaults to 1000.
+	Compact int
+	// Path is the location of the append-only log on the local file system.
+	// Two more files are maintained next to the log, one for serializing
+	// storage access, and one for the engine level lock returned by Locker.
+	Path string
+}
+
+// File implements Interface using an embedded append-only log on the local
+// file system. Every write is appended to the log and synced to disk before
+// returning, so that all data survives process restarts. The log is compacted
+// automatically once enough of its records became obsolete. Multiple processes
+// on the same host may share the same log, since all access is serialized
+// using file locks. Every process keeps an in-memory copy of the log which is
+// brought up to date before every operation.
+type File struct {
+	cmp int
+	cou int
+	fil *os.File
+	idx map[string]map[string]struct{}
+	loc *flock
+	mut sync.Mutex
+	ntf notifier
+	off int64
+	pat string
+	set map[string]map[objectid.ID]string
+	syn *os.File
+}
+
+type record struct {
+	Act string      `json:"act"`
+	Bat []record    `json:"bat,omitempty"`
+	Key string      `json:"key"`
+	Mem []string    `json:"mem,omitempty"`
+	Oid objectid.ID `json:"oid,omitempty"`
+	Val string      `json:"val,omitempty"`
+}
+
+func NewFile(config FileConfig) (*File, error) {
+	if config.Compact == 0 {
+		config.Compact = Compact
+	}
+	if config.Path == "" {
+		return nil, tracer.Maskf(invalidConfigError, "%T.Path must not be empty", config)
+	}
+
+	var err error
+
+	var loc *os.File
+	{
+		loc, err = os.OpenFile(config.Path+".engine.lock", os.O_CREATE|os.O_RDWR, 0600)
+		if err != nil {
+			return nil, tracer.Mask(err)
+		}
+	}
+
+	var syn *os.File
+	{
+		syn, err = os.OpenFile(config.Path+".store.lock", os.O_CREATE|os.O_RDWR, 0600)
+		if err != nil {
+			loc.Close()
+			return nil, tracer.Mask(err)
+		}
+	}
+
+	f := &File{
+		cmp: config.Compact,
+		idx: map[string]map[string]struct{}{},
+		loc: &flock{fil: loc},
+		pat: config.Path,
+		set: map[string]map[objectid.ID]string{},
+		syn: syn,
+	}
+
+	// Acquiring the storage lock loads the current state of the log into
+	// memory.
+	{
+		err = f.lock()
+		if err != nil {
+			f.Close()
+			return nil, tracer.Mask(err)
+		}
+
+		f.unlock(&err)
+		if err != nil {
+			f.Close()
+			return nil, tracer.Mask(err)
+		}
+	}
+
+	return f, nil
+}
+
+func (f *File) Attach(key string, mem ...string) (err error) {
+	if len(mem) == 0 {
+		return nil
+	}
+
+	err = f.lock()
+	if err != nil {
+		return tracer.Mask(err)
+	}
+
+	defer f.unlock(&err)
+
+	err = f.append(record{Act: actAttach, Key: key, Mem: mem})
+	if err != nil {
+		return tracer.Mask(err)
+	}
+
+	return nil
+}
+
+// Batch appends a single record containing all of the given elements, so that
+// a process crashing while writing the record can never leave only some of the
+// elements behind.
+func (f *File) Batch(ele []Element) (err error) {
+	if len(ele) == 0 {
+		return nil
+	}
+
+	err = f.lock()
+	if err != nil {
+		return tracer.Mask(err)
+	}
+
+	defer f.unlock(&err)
+
+	err = unique(ele)
+	if err != nil {
+		return tracer.Mask(err)
+	}
+
+	var bat []record
+	for _, x := range ele {
+		_, exi := f.set[x.Key][x.Oid]
+		if exi {
+			return tracer.Maskf(elementExistsError, "%s", x.Oid)
+		}
+
+		bat = append(bat, record{Act: actCreate, Key: x.Key, Oid: x.Oid, Val: x.Val})
+	}
+
+	err = f.append(record{Act: actBatch, Bat: bat})
+	if err != nil {
+		return tracer.Mask(err)
+	}
+
+	return nil
+}
+
+// Close releases the file descriptors of the log and its lock files. The file
+// store must not be used anymore afterwards.
+func (f *File) Close() error {
+	f.mut.Lock()
+	defer f.mut.Unlock()
+
+	var err error
+
+	for _, x := range []*os.File{f.fil, f.syn, f.loc.fil} {
+		if x == nil {
+			continue
+		}
+
+		cer := x.Close()
+		if cer != nil && err == nil {
+			err = cer
+		}
+	}
+
+	{
+		f.fil = nil
+	}
+
+	if err != nil {
+		return tracer.Mask(err)
+	}
+
+	return nil
+}
+
+func (f *File) Create(key string, oid objectid.ID, val string) (err error) {
+	err = f.lock()
+	if err != nil {
+		return tracer.Mask(err)
+	}
+
+	defer f.unlock(&err)
+
+	_, exi := f.set[key][oid]
+	if exi {
+		return tracer.Maskf(elementExistsError, "%s", oid)
+	}
+
+	err = f.append(record{Act: actCreate, Key: key, Oid: oid, Val: val})
+	if err != nil {
+		return tracer.Mask(err)
+	}
+
+	return nil
+}
+
+func (f *File) Delete(key string, oid objectid.ID, cur ...string) (_ bool, err error) {
+	err = f.lock()
+	if err != nil {
+		return false, tracer.Mask(err)
+	}
+
+	defer f.unlock(&err)
+
+	str, exi := f.set[key][oid]
+	if !exi || (len(cur) != 0 && str != cur[0]) {
+		return false, nil
+	}
+
+	err = f.append(record{Act: actDelete, Key: key, Oid: oid})
+	if err != nil {
+		return false, tracer.Mask(err)
+	}
+
+	return true, nil
+}
+
+func (f *File) Detach(key string, mem ...string) (err error) {
+	if len(mem) == 0 {
+		return nil
+	}
+
+	err = f.lock()
+	if err != nil {
+		return tracer.Mask(err)
+	}
+
+	defer f.unlock(&err)
+
+	err = f.append(record{Act: actDetach, Key: key, Mem: mem})
+	if err != nil {
+		return tracer.Mask(err)
+	}
+
+	return nil
+}
+
+func (f *File) Lister(key string) (_ []string, err error) {
+	err = f.lock()
+	if err != nil {
+		return nil, tracer.Mask(err)
+	}
+
+	defer f.unlock(&err)
+
+	return sorted(f.set[key]), nil
+}
+
+func (f *File) Locker() locker.Interface {
+	return f.loc
+}
+
+func (f *File) Member(key string) (_ []string, err error) {
+	err = f.lock()
+	if err != nil {
+		return nil, tracer.Mask(err)
+	}
+
+	defer f.unlock(&err)
+
+	return member(f.idx[key]), nil
+}
+
+// Notify wakes up everyone watching key within this process. Processes sharing
+// the same log are not notified.
+func (f *File) Notify(key string) error {
+	f.ntf.notify(key)
+	return nil
+}
+
+func (f *File) Purge() (err error) {
+	err = f.lock()
+	if err != nil {
+		return tracer.Mask(err)
+	}
+
+	defer f.unlock(&err)
+
+	{
+		f.idx = map[string]map[string]struct{}{}
+		f.set = map[string]map[objectid.ID]string{}
+	}
+
+	err = f.compact()
+	if err != nil {
+		return tracer.Mask(err)
+	}
+
+	return nil
+}
+
+func (f *File) Search(key string, oid objectid.ID) (_ string, err error) {
+	err = f.lock()
+	if err != nil {
+		return "", tracer.Mask(err)
+	}
+
+	defer f.unlock(&err)
+
+	return f.set[key][oid], nil
+}
+
+// Swap appends a single record containing all of the given updates, the same
+// way Batch does for newly created elements.
+func (f *File) Swap(ele []Element) (_ bool, err error) {
+	if len(ele) == 0 {
+		return true, nil
+	}
+
+	err = f.lock()
+	if err != nil {
+		return false, tracer.Mask(err)
+	}
+
+	defer f.unlock(&err)
+
+	var bat []record
+	for _, x := range ele {
+		str, exi := f.set[x.Key][x.Oid]
+		if !exi || str != x.Cur {
+			return false, nil
+		}
+
+		bat = append(bat, record{Act: actUpdate, Key: x.Key, Oid: x.Oid, Val: x.Val})
+	}
+
+	err = f.append(record{Act: actBatch, Bat: bat})
+	if err != nil {
+		return false, tracer.Mask(err)
+	}
+
+	return true, nil
+}
+
+func (f *File) Update(key string, oid objectid.ID, cur string, val string) (_ bool, err error) {
+	err = f.lock()
+	if err != nil {
+		return false, tracer.Mask(err)
+	}
+
+	defer f.unlock(&err)
+
+	str, exi := f.set[key][oid]
+	if !exi || str != cur {
+		return false, nil
+	}
+
+	err = f.append(record{Act: actUpdate, Key: key, Oid: oid, Val: val})
+	if err != nil {
+		return false, tracer.Mask(err)
+	}
+
+	return true, nil
+}
+
+func (f *File) Watch(key string) (<-chan struct{}, func(), error) {
+	c, cls := f.ntf.watch(key)
+	return c, cls, nil
+}
+
+// append writes the given record to the end of the log, syncs it to disk and
+// applies it to the in-memory copy afterwards. The log is compacted if the
+// number of obsolete records exceeds the configured threshold.
+func (f *File) append(rec record) error {
+	var err error
+
+	var byt []byte
+	{
+		byt, err = json.Marshal(rec)
+		if err != nil {
+			return tracer.Mask(err)
+		}
+	}
+
+	// A record that did not make it to disk must not be replayed later on, since
+	// the caller is told that the write failed. So the log is truncated back to
+	// its previous size, which drops any partially written record as well.
+	{
+		_, err = f.fil.Write(append(byt, '\n'))
+		if err == nil {
+			err = f.fil.Sync()
+		}
+
+		if err != nil {
+			f.fil.Truncate(f.off)
+			return tracer.Mask(err)
+		}
+	}
+
+	{
+		f.apply(rec)
+		f.cou++
+		f.off += int64(len(byt) + 1)
+	}
+
+	if f.cou >= f.cmp && f.cou > 2*f.len() {
+		err = f.compact()
+		if err != nil {
+			return tracer.Mask(err)
+		}
+	}
+
+	return nil
+}
+
+func (f *File) apply(rec record) {
+	switch rec.Act {
+	case actAttach:
+		attach(f.idx, rec.Key, rec.Mem...)
+	case actBatch:
+		for _, x := range rec.Bat {
+			f.apply(x)
+		}
+	case actDetach:
+		detach(f.idx, rec.Key, rec.Mem...)
+	case actCreate, actUpdate:
+		if f.set[rec.Key] == nil {
+			f.set[rec.Key] = map[objectid.ID]string{}
+		}
+
+		f.set[rec.Key][rec.Oid] = rec.Val
+	case actDelete:
+		delete(f.set[rec.Key], rec.Oid)
+
+		if len(f.set[rec.Key]) == 0 {
+			delete(f.set, rec.Key)
+		}
+	}
+}
+
+// compact rewrites the log using a single create record for every element of
+// the in-memory copy, and a single attach record for every set. The new log is
+// written to a temporary file first, which then atomically replaces the
+// current log. Other processes detect the replaced log and reload it before
+// their next operation.
+func (f *File) compact() error {
+	var err error
+
+	var tmp *os.File
+	{
+		tmp, err = os.OpenFile(f.pat+".tmp", os.O_CREATE|os.O_TRUNC|os.O_WRONLY, 0600)
+		if err != nil {
+			return tracer.Mask(err)
+		}
+	}
+
+	// The temporary file is removed if anything goes wrong before it replaced
+	// the current log, so that no partially written log is left behind.
+	var don bool
+	defer func() {
+		if !don {
+			tmp.Close()
+			os.Remove(f.pat + ".tmp")
+		}
+	}()
+
+	var cou int
+	var off int64
+	{
+		buf := bufio.NewWriter(tmp)
+
+		for k, v := range f.set {
+			for o, s := range v {
+				byt, err := json.Marshal(record{Act: actCreate, Key: k, Oid: o, Val: s})
+				if err != nil {
+					return tracer.Mask(err)
+				}
+
+				_, err = buf.Write(append(byt, '\n'))
+				if err != nil {
+					return tracer.Mask(err)
+				}
+
+				cou++
+				off += int64(len(byt) + 1)
+			}
+		}
+
+		for k, v := range f.idx {
+			byt, err := json.Marshal(record{Act: actAttach, Key: k, Mem: member(v)})
+			if err != nil {
+				return tracer.Mask(err)
+			}
+
+			_, err = buf.Write(append(byt, '\n'))
+			if err != nil {
+				return tracer.Mask(err)
+			}
+
+			cou++
+			off += int64(len(byt) + 1)
+		}
+
+		err = buf.Flush()
+		if err != nil {
+			return tracer.Mask(err)
+		}
+
+		err = tmp.Sync()
+		if err != nil {
+			return tracer.Mask(err)
+		}
+
+		err = tmp.Close()
+		if err != nil {
+			return tracer.Mask(err)
+		}
+	}
+
+	{
+		err = os.Rename(f.pat+".tmp", f.pat)
+		if err != nil {
+			return tracer.Mask(err)
+		}
+
+		don = true
+	}
+
+	// The rename is only durable once the directory containing the log got
+	// synced to disk as well.
+	{
+		err = syncDir(filepath.Dir(f.pat))
+		if err != nil {
+			return tracer.Mask(err)
+		}
+	}
+
+	{
+		err = f.open()
+		if err != nil {
+			return tracer.Mask(err)
+		}
+	}
+
+	{
+		f.cou = cou
+		f.off = off
+	}
+
+	return nil
+}
+
+func (f *File) len() int {
+	var l int
+
+	for _, v := range f.set {
+		l += len(v)
+	}
+
+	return l + len(f.idx)
+}
+
+// lock serializes access to the log across goroutines and processes, and
+// brings the in-memory copy up to date with the current state of the log.
+func (f *File) lock() error {
+	f.mut.Lock()
+
+	err := flockAcquire(f.syn)
+	if err != nil {
+		f.mut.Unlock()
+		return tracer.Mask(err)
+	}
+
+	err = f.refresh()
+	if err != nil {
+		f.unlock(&err)
+		return tracer.Mask(err)
+	}
+
+	return nil
+}
+
+func (f *File) open() error {
+	if f.fil != nil {
+		err := f.fil.Close()
+		if err != nil {
+			return tracer.Mask(err)
+		}
+	}
+
+	fil, err := os.OpenFile(f.pat, os.O_CREATE|os.O_RDWR|os.O_APPEND, 0600)
+	if err != nil {
+		return tracer.Mask(err)
+	}
+
+	f.fil = fil
+
+	return nil
+}
+
+// refresh applies all records that got appended to the log by other processes
+// since the last operation of this process. If the log got replaced by
+// compaction meanwhile, the complete log is reloaded.
+func (f *File) refresh() error {
+	var err error
+
+	if f.fil == nil {
+		return tracer.Mask(f.reload())
+	}
+
+	var sta os.FileInfo
+	{
+		sta, err = os.Stat(f.pat)
+		if os.IsNotExist(err) {
+			return tracer.Mask(f.reload())
+		} else if err != nil {
+			return tracer.Mask(err)
+		}
+	}
+
+	var cur os.FileInfo
+	{
+		cur, err = f.fil.Stat()
+		if err != nil {
+			return tracer.Mask(err)
+		}
+	}
+
+	if !os.SameFile(sta, cur) || sta.Size() < f.off {
+		return tracer.Mask(f.reload())
+	}
+
+	if sta.Size() == f.off {
+		return nil
+	}
+
+	return tracer.Mask(f.replay())
+}
+
+// reload discards the in-memory copy and replays the complete log.
+func (f *File) reload() error {
+	{
+		f.cou = 0
+		f.idx = map[string]map[string]struct{}{}
+		f.off = 0
+		f.set = map[string]map[objectid.ID]string{}
+	}
+
+	err := f.open()
+	if err != nil {
+		return tracer.Mask(err)
+	}
+
+	return tracer.Mask(f.replay())
+}
+
+// replay applies all complete records found after the current offset. An
+// incomplete record at the end of the log can only be the result of a process
+// crashing while writing, because writers hold the storage lock. Such a record
+// is truncated, so that subsequent records can be appended safely.
+func (f *File) replay() error {
+	var err error
+
+	var byt []byte
+	{
+		_, err = f.fil.Seek(f.off, io.SeekStart)
+		if err != nil {
+			return tracer.Mask(err)
+		}
+
+		byt, err = io.ReadAll(f.fil)
+		if err != nil {
+			return tracer.Mask(err)
+		}
+	}
+
+	for {
+		i := bytes.IndexByte(byt, '\n')
+		if i == -1 {
+			break
+		}
+
+		var rec record
+		{
+			err = json.Unmarshal(byt[:i], &rec)
+			if err != nil {
+				return tracer.Mask(err)
+			}
+		}
+
+		{
+			f.apply(rec)
+			f.cou++
+			f.off += int64(i + 1)
+			byt = byt[i+1:]
+		}
+	}
+
+	if len(byt) != 0 {
+		err = f.fil.Truncate(f.off)
+		if err != nil {
+			return tracer.Mask(err)
+		}
+	}
+
+	return nil
+}
+
+// unlock releases the storage lock acquired using lock. Failing to release the
+// file lock is reported using the given error of the guarded operation, unless
+// that operation failed already.
+func (f *File) unlock(err *error) {
+	defer f.mut.Unlock()
+
+	rel := flockRelease(f.syn)
+	if rel != nil && *err == nil {
+		*err = tracer.Mask(rel)
+	}
+}
+
+// flock implements locker.Interface using a file lock, so that the engine
+// level lock is shared by all processes on the same host using the same log.
+type flock struct {
+	fil *os.File
+	mut sync.Mutex
+}
+
+func (l *flock) Acquire() error {
+	l.mut.Lock()
+
+	err := flockAcquire(l.fil)
+	if err != nil {
+		l.mut.Unlock()
+		return tracer.Mask(err)
+	}
+
+	return nil
+}
+
+func (l *flock) Refresh() error {
+	return nil
+}
+
+func (l *flock) Release() error {
+	defer l.mut.Unlock()
+
+	err := flockRelease(l.fil)
+	if err != nil {
+		return tracer.Mask(err)
+	}
+
+	return nil
+}
@@ -0,0 +1,20 @@
+//go:build !unix
+
+package store
+
+import "os"
+
+// flockAcquire is a no-op on platforms without file locks. Access to the log
+// is then only serialized within the current process.
+func flockAcquire(fil *os.File) error {
+	return nil
+}
+
+func flockRelease(fil *os.File) error {
+	return nil
+}
+
+// syncDir is a no-op on platforms that do not support syncing directories.
+func syncDir(dir string) error {
+	return nil
+}
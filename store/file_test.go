@@ -0,0 +1,445 @@
+package store
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func Test_Store_File_Close(t *testing.T) {
+	var err error
+
+	var pat string
+	{
+		pat = filepath.Join(t.TempDir(), "queue.log")
+	}
+
+	var f *File
+	{
+		f, err = NewFile(FileConfig{Path: pat})
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	{
+		err = f.Create("k", "1611318984211861", "foo")
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	{
+		err = f.Close()
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	// All file descriptors must be released once the store got closed.
+	{
+		err = f.syn.Close()
+		if !errors.Is(err, os.ErrClosed) {
+			t.Fatal("expected", os.ErrClosed, "got", err)
+		}
+
+		err = f.loc.fil.Close()
+		if !errors.Is(err, os.ErrClosed) {
+			t.Fatal("expected", os.ErrClosed, "got", err)
+		}
+	}
+
+	var r *File
+	{
+		r, err = NewFile(FileConfig{Path: pat})
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	{
+		str, err := r.Search("k", "1611318984211861")
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if str != "foo" {
+			t.Fatal("expected", "foo", "got", str)
+		}
+	}
+}
+
+func Test_Store_File_Compact(t *testing.T) {
+	var err error
+
+	var pat string
+	{
+		pat = filepath.Join(t.TempDir(), "queue.log")
+	}
+
+	var f *File
+	{
+		f, err = NewFile(FileConfig{Compact: 4, Path: pat})
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	{
+		err = f.Create("k", "1611318984211861", "foo")
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	for _, x := range []string{"bar", "baz", "zap"} {
+		str, err := f.Search("k", "1611318984211861")
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		_, err = f.Update("k", "1611318984211861", str, x)
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	// After 4 records and only 1 existing element, the log must have been
+	// compacted to a single record.
+	{
+		if f.cou != 1 {
+			t.Fatal("expected", 1, "got", f.cou)
+		}
+	}
+
+	var r *File
+	{
+		r, err = NewFile(FileConfig{Path: pat})
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	{
+		lis, err := r.Lister("k")
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if !reflect.DeepEqual([]string{"zap"}, lis) {
+			t.Fatalf("\n\n%s\n", cmp.Diff([]string{"zap"}, lis))
+		}
+	}
+}
+
+// Test_Store_File_Member ensures that sets survive compaction and process
+// restarts.
+func Test_Store_File_Locker(t *testing.T) {
+	var err error
+
+	var f *File
+	{
+		f, err = NewFile(FileConfig{Path: filepath.Join(t.TempDir(), "queue.log")})
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	{
+		err = f.Locker().Acquire()
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	// Releasing the file lock fails once its file descriptor got closed. The
+	// process level lock must be released anyway, so that further attempts to
+	// acquire the lock do not block forever.
+	{
+		err = f.loc.fil.Close()
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	{
+		err = f.Locker().Release()
+		if err == nil {
+			t.Fatal("expected", "error", "got", nil)
+		}
+	}
+
+	{
+		err = f.Locker().Acquire()
+		if err == nil {
+			t.Fatal("expected", "error", "got", nil)
+		}
+	}
+}
+
+func Test_Store_File_Member(t *testing.T) {
+	var err error
+
+	var pat string
+	{
+		pat = filepath.Join(t.TempDir(), "queue.log")
+	}
+
+	var f *File
+	{
+		f, err = NewFile(FileConfig{Compact: 4, Path: pat})
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	{
+		err = f.Attach("s", "foo", "bar", "baz")
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		err = f.Attach("s", "zap")
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		err = f.Detach("s", "bar", "baz")
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		err = f.Detach("s", "baz")
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	// After 4 records and only 1 existing set, the log must have been compacted
+	// to a single record.
+	{
+		if f.cou != 1 {
+			t.Fatal("expected", 1, "got", f.cou)
+		}
+	}
+
+	var r *File
+	{
+		r, err = NewFile(FileConfig{Path: pat})
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	{
+		mem, err := r.Member("s")
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if !reflect.DeepEqual([]string{"foo", "zap"}, mem) {
+			t.Fatalf("\n\n%s\n", cmp.Diff([]string{"foo", "zap"}, mem))
+		}
+	}
+}
+
+// Test_Store_File_Reopen ensures that all data survives a process restart,
+// which is simulated by creating a new file store using the same path.
+func Test_Store_File_Reopen(t *testing.T) {
+	var err error
+
+	var pat string
+	{
+		pat = filepath.Join(t.TempDir(), "queue.log")
+	}
+
+	{
+		f, err := NewFile(FileConfig{Path: pat})
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		err = f.Create("k", "1611318984211861", "foo")
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		err = f.Create("k", "1611318984211862", "bar")
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		err = f.Create("k", "1611318984211863", "baz")
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		_, err = f.Delete("k", "1611318984211862")
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		err = f.Batch([]Element{
+			{Key: "k", Oid: "1611318984211865", Val: "qux"},
+			{Key: "l", Oid: "1611318984211865", Val: "qux"},
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	// Simulate a crash while writing the next record. The incomplete record
+	// must be ignored and truncated upon restart.
+	{
+		fil, err := os.OpenFile(pat, os.O_APPEND|os.O_WRONLY, 0600)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		_, err = fil.WriteString(`{"act":"create","key":"k","oi`)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		err = fil.Close()
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	var f *File
+	{
+		f, err = NewFile(FileConfig{Path: pat})
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	{
+		err = f.Create("k", "1611318984211864", "zap")
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	var r *File
+	{
+		r, err = NewFile(FileConfig{Path: pat})
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	{
+		lis, err := r.Lister("k")
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		exp := []string{"foo", "baz", "zap", "qux"}
+		if !reflect.DeepEqual(exp, lis) {
+			t.Fatalf("\n\n%s\n", cmp.Diff(exp, lis))
+		}
+	}
+}
+
+// Test_Store_File_Shared ensures that multiple file stores sharing the same
+// log observe each other's writes, as multiple processes on the same host
+// would.
+func Test_Store_File_Shared(t *testing.T) {
+	var err error
+
+	var pat string
+	{
+		pat = filepath.Join(t.TempDir(), "queue.log")
+	}
+
+	var one *File
+	var two *File
+	{
+		one, err = NewFile(FileConfig{Compact: 2, Path: pat})
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		two, err = NewFile(FileConfig{Compact: 2, Path: pat})
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	{
+		err = one.Create("k", "1611318984211861", "foo")
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	{
+		upd, err := two.Update("k", "1611318984211861", "foo", "bar")
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if !upd {
+			t.Fatal("expected", true, "got", false)
+		}
+	}
+
+	{
+		upd, err := one.Update("k", "1611318984211861", "foo", "baz")
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if upd {
+			t.Fatal("expected", false, "got", true)
+		}
+	}
+
+	// Another update causes the log to be compacted by store two, which store
+	// one must detect.
+	{
+		upd, err := two.Update("k", "1611318984211861", "bar", "zap")
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if !upd {
+			t.Fatal("expected", true, "got", false)
+		}
+	}
+
+	{
+		str, err := one.Search("k", "1611318984211861")
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if str != "zap" {
+			t.Fatal("expected", "zap", "got", str)
+		}
+	}
+
+	{
+		err = one.Purge()
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	{
+		lis, err := two.Lister("k")
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if len(lis) != 0 {
+			t.Fatal("expected", 0, "got", len(lis))
+		}
+	}
+}
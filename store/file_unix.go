@@ -0,0 +1,46 @@
+//go:build unix
+
+package store
+
+import (
+	"os"
+	"syscall"
+
+	"github.com/xh3b4sd/tracer"
+)
+
+func flockAcquire(fil *os.File) error {
+	err := syscall.Flock(int(fil.Fd()), syscall.LOCK_EX)
+	if err != nil {
+		return tracer.Mask(err)
+	}
+
+	return nil
+}
+
+func flockRelease(fil *os.File) error {
+	err := syscall.Flock(int(fil.Fd()), syscall.LOCK_UN)
+	if err != nil {
+		return tracer.Mask(err)
+	}
+
+	return nil
+}
+
+// syncDir flushes the given directory to disk, so that renames of the files it
+// contains survive crashes.
+func syncDir(dir string) error {
+	fil, err := os.Open(dir)
+	if err != nil {
+		return tracer.Mask(err)
+	}
+
+	defer fil.Close()
+
+	err = fil.Sync()
+	if err != nil {
+		return tracer.Mask(err)
+	}
+
+	return nil
+}
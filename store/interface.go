@@ -0,0 +1,102 @@
+package store
+
+import (
+	"github.com/xh3b4sd/objectid"
+	"github.com/xh3b4sd/redigo/locker"
+)
+
+// Interface describes the storage primitives that the engine requires in order
+// to manage its queue. Every queue is a sorted set of opaque values under a
+// given key, where every value is uniquely identified and ordered by its
+// object ID. The engine only ever operates on those primitives, so that the
+// very same engine logic can run on top of any storage system capable of
+// providing the semantics described below. Next to sorted sets, the engine
+// maintains plain sets of strings, e.g. for indexing tasks by their labels.
+type Interface interface {
+	// Attach adds the given members to the set under key. Adding members that
+	// do already exist is not an error.
+	Attach(key string, mem ...string) error
+
+	// Batch adds all of the given elements atomically, the same way Create adds
+	// a single element. Either all elements get created, or none of them.
+	// Batch results in an error without creating any element if any of the
+	// given object IDs does already exist under its key, or if the same object
+	// ID is given twice for the same key.
+	Batch(ele []Element) error
+
+	// Create adds the element val to the sorted set under key, using the given
+	// object ID as the element's unique score. Creating an element for an object
+	// ID that does already exist under key results in an error.
+	Create(key string, oid objectid.ID, val string) error
+
+	// Detach removes the given members from the set under key. Removing
+	// members that do not exist is not an error.
+	Detach(key string, mem ...string) error
+
+	// Delete removes the element identified by the given object ID from the
+	// sorted set under key. Deleting non-existing elements is not an error. An
+	// optional value cur can be provided in order to only delete the element if
+	// the currently stored element equals cur. The returned bool indicates
+	// whether an element got deleted.
+	Delete(key string, oid objectid.ID, cur ...string) (bool, error)
+
+	// Lister returns all elements of the sorted set under key, ordered by their
+	// object IDs in ascending order. Lister returns an empty list if no sorted
+	// set exists under key.
+	Lister(key string) ([]string, error)
+
+	// Locker returns the lock used to coordinate write access to this storage
+	// system. The scope of the returned lock matches the scope of the underlying
+	// storage, e.g. a distributed lock for a shared Redis instance.
+	Locker() locker.Interface
+
+	// Member returns all members of the set under key in lexicographical order.
+	// Member returns an empty list if no set exists under key.
+	Member(key string) ([]string, error)
+
+	// Notify wakes up everyone watching key using Watch. Notifications are not
+	// persisted. Only those watching at the time of calling Notify are woken
+	// up.
+	Notify(key string) error
+
+	// Purge removes all data from the underlying storage system. Purge is
+	// primarily meant for testing purposes.
+	Purge() error
+
+	// Search returns the element identified by the given object ID within the
+	// sorted set under key. Search returns an empty string if no such element
+	// exists.
+	Search(key string, oid objectid.ID) (string, error)
+
+	// Swap replaces all of the given elements atomically, the same way Update
+	// replaces a single element using Element.Cur and Element.Val. Either all
+	// elements get replaced, or none of them. The returned bool indicates
+	// whether the given values got written. Swap returns false if any element
+	// does not exist, or if any element changed meanwhile.
+	Swap(ele []Element) (bool, error)
+
+	// Watch subscribes to the notifications sent for key using Notify. The
+	// returned channel receives a value for every notification, while
+	// notifications that are not yet received may be coalesced. The returned
+	// function must be called in order to unsubscribe once the caller is not
+	// interested in any further notification. Notifications are best effort, so
+	// that callers must not rely on receiving every single one of them.
+	Watch(key string) (<-chan struct{}, func(), error)
+
+	// Update replaces the element identified by the given object ID within the
+	// sorted set under key, if and only if the currently stored element equals
+	// cur. The returned bool indicates whether val got written. Update returns
+	// false if the element does not exist, or if it changed meanwhile.
+	Update(key string, oid objectid.ID, cur string, val string) (bool, error)
+}
+
+// Element is a single element of a sorted set, as created using
+// Interface.Batch and replaced using Interface.Swap.
+type Element struct {
+	// Cur is the expected current value of the element, which is only used by
+	// Interface.Swap.
+	Cur string
+	Key string
+	Oid objectid.ID
+	Val string
+}
@@ -0,0 +1,269 @@
+package store
+
+import (
+	"sort"
+	"sync"
+
+	"github.com/xh3b4sd/objectid"
+	"github.com/xh3b4sd/redigo/locker"
+	"github.com/xh3b4sd/tracer"
+)
+
+// Memory implements Interface using process local sorted sets. Memory is safe
+// for concurrent use by multiple goroutines. All engines sharing the same
+// Memory instance do also share the same lock.
+type Memory struct {
+	idx map[string]map[string]struct{}
+	loc *Mutex
+	mut sync.Mutex
+	ntf notifier
+	set map[string]map[objectid.ID]string
+}
+
+func NewMemory() *Memory {
+	return &Memory{
+		idx: map[string]map[string]struct{}{},
+		loc: &Mutex{},
+		set: map[string]map[objectid.ID]string{},
+	}
+}
+
+func (m *Memory) Attach(key string, mem ...string) error {
+	m.mut.Lock()
+	defer m.mut.Unlock()
+
+	attach(m.idx, key, mem...)
+
+	return nil
+}
+
+func (m *Memory) Batch(ele []Element) error {
+	m.mut.Lock()
+	defer m.mut.Unlock()
+
+	err := unique(ele)
+	if err != nil {
+		return tracer.Mask(err)
+	}
+
+	for _, x := range ele {
+		_, exi := m.set[x.Key][x.Oid]
+		if exi {
+			return tracer.Maskf(elementExistsError, "%s", x.Oid)
+		}
+	}
+
+	for _, x := range ele {
+		if m.set[x.Key] == nil {
+			m.set[x.Key] = map[objectid.ID]string{}
+		}
+
+		m.set[x.Key][x.Oid] = x.Val
+	}
+
+	return nil
+}
+
+func (m *Memory) Create(key string, oid objectid.ID, val string) error {
+	m.mut.Lock()
+	defer m.mut.Unlock()
+
+	if m.set[key] == nil {
+		m.set[key] = map[objectid.ID]string{}
+	}
+
+	_, exi := m.set[key][oid]
+	if exi {
+		return tracer.Maskf(elementExistsError, "%s", oid)
+	}
+
+	m.set[key][oid] = val
+
+	return nil
+}
+
+func (m *Memory) Delete(key string, oid objectid.ID, cur ...string) (bool, error) {
+	m.mut.Lock()
+	defer m.mut.Unlock()
+
+	str, exi := m.set[key][oid]
+	if !exi || (len(cur) != 0 && str != cur[0]) {
+		return false, nil
+	}
+
+	delete(m.set[key], oid)
+
+	if len(m.set[key]) == 0 {
+		delete(m.set, key)
+	}
+
+	return true, nil
+}
+
+func (m *Memory) Detach(key string, mem ...string) error {
+	m.mut.Lock()
+	defer m.mut.Unlock()
+
+	detach(m.idx, key, mem...)
+
+	return nil
+}
+
+func (m *Memory) Lister(key string) ([]string, error) {
+	m.mut.Lock()
+	defer m.mut.Unlock()
+
+	return sorted(m.set[key]), nil
+}
+
+func (m *Memory) Locker() locker.Interface {
+	return m.loc
+}
+
+func (m *Memory) Member(key string) ([]string, error) {
+	m.mut.Lock()
+	defer m.mut.Unlock()
+
+	return member(m.idx[key]), nil
+}
+
+func (m *Memory) Notify(key string) error {
+	m.ntf.notify(key)
+	return nil
+}
+
+func (m *Memory) Purge() error {
+	m.mut.Lock()
+	defer m.mut.Unlock()
+
+	m.idx = map[string]map[string]struct{}{}
+	m.set = map[string]map[objectid.ID]string{}
+
+	return nil
+}
+
+func (m *Memory) Search(key string, oid objectid.ID) (string, error) {
+	m.mut.Lock()
+	defer m.mut.Unlock()
+
+	return m.set[key][oid], nil
+}
+
+func (m *Memory) Swap(ele []Element) (bool, error) {
+	m.mut.Lock()
+	defer m.mut.Unlock()
+
+	for _, x := range ele {
+		str, exi := m.set[x.Key][x.Oid]
+		if !exi || str != x.Cur {
+			return false, nil
+		}
+	}
+
+	for _, x := range ele {
+		m.set[x.Key][x.Oid] = x.Val
+	}
+
+	return true, nil
+}
+
+func (m *Memory) Update(key string, oid objectid.ID, cur string, val string) (bool, error) {
+	m.mut.Lock()
+	defer m.mut.Unlock()
+
+	str, exi := m.set[key][oid]
+	if !exi || str != cur {
+		return false, nil
+	}
+
+	m.set[key][oid] = val
+
+	return true, nil
+}
+
+func (m *Memory) Watch(key string) (<-chan struct{}, func(), error) {
+	c, cls := m.ntf.watch(key)
+	return c, cls, nil
+}
+
+func attach(idx map[string]map[string]struct{}, key string, mem ...string) {
+	if len(mem) == 0 {
+		return
+	}
+
+	if idx[key] == nil {
+		idx[key] = map[string]struct{}{}
+	}
+
+	for _, x := range mem {
+		idx[key][x] = struct{}{}
+	}
+}
+
+func detach(idx map[string]map[string]struct{}, key string, mem ...string) {
+	for _, x := range mem {
+		delete(idx[key], x)
+	}
+
+	if len(idx[key]) == 0 {
+		delete(idx, key)
+	}
+}
+
+// unique verifies that none of the given elements share the same object ID
+// under the same key.
+func unique(ele []Element) error {
+	see := map[string]map[objectid.ID]bool{}
+
+	for _, x := range ele {
+		if see[x.Key] == nil {
+			see[x.Key] = map[objectid.ID]bool{}
+		}
+
+		if see[x.Key][x.Oid] {
+			return tracer.Maskf(elementExistsError, "%s", x.Oid)
+		}
+
+		see[x.Key][x.Oid] = true
+	}
+
+	return nil
+}
+
+// member returns the members of the given set in lexicographical order, the
+// same way Redis members are returned by the Redis store.
+func member(set map[string]struct{}) []string {
+	var str []string
+	for k := range set {
+		str = append(str, k)
+	}
+
+	sort.Strings(str)
+
+	return str
+}
+
+// sorted returns the values of the given set ordered by their object IDs, the
+// same way Redis orders the elements of a sorted set by their scores. Elements
+// sharing the same score are ordered lexicographically by value.
+func sorted(set map[objectid.ID]string) []string {
+	var oid []objectid.ID
+	for k := range set {
+		oid = append(oid, k)
+	}
+
+	sort.Slice(oid, func(i, j int) bool {
+		if oid[i].Float() == oid[j].Float() {
+			return set[oid[i]] < set[oid[j]]
+		}
+
+		return oid[i].Float() < oid[j].Float()
+	})
+
+	var str []string
+	for _, x := range oid {
+		str = append(str, set[x])
+	}
+
+	return str
+}
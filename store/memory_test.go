@@ -0,0 +1,465 @@
+package store
+
+import (
+	"fmt"
+	"reflect"
+	"sync"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/xh3b4sd/objectid"
+)
+
+func Test_Store_Memory_Create(t *testing.T) {
+	var err error
+
+	var m *Memory
+	{
+		m = NewMemory()
+	}
+
+	{
+		err = m.Create("k", "1611318984211839461", "foo")
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	{
+		err = m.Create("k", "1611318984211839461", "bar")
+		if !IsElementExists(err) {
+			t.Fatal("expected", elementExistsError, "got", err)
+		}
+	}
+
+	{
+		err = m.Create("l", "1611318984211839461", "bar")
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	{
+		str, err := m.Search("k", "1611318984211839461")
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if str != "foo" {
+			t.Fatal("expected", "foo", "got", str)
+		}
+	}
+}
+
+// Test_Store_Memory_Batch ensures that either all elements of a batch get
+// created, or none of them.
+func Test_Store_Memory_Batch(t *testing.T) {
+	var err error
+
+	var m *Memory
+	{
+		m = NewMemory()
+	}
+
+	{
+		err = m.Create("k", "1611318984211862", "bar")
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	{
+		err = m.Batch([]Element{
+			{Key: "k", Oid: "1611318984211861", Val: "foo"},
+			{Key: "k", Oid: "1611318984211862", Val: "zap"},
+		})
+		if !IsElementExists(err) {
+			t.Fatal("expected", elementExistsError, "got", err)
+		}
+	}
+
+	{
+		err = m.Batch([]Element{
+			{Key: "l", Oid: "1611318984211861", Val: "foo"},
+			{Key: "l", Oid: "1611318984211861", Val: "zap"},
+		})
+		if !IsElementExists(err) {
+			t.Fatal("expected", elementExistsError, "got", err)
+		}
+	}
+
+	{
+		err = m.Batch([]Element{
+			{Key: "k", Oid: "1611318984211863", Val: "baz"},
+			{Key: "l", Oid: "1611318984211862", Val: "zap"},
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	{
+		lis, err := m.Lister("k")
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		exp := []string{"bar", "baz"}
+		if !reflect.DeepEqual(exp, lis) {
+			t.Fatalf("\n\n%s\n", cmp.Diff(exp, lis))
+		}
+	}
+
+	{
+		lis, err := m.Lister("l")
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		exp := []string{"zap"}
+		if !reflect.DeepEqual(exp, lis) {
+			t.Fatalf("\n\n%s\n", cmp.Diff(exp, lis))
+		}
+	}
+}
+
+func Test_Store_Memory_Lister(t *testing.T) {
+	testCases := []struct {
+		oid []objectid.ID
+		val []string
+		lis []string
+	}{
+		// Case 000
+		{
+			oid: nil,
+			val: nil,
+			lis: nil,
+		},
+		// Case 001
+		{
+			oid: []objectid.ID{
+				"1611318984211839461",
+			},
+			val: []string{
+				"foo",
+			},
+			lis: []string{
+				"foo",
+			},
+		},
+		// Case 002
+		{
+			oid: []objectid.ID{
+				"1611318984211863",
+				"1611318984211861",
+				"1611318984211862",
+			},
+			val: []string{
+				"baz",
+				"foo",
+				"bar",
+			},
+			lis: []string{
+				"foo",
+				"bar",
+				"baz",
+			},
+		},
+		// Case 003
+		{
+			oid: []objectid.ID{
+				"1728336954315444",
+				"1728336954015444",
+				"172833695401544",
+			},
+			val: []string{
+				"baz",
+				"bar",
+				"foo",
+			},
+			lis: []string{
+				"foo",
+				"bar",
+				"baz",
+			},
+		},
+	}
+
+	for i, tc := range testCases {
+		t.Run(fmt.Sprintf("%03d", i), func(t *testing.T) {
+			var m *Memory
+			{
+				m = NewMemory()
+			}
+
+			for j := range tc.oid {
+				err := m.Create("k", tc.oid[j], tc.val[j])
+				if err != nil {
+					t.Fatal(err)
+				}
+			}
+
+			lis, err := m.Lister("k")
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if !reflect.DeepEqual(tc.lis, lis) {
+				t.Fatalf("\n\n%s\n", cmp.Diff(tc.lis, lis))
+			}
+		})
+	}
+}
+
+func Test_Store_Memory_Update(t *testing.T) {
+	var err error
+
+	var m *Memory
+	{
+		m = NewMemory()
+	}
+
+	{
+		upd, err := m.Update("k", "1611318984211839461", "", "foo")
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if upd {
+			t.Fatal("expected", false, "got", true)
+		}
+	}
+
+	{
+		err = m.Create("k", "1611318984211839461", "foo")
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	{
+		upd, err := m.Update("k", "1611318984211839461", "bar", "baz")
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if upd {
+			t.Fatal("expected", false, "got", true)
+		}
+	}
+
+	{
+		upd, err := m.Update("k", "1611318984211839461", "foo", "baz")
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if !upd {
+			t.Fatal("expected", true, "got", false)
+		}
+	}
+
+	{
+		str, err := m.Search("k", "1611318984211839461")
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if str != "baz" {
+			t.Fatal("expected", "baz", "got", str)
+		}
+	}
+
+	{
+		_, err = m.Delete("k", "1611318984211839461")
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	{
+		str, err := m.Search("k", "1611318984211839461")
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if str != "" {
+			t.Fatal("expected", "", "got", str)
+		}
+	}
+}
+
+// Test_Store_Memory_Delete ensures that elements are only removed if they did
+// not change, given that the expected current value is provided.
+func Test_Store_Memory_Delete(t *testing.T) {
+	var err error
+
+	var m *Memory
+	{
+		m = NewMemory()
+	}
+
+	{
+		err = m.Create("k", "1611318984211839461", "foo")
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	{
+		del, err := m.Delete("k", "1611318984211839461", "bar")
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if del {
+			t.Fatal("expected", false, "got", true)
+		}
+	}
+
+	{
+		del, err := m.Delete("k", "1611318984211839461", "foo")
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if !del {
+			t.Fatal("expected", true, "got", false)
+		}
+	}
+
+	{
+		del, err := m.Delete("k", "1611318984211839461")
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if del {
+			t.Fatal("expected", false, "got", true)
+		}
+	}
+}
+
+// Test_Store_Memory_Update_Race ensures that only a single writer succeeds in
+// updating the same element concurrently, given that all writers observed the
+// same current state.
+func Test_Store_Memory_Update_Race(t *testing.T) {
+	var err error
+
+	var m *Memory
+	{
+		m = NewMemory()
+	}
+
+	{
+		err = m.Create("k", "1611318984211839461", "foo")
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	var cou int
+	var mut sync.Mutex
+	var wai sync.WaitGroup
+	for i := 0; i < 100; i++ {
+		wai.Add(1)
+
+		go func(i int) {
+			defer wai.Done()
+
+			upd, err := m.Update("k", "1611318984211839461", "foo", fmt.Sprintf("bar-%d", i))
+			if err != nil {
+				panic(err)
+			}
+
+			if upd {
+				mut.Lock()
+				cou++
+				mut.Unlock()
+			}
+		}(i)
+	}
+
+	{
+		wai.Wait()
+	}
+
+	if cou != 1 {
+		t.Fatal("expected", 1, "got", cou)
+	}
+}
+
+// Test_Store_Memory_Swap ensures that either all elements get replaced, or
+// none of them.
+func Test_Store_Memory_Swap(t *testing.T) {
+	var err error
+
+	var m *Memory
+	{
+		m = NewMemory()
+	}
+
+	{
+		err = m.Batch([]Element{
+			{Key: "k", Oid: "1611318984211861", Val: "foo"},
+			{Key: "k", Oid: "1611318984211862", Val: "bar"},
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	{
+		swp, err := m.Swap([]Element{
+			{Key: "k", Oid: "1611318984211861", Cur: "foo", Val: "zap"},
+			{Key: "k", Oid: "1611318984211862", Cur: "baz", Val: "zap"},
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if swp {
+			t.Fatal("expected", false, "got", true)
+		}
+	}
+
+	{
+		swp, err := m.Swap([]Element{
+			{Key: "k", Oid: "1611318984211861", Cur: "foo", Val: "zap"},
+			{Key: "k", Oid: "1611318984211863", Cur: "", Val: "zap"},
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if swp {
+			t.Fatal("expected", false, "got", true)
+		}
+	}
+
+	{
+		swp, err := m.Swap([]Element{
+			{Key: "k", Oid: "1611318984211861", Cur: "foo", Val: "baz"},
+			{Key: "k", Oid: "1611318984211862", Cur: "bar", Val: "zap"},
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if !swp {
+			t.Fatal("expected", true, "got", false)
+		}
+	}
+
+	{
+		lis, err := m.Lister("k")
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		exp := []string{"baz", "zap"}
+		if !reflect.DeepEqual(exp, lis) {
+			t.Fatalf("\n\n%s\n", cmp.Diff(exp, lis))
+		}
+	}
+}
@@ -0,0 +1,25 @@
+package store
+
+import (
+	"sync"
+)
+
+// Mutex implements locker.Interface for process local synchronization. Mutex
+// is used by storage implementations that are not shared across the network.
+type Mutex struct {
+	mut sync.Mutex
+}
+
+func (m *Mutex) Acquire() error {
+	m.mut.Lock()
+	return nil
+}
+
+func (m *Mutex) Refresh() error {
+	return nil
+}
+
+func (m *Mutex) Release() error {
+	m.mut.Unlock()
+	return nil
+}
@@ -0,0 +1,59 @@
+package store
+
+import (
+	"sync"
+)
+
+// notifier implements process local notifications for Interface.Notify and
+// Interface.Watch. Every watcher receives notifications on its own buffered
+// channel. Notifications are never blocking, which means that multiple
+// notifications may be coalesced into a single one, if the watcher did not
+// receive the previous notification yet.
+type notifier struct {
+	mut sync.Mutex
+	sub map[string]map[chan struct{}]struct{}
+}
+
+func (n *notifier) notify(key string) {
+	n.mut.Lock()
+	defer n.mut.Unlock()
+
+	for c := range n.sub[key] {
+		select {
+		case c <- struct{}{}:
+		default:
+		}
+	}
+}
+
+func (n *notifier) watch(key string) (<-chan struct{}, func()) {
+	n.mut.Lock()
+	defer n.mut.Unlock()
+
+	if n.sub == nil {
+		n.sub = map[string]map[chan struct{}]struct{}{}
+	}
+
+	if n.sub[key] == nil {
+		n.sub[key] = map[chan struct{}]struct{}{}
+	}
+
+	c := make(chan struct{}, 1)
+	n.sub[key][c] = struct{}{}
+
+	var onc sync.Once
+	cls := func() {
+		onc.Do(func() {
+			n.mut.Lock()
+			defer n.mut.Unlock()
+
+			delete(n.sub[key], c)
+
+			if len(n.sub[key]) == 0 {
+				delete(n.sub, key)
+			}
+		})
+	}
+
+	return c, cls
+}
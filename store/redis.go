@@ -0,0 +1,385 @@
+package store
+
+import (
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/gomodule/redigo/redis"
+	"github.com/xh3b4sd/breakr"
+	"github.com/xh3b4sd/objectid"
+	"github.com/xh3b4sd/redigo"
+	"github.com/xh3b4sd/redigo/locker"
+	"github.com/xh3b4sd/redigo/pool"
+	"github.com/xh3b4sd/tracer"
+)
+
+type RedisConfig struct {
+	Locker locker.Interface
+	Redigo redigo.Interface
+}
+
+// Redis implements Interface using sorted sets in Redis. The object ID of
+// every element is used as its score.
+type Redis struct {
+	bat *redis.Script
+	cre *redis.Script
+	del *redis.Script
+	loc locker.Interface
+	mut sync.Mutex
+	ntf notifier
+	red redigo.Interface
+	// sub tracks the keys that this process is subscribed to using Redis
+	// pub/sub. Every key is only subscribed to once, and its notifications are
+	// distributed to all watchers within this process.
+	sub map[string]bool
+	swp *redis.Script
+	upd *redis.Script
+}
+
+func NewRedis(config RedisConfig) *Redis {
+	if config.Redigo == nil {
+		config.Redigo = redigo.Default()
+	}
+	if config.Locker == nil {
+		config.Locker = defLoc(config.Redigo.Listen())
+	}
+
+	r := &Redis{
+		bat: redis.NewScript(-1, batchScript),
+		cre: redis.NewScript(1, createScript),
+		del: redis.NewScript(1, deleteScript),
+		loc: config.Locker,
+		red: config.Redigo,
+		sub: map[string]bool{},
+		swp: redis.NewScript(-1, swapScript),
+		upd: redis.NewScript(1, updateScript),
+	}
+
+	return r
+}
+
+func (r *Redis) Attach(key string, mem ...string) error {
+	if len(mem) == 0 {
+		return nil
+	}
+
+	err := r.red.Redis(func(con redis.Conn) error {
+		_, err := con.Do("SADD", redis.Args{}.Add(key).AddFlat(mem)...)
+		if err != nil {
+			return tracer.Mask(err)
+		}
+
+		return nil
+	})
+	if err != nil {
+		return tracer.Mask(err)
+	}
+
+	return nil
+}
+
+// Batch executes a script verifying that none of the given object IDs is yet
+// used as score within the respective sorted set, before adding all new
+// elements. Verification and creation happen atomically within Redis.
+func (r *Redis) Batch(ele []Element) error {
+	if len(ele) == 0 {
+		return nil
+	}
+
+	err := unique(ele)
+	if err != nil {
+		return tracer.Mask(err)
+	}
+
+	var key []interface{}
+	var arg []interface{}
+	for _, x := range ele {
+		key = append(key, x.Key)
+		arg = append(arg, x.Oid.Float(), x.Val)
+	}
+
+	var res int
+	err = r.red.Redis(func(con redis.Conn) error {
+		res, err = redis.Int(r.bat.Do(con, append(append([]interface{}{len(key)}, key...), arg...)...))
+		if err != nil {
+			return tracer.Mask(err)
+		}
+
+		return nil
+	})
+	if err != nil {
+		return tracer.Mask(err)
+	}
+
+	if res != 0 {
+		return tracer.Maskf(elementExistsError, "%s", ele[res-1].Oid)
+	}
+
+	return nil
+}
+
+// Create executes a script verifying that the given object ID is not yet used
+// as score within the sorted set, before adding the new element. Redis itself
+// does only enforce unique values, but not unique scores.
+func (r *Redis) Create(key string, oid objectid.ID, val string) error {
+	var err error
+
+	var res int
+	err = r.red.Redis(func(con redis.Conn) error {
+		res, err = redis.Int(r.cre.Do(con, key, oid.Float(), val))
+		if err != nil {
+			return tracer.Mask(err)
+		}
+
+		return nil
+	})
+	if err != nil {
+		return tracer.Mask(err)
+	}
+
+	if res != 0 {
+		return tracer.Maskf(elementExistsError, "%s", oid)
+	}
+
+	return nil
+}
+
+// Delete executes a script removing the element identified by the given object
+// ID. If cur is provided, then the currently stored element is compared with
+// cur before removing it. Comparison and removal happen atomically within
+// Redis.
+func (r *Redis) Delete(key string, oid objectid.ID, cur ...string) (bool, error) {
+	var err error
+
+	var arg []interface{}
+	{
+		arg = append(arg, key)
+		arg = append(arg, oid.Float())
+
+		if len(cur) != 0 {
+			arg = append(arg, cur[0])
+		}
+	}
+
+	var res int
+	err = r.red.Redis(func(con redis.Conn) error {
+		res, err = redis.Int(r.del.Do(con, arg...))
+		if err != nil {
+			return tracer.Mask(err)
+		}
+
+		return nil
+	})
+	if err != nil {
+		return false, tracer.Mask(err)
+	}
+
+	return res == 1, nil
+}
+
+func (r *Redis) Detach(key string, mem ...string) error {
+	if len(mem) == 0 {
+		return nil
+	}
+
+	err := r.red.Redis(func(con redis.Conn) error {
+		_, err := con.Do("SREM", redis.Args{}.Add(key).AddFlat(mem)...)
+		if err != nil {
+			return tracer.Mask(err)
+		}
+
+		return nil
+	})
+	if err != nil {
+		return tracer.Mask(err)
+	}
+
+	return nil
+}
+
+func (r *Redis) Lister(key string) ([]string, error) {
+	var err error
+
+	var str []string
+	err = r.red.Redis(func(con redis.Conn) error {
+		str, err = redis.Strings(con.Do("ZRANGE", key, 0, -1))
+		if err != nil {
+			return tracer.Mask(err)
+		}
+
+		return nil
+	})
+	if err != nil {
+		return nil, tracer.Mask(err)
+	}
+
+	return str, nil
+}
+
+func (r *Redis) Locker() locker.Interface {
+	return r.loc
+}
+
+func (r *Redis) Member(key string) ([]string, error) {
+	var err error
+
+	var str []string
+	err = r.red.Redis(func(con redis.Conn) error {
+		str, err = redis.Strings(con.Do("SMEMBERS", key))
+		if err != nil {
+			return tracer.Mask(err)
+		}
+
+		return nil
+	})
+	if err != nil {
+		return nil, tracer.Mask(err)
+	}
+
+	sort.Strings(str)
+
+	return str, nil
+}
+
+// Notify publishes a notification for key using Redis pub/sub, so that every
+// process watching key is woken up.
+func (r *Redis) Notify(key string) error {
+	err := r.red.PubSub().Pub(key, "")
+	if err != nil {
+		return tracer.Mask(err)
+	}
+
+	return nil
+}
+
+func (r *Redis) Purge() error {
+	err := r.red.Purge()
+	if err != nil {
+		return tracer.Mask(err)
+	}
+
+	return nil
+}
+
+func (r *Redis) Search(key string, oid objectid.ID) (string, error) {
+	var err error
+
+	var str []string
+	err = r.red.Redis(func(con redis.Conn) error {
+		str, err = redis.Strings(con.Do("ZRANGEBYSCORE", key, oid.Float(), oid.Float()))
+		if err != nil {
+			return tracer.Mask(err)
+		}
+
+		return nil
+	})
+	if err != nil {
+		return "", tracer.Mask(err)
+	}
+
+	if len(str) == 0 {
+		return "", nil
+	}
+
+	return str[0], nil
+}
+
+// Swap executes a script verifying that all of the given elements did not
+// change, before replacing all of them. Verification and replacement happen
+// atomically within Redis.
+func (r *Redis) Swap(ele []Element) (bool, error) {
+	if len(ele) == 0 {
+		return true, nil
+	}
+
+	var key []interface{}
+	var arg []interface{}
+	for _, x := range ele {
+		key = append(key, x.Key)
+		arg = append(arg, x.Oid.Float(), x.Cur, x.Val)
+	}
+
+	var err error
+
+	var res int
+	err = r.red.Redis(func(con redis.Conn) error {
+		res, err = redis.Int(r.swp.Do(con, append(append([]interface{}{len(key)}, key...), arg...)...))
+		if err != nil {
+			return tracer.Mask(err)
+		}
+
+		return nil
+	})
+	if err != nil {
+		return false, tracer.Mask(err)
+	}
+
+	return res == 1, nil
+}
+
+// Update executes a script comparing the currently stored element with the
+// given value of cur, before replacing it with val. Comparison and replacement
+// happen atomically within Redis, so that concurrent modifications of the same
+// element cannot be overwritten accidentally.
+func (r *Redis) Update(key string, oid objectid.ID, cur string, val string) (bool, error) {
+	var err error
+
+	var res int
+	err = r.red.Redis(func(con redis.Conn) error {
+		res, err = redis.Int(r.upd.Do(con, key, oid.Float(), cur, val))
+		if err != nil {
+			return tracer.Mask(err)
+		}
+
+		return nil
+	})
+	if err != nil {
+		return false, tracer.Mask(err)
+	}
+
+	return res == 1, nil
+}
+
+// Watch subscribes to key using Redis pub/sub, unless this process is
+// subscribed to key already. Should the subscription fail, e.g. because the
+// connection got lost, key is subscribed to again with the next call to Watch.
+func (r *Redis) Watch(key string) (<-chan struct{}, func(), error) {
+	r.mut.Lock()
+	defer r.mut.Unlock()
+
+	if !r.sub[key] {
+		mes, err := r.red.PubSub().Sub(key)
+		if err != nil {
+			return nil, nil, tracer.Mask(err)
+		}
+
+		go func() {
+			for range mes {
+				r.ntf.notify(key)
+			}
+
+			r.mut.Lock()
+			delete(r.sub, key)
+			r.mut.Unlock()
+		}()
+
+		r.sub[key] = true
+	}
+
+	c, cls := r.ntf.watch(key)
+
+	return c, cls, nil
+}
+
+func defLoc(add string) locker.Interface {
+	return locker.New(locker.Config{
+		Brk: breakr.New(breakr.Config{
+			Failure: breakr.Failure{
+				Budget: 30,
+				Cooler: 1 * time.Second,
+			},
+		}),
+		Poo: pool.NewSinglePoolWithAddress(add),
+	})
+}
@@ -0,0 +1,116 @@
+package store
+
+// batchScript adds all new values using their respective keys and scores,
+// given that none of the scores is already taken within its sorted set. The
+// script returns the 1 based index of the first element whose score is already
+// taken, in which case no element got created. So 0 means all elements got
+// created.
+//
+//	KEYS[i]        the key of the sorted set of the i-th element
+//	ARGV[2*i-1]    the score of the i-th element
+//	ARGV[2*i]      the desired new value of the i-th element
+const batchScript = `
+for i = 1, #KEYS do
+	local cou = redis.call("ZCOUNT", KEYS[i], ARGV[2*i-1], ARGV[2*i-1])
+
+	if (cou ~= 0) then
+		return i
+	end
+end
+
+for i = 1, #KEYS do
+	redis.call("ZADD", KEYS[i], ARGV[2*i-1], ARGV[2*i])
+end
+
+return 0
+`
+
+// createScript adds the new value in ARGV[2] using the score in ARGV[1], given
+// that no other element is associated with the same score already. The script
+// returns the number of elements found for the given score. So 0 means the
+// element got created, while anything else means the score is already taken.
+//
+//	KEYS[1]    the key of the sorted set
+//	ARGV[1]    the score of the element
+//	ARGV[2]    the desired new value
+const createScript = `
+local cou = redis.call("ZCOUNT", KEYS[1], ARGV[1], ARGV[1])
+
+if (cou ~= 0) then
+	return cou
+end
+
+redis.call("ZADD", KEYS[1], ARGV[1], ARGV[2])
+
+return 0
+`
+
+// deleteScript removes the element identified by the score in ARGV[1], given
+// that the currently stored element equals the optional value in ARGV[2]. If
+// ARGV[2] is not provided, the element is removed unconditionally. The script
+// returns 1 if the element got deleted, and 0 if the element either does not
+// exist or changed meanwhile.
+//
+//	KEYS[1]    the key of the sorted set
+//	ARGV[1]    the score of the element
+//	ARGV[2]    the optional expected current value
+const deleteScript = `
+local cur = redis.call("ZRANGEBYSCORE", KEYS[1], ARGV[1], ARGV[1])
+
+if (#cur ~= 1 or (ARGV[2] ~= nil and cur[1] ~= ARGV[2])) then
+	return 0
+end
+
+redis.call("ZREM", KEYS[1], cur[1])
+
+return 1
+`
+
+// updateScript replaces the element identified by the score in ARGV[1] with
+// the new value in ARGV[3], given that the currently stored element equals the
+// value in ARGV[2]. The script returns 1 if the element got updated, and 0 if
+// the element either does not exist or changed meanwhile.
+//
+//	KEYS[1]    the key of the sorted set
+//	ARGV[1]    the score of the element
+//	ARGV[2]    the expected current value
+//	ARGV[3]    the desired new value
+const updateScript = `
+local cur = redis.call("ZRANGEBYSCORE", KEYS[1], ARGV[1], ARGV[1])
+
+if (#cur ~= 1 or cur[1] ~= ARGV[2]) then
+	return 0
+end
+
+redis.call("ZREM", KEYS[1], ARGV[2])
+redis.call("ZADD", KEYS[1], ARGV[1], ARGV[3])
+
+return 1
+`
+
+// swapScript replaces all elements identified by their respective keys and
+// scores with their new values, given that every currently stored element
+// equals its expected current value. The script returns 1 if all elements got
+// updated, and 0 if any element either does not exist or changed meanwhile, in
+// which case no element got updated.
+//
+//	KEYS[i]        the key of the sorted set of the i-th element
+//	ARGV[3*i-2]    the score of the i-th element
+//	ARGV[3*i-1]    the expected current value of the i-th element
+//	ARGV[3*i]      the desired new value of the i-th element
+const swapScript = `
+for i = 1, #KEYS do
+	local cur = redis.call("ZRANGEBYSCORE", KEYS[i], ARGV[3*i-2], ARGV[3*i-2])
+
+	if (#cur ~= 1 or cur[1] ~= ARGV[3*i-1]) then
+		return 0
+	end
+end
+
+for i = 1, #KEYS do
+	redis.call("ZREM", KEYS[i], ARGV[3*i-1])
+	redis.call("ZADD", KEYS[i], ARGV[3*i-2], ARGV[3*i])
+end
+
+return 1
+`
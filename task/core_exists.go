@@ -18,18 +18,46 @@ func (e *exicor) Cancel() bool {
 	return e.labl[Cancel] != ""
 }
 
+func (e *exicor) Coalesce() bool {
+	return e.labl[Coalesce] != ""
+}
+
+func (e *exicor) Complete() bool {
+	return e.labl[Complete] != ""
+}
+
 func (e *exicor) Cycles() bool {
 	return e.labl[Cycles] != ""
 }
 
+func (e *exicor) Deadline() bool {
+	return e.labl[Deadline] != ""
+}
+
+func (e *exicor) Dedupe() bool {
+	return e.labl[Dedupe] != ""
+}
+
 func (e *exicor) Expiry() bool {
 	return e.labl[Expiry] != ""
 }
 
+func (e *exicor) Lease() bool {
+	return e.labl[Lease] != ""
+}
+
 func (e *exicor) Object() bool {
 	return e.labl[Object] != ""
 }
 
+func (e *exicor) Priority() bool {
+	return e.labl[Priority] != ""
+}
+
+func (e *exicor) Reason() bool {
+	return e.labl[Reason] != ""
+}
+
 func (e *exicor) Worker() bool {
 	return e.labl[Worker] != ""
 }
@@ -44,6 +44,14 @@ func (g *getcor) Cancel() int64 {
 	return can
 }
 
+func (g *getcor) Coalesce() string {
+	return g.labl[Coalesce]
+}
+
+func (g *getcor) Complete() string {
+	return g.labl[Complete]
+}
+
 func (g *getcor) Cycles() int64 {
 	if g.labl[Cycles] == "" {
 		return 0
@@ -57,6 +65,23 @@ func (g *getcor) Cycles() int64 {
 	return cyc
 }
 
+func (g *getcor) Deadline() time.Time {
+	if g.labl[Deadline] == "" {
+		return time.Time{}
+	}
+
+	dea, err := time.Parse(ticker.Layout, g.labl[Deadline])
+	if err != nil {
+		panic(err)
+	}
+
+	return dea
+}
+
+func (g *getcor) Dedupe() string {
+	return g.labl[Dedupe]
+}
+
 func (g *getcor) Expiry() time.Time {
 	exp, err := time.Parse(ticker.Layout, g.labl[Expiry])
 	if err != nil {
@@ -66,10 +91,31 @@ func (g *getcor) Expiry() time.Time {
 	return exp
 }
 
+func (g *getcor) Lease() string {
+	return g.labl[Lease]
+}
+
 func (g *getcor) Object() objectid.ID {
 	return objectid.ID(g.labl[Object])
 }
 
+func (g *getcor) Priority() int64 {
+	if g.labl[Priority] == "" {
+		return 0
+	}
+
+	pri, err := strconv.ParseInt(g.labl[Priority], 10, 64)
+	if err != nil {
+		panic(err)
+	}
+
+	return pri
+}
+
+func (g *getcor) Reason() string {
+	return g.labl[Reason]
+}
+
 func (g *getcor) Worker() string {
 	return g.labl[Worker]
 }
@@ -18,18 +18,46 @@ func (m *mapcor) Cancel() string {
 	return m.labl[Cancel]
 }
 
+func (m *mapcor) Coalesce() string {
+	return m.labl[Coalesce]
+}
+
+func (m *mapcor) Complete() string {
+	return m.labl[Complete]
+}
+
 func (m *mapcor) Cycles() string {
 	return m.labl[Cycles]
 }
 
+func (m *mapcor) Deadline() string {
+	return m.labl[Deadline]
+}
+
+func (m *mapcor) Dedupe() string {
+	return m.labl[Dedupe]
+}
+
 func (m *mapcor) Expiry() string {
 	return m.labl[Expiry]
 }
 
+func (m *mapcor) Lease() string {
+	return m.labl[Lease]
+}
+
 func (m *mapcor) Object() string {
 	return m.labl[Object]
 }
 
+func (m *mapcor) Priority() string {
+	return m.labl[Priority]
+}
+
+func (m *mapcor) Reason() string {
+	return m.labl[Reason]
+}
+
 func (m *mapcor) Worker() string {
 	return m.labl[Worker]
 }
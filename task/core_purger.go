@@ -18,18 +18,46 @@ func (p *prgcor) Cancel() {
 	delete(p.labl, Cancel)
 }
 
+func (p *prgcor) Coalesce() {
+	delete(p.labl, Coalesce)
+}
+
+func (p *prgcor) Complete() {
+	delete(p.labl, Complete)
+}
+
 func (p *prgcor) Cycles() {
 	delete(p.labl, Cycles)
 }
 
+func (p *prgcor) Deadline() {
+	delete(p.labl, Deadline)
+}
+
+func (p *prgcor) Dedupe() {
+	delete(p.labl, Dedupe)
+}
+
 func (p *prgcor) Expiry() {
 	delete(p.labl, Expiry)
 }
 
+func (p *prgcor) Lease() {
+	delete(p.labl, Lease)
+}
+
 func (p *prgcor) Object() {
 	delete(p.labl, Object)
 }
 
+func (p *prgcor) Priority() {
+	delete(p.labl, Priority)
+}
+
+func (p *prgcor) Reason() {
+	delete(p.labl, Reason)
+}
+
 func (p *prgcor) Worker() {
 	delete(p.labl, Worker)
 }
@@ -26,18 +26,46 @@ func (s *setcor) Cancel(x int64) {
 	s.labl[Cancel] = strconv.FormatInt(x, 10)
 }
 
+func (s *setcor) Coalesce(x string) {
+	s.labl[Coalesce] = x
+}
+
+func (s *setcor) Complete(x string) {
+	s.labl[Complete] = x
+}
+
 func (s *setcor) Cycles(x int64) {
 	s.labl[Cycles] = strconv.FormatInt(x, 10)
 }
 
+func (s *setcor) Deadline(x time.Time) {
+	s.labl[Deadline] = x.Format(ticker.Layout)
+}
+
+func (s *setcor) Dedupe(x string) {
+	s.labl[Dedupe] = x
+}
+
 func (s *setcor) Expiry(x time.Time) {
 	s.labl[Expiry] = x.Format(ticker.Layout)
 }
 
+func (s *setcor) Lease(x string) {
+	s.labl[Lease] = x
+}
+
 func (s *setcor) Object(x objectid.ID) {
 	s.labl[Object] = string(x)
 }
 
+func (s *setcor) Priority(x int64) {
+	s.labl[Priority] = strconv.FormatInt(x, 10)
+}
+
+func (s *setcor) Reason(x string) {
+	s.labl[Reason] = x
+}
+
 func (s *setcor) Worker(x string) {
 	s.labl[Worker] = x
 }
@@ -7,7 +7,7 @@ const (
 	//
 	//     all    delivered to all workers within the network (timeline based)
 	//     any    delivered to any worker within the network (default method)
-	//     mny    delivered to many specific workers within the network (not implemented)
+	//     mny    delivered to many specific workers within the network (worker based)
 	//     uni    delivered to a single specific worker within the network (worker based)
 	//
 	Method = "addr.rescue.io/method"
@@ -35,6 +35,24 @@ const (
 	// successfully.
 	Cancel = "task.rescue.io/cancel"
 
+	// Coalesce is the optional comma separated list of Task.Meta keys, that
+	// makes a new task supersede an older task, if both tasks define the same
+	// Task.Meta values for those keys. Instead of creating the new task, the
+	// older task adopts the new task's Task.Sync, deadline, lease and priority,
+	// while keeping its object ID.
+	// Only tasks that are not yet owned by any worker get superseded. That way
+	// only the latest request of e.g. "recompute X" is processed.
+	//
+	//     task.rescue.io/coalesce    x.api.io/object
+	//
+	Coalesce = "task.rescue.io/coalesce"
+
+	// Complete is the comma separated list of workers that completed a task
+	// defining the delivery method "mny". The task is removed from the queue
+	// once every target worker listed in Task.Node completed it. Complete is
+	// managed by the system and must not be defined by users.
+	Complete = "task.rescue.io/complete"
+
 	// Cycles is the number of attempts that workers tried to execute a given
 	// task. This number is being incremented e.g. after ownership expiration,
 	// resulting in rescheduling so that other workers can take over task
@@ -43,6 +61,24 @@ const (
 	// balanced accordingly.
 	Cycles = "task.rescue.io/cycles"
 
+	// Deadline is the optional timestamp after which a task is not worth being
+	// processed anymore, e.g. a notification for an event that happened
+	// already. Tasks that did not get processed before their deadline passed
+	// are removed from the queue, or moved into the dead-letter queue, if
+	// enabled.
+	//
+	//     task.rescue.io/deadline    2023-09-28T12:00:00.000000Z
+	//
+	Deadline = "task.rescue.io/deadline"
+
+	// Dedupe is the optional idempotency key of a task. Creating a task with a
+	// dedupe key does not insert another task, as long as a task with the same
+	// dedupe key exists already, or completed within the engine's configured
+	// dedupe window. Instead, the object ID of the existing task is returned.
+	// Producers retrying their task creation after e.g. network errors do
+	// then not cause duplicated work.
+	Dedupe = "task.rescue.io/dedupe"
+
 	// Expiry is the unix timestamp of a tasks expiration time. This expiration
 	// time gets set once a worker takes the task for execution. The worker taking
 	// the task becomes the owner and has a certain time window to successfully
@@ -54,9 +90,25 @@ const (
 	// fact and stop executing on the expired task.
 	Expiry = "task.rescue.io/expiry"
 
+	// Lease is the optional time interval expression, e.g. "5 minutes", that
+	// defines the expiry of a task once a worker takes the task for execution.
+	// Tasks not defining a lease expire according to the engine's default
+	// expiry. See task.rescue.io/expiry above.
+	Lease = "task.rescue.io/lease"
+
 	// Object is the identifier of the task within the queue.
 	Object = "task.rescue.io/object"
 
+	// Priority is the optional urgency of a task. Tasks with a higher priority
+	// are claimed before tasks with a lower priority, while tasks of the same
+	// priority are claimed in the order of their creation. Priorities may be
+	// negative and default to 0.
+	Priority = "task.rescue.io/priority"
+
+	// Reason is the optional failure reason that the most recent owner of a
+	// task recorded when releasing the task without completing it.
+	Reason = "task.rescue.io/reason"
+
 	// Worker is the name of the worker executing the task.
 	Worker = "task.rescue.io/worker"
 )
@@ -121,6 +173,19 @@ const (
 	// to be specified.
 	MthdAny = "any"
 
+	// MthdMny is the addressing method to deliver a task to many specific workers
+	// within the network. Using "mny" requires the accompanied usage of the core
+	// label key "task.rescue.io/worker" for specifying a comma separated list of
+	// worker identifiers. Every listed worker processes the task once, without
+	// assigning ownership to any of them. Every worker completing the task is
+	// recorded in Task.Core, and the task is removed from the queue once every
+	// listed worker completed it, or once the engine's retention period passed.
+	//
+	//     addr.rescue.io/method    mny
+	//     task.rescue.io/worker    eon,etw
+	//
+	MthdMny = "mny"
+
 	// MthdUni is the addressing method to deliver a task to a specific worker
 	// within the network. Using "uni" requires the accompanied usage of the core
 	// label key "task.rescue.io/worker" for specifying a particular identifier.
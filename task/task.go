@@ -105,14 +105,22 @@ type Task struct {
 
 	// Node contains addressable task delivery information for targeting any
 	// addressable worker within the network. The default delivery method is
-	// "any". Tasks may be processed by "all" workers within the network without
-	// acknowledgement of completion. Any particular worker may be addressed like
-	// shown below. Tasks not being addressed within a configured retention period
-	// are being deleted.
+	// "any". Tasks may be processed by "all" workers within the network, each of
+	// them recording their completion within the task, without the task being
+	// deleted upon completion. Any particular worker may be addressed like shown
+	// below. Tasks not being addressed within a configured retention period are
+	// being deleted.
 	//
 	//     addr.rescue.io/method    uni
 	//     task.rescue.io/worker    90dc68ba-4820-42ac-a924-2450388c15a6
 	//
+	// Many particular workers may be addressed using a comma separated list of
+	// worker identifiers. Every listed worker processes the task once, and the
+	// task is deleted once every listed worker acknowledged its completion.
+	//
+	//     addr.rescue.io/method    mny
+	//     task.rescue.io/worker    90dc68ba-4820-42ac-a924-2450388c15a6,a8c2e0f1-37b5-4d0e-9b4e-6f1d2c3b4a59
+	//
 	Node *Node `json:"node,omitempty"`
 
 	// Root allows to manage a tree of dependencies. Consider task x and y, where
@@ -4,6 +4,7 @@ import "time"
 
 type Timer struct {
 	fac func() time.Time
+	wai func(time.Duration) (<-chan time.Time, func())
 }
 
 func New() *Timer {
@@ -11,9 +12,20 @@ func New() *Timer {
 		fac: func() time.Time {
 			return time.Now().UTC()
 		},
+		wai: func(dur time.Duration) (<-chan time.Time, func()) {
+			tim := time.NewTimer(dur)
+			return tim.C, func() { tim.Stop() }
+		},
 	}
 }
 
+// After returns a channel receiving a value once the given duration passed,
+// together with a function releasing the underlying resources, in case the
+// caller stops waiting before.
+func (t *Timer) After(dur time.Duration) (<-chan time.Time, func()) {
+	return t.wai(dur)
+}
+
 func (t *Timer) Create() time.Time {
 	return t.fac()
 }
@@ -34,6 +46,18 @@ func (t *Timer) Extend() time.Time {
 	return t.fac()
 }
 
+func (t *Timer) Heartbeat() time.Time {
+	return t.fac()
+}
+
+func (t *Timer) Redrive() time.Time {
+	return t.fac()
+}
+
+func (t *Timer) Release() time.Time {
+	return t.fac()
+}
+
 func (t *Timer) Search() time.Time {
 	return t.fac()
 }
@@ -45,3 +69,9 @@ func (t *Timer) Ticker() time.Time {
 func (t *Timer) Setter(fac func() time.Time) {
 	t.fac = fac
 }
+
+// Waiter replaces the way After waits for the given duration to pass, e.g. in
+// order to control background processes in tests.
+func (t *Timer) Waiter(wai func(time.Duration) (<-chan time.Time, func())) {
+	t.wai = wai
+}
@@ -0,0 +1,24 @@
+package worker
+
+import (
+	"errors"
+
+	"github.com/xh3b4sd/tracer"
+)
+
+var invalidConfigError = &tracer.Error{
+	Kind: "invalidConfigError",
+}
+
+func IsInvalidConfig(err error) bool {
+	return errors.Is(err, invalidConfigError)
+}
+
+var resultInvalidError = &tracer.Error{
+	Kind: "resultInvalidError",
+	Desc: "When acknowledging tasks, the handler result must either delete the task, requeue it using a paging pointer, or defer it. Requeuing and deferring a task at the same time is not supported.",
+}
+
+func IsResultInvalid(err error) bool {
+	return errors.Is(err, resultInvalidError)
+}
@@ -0,0 +1,60 @@
+package worker
+
+import (
+	"context"
+
+	"github.com/xh3b4sd/rescue/task"
+)
+
+// Handler executes the business logic for all tasks matching the Task.Meta
+// selector that the handler got registered with. The given context is
+// cancelled once the calling worker lost ownership of the given task, or once
+// the worker is shutting down. Returning an error releases the task together
+// with the error message as failure reason, so that it gets picked up again by
+// any worker. Otherwise the returned Result decides how the task is
+// acknowledged.
+type Handler interface {
+	Ensure(ctx context.Context, tas *task.Task) (Result, error)
+}
+
+// HandlerFunc allows plain functions to be registered as Handler.
+type HandlerFunc func(ctx context.Context, tas *task.Task) (Result, error)
+
+func (f HandlerFunc) Ensure(ctx context.Context, tas *task.Task) (Result, error) {
+	return f(ctx, tas)
+}
+
+// Result describes how a processed task is acknowledged. The zero value
+// deletes the task for good. Note that tasks defining Task.Gate or Task.Root
+// cannot be requeued or deferred, and are always deleted.
+type Result struct {
+	// Defer is the optional time interval expression used to execute the task
+	// again later, e.g. "1 minute". See task.Adefer for more information.
+	Defer string
+	// Paging is the optional paging pointer used to requeue the task, so that
+	// it is processed again right away, starting at the given progress
+	// indicator. See task.Paging for more information.
+	Paging string
+}
+
+// Delete returns the Result for tasks that completed successfully.
+func Delete() Result {
+	return Result{}
+}
+
+// Defer returns the Result for tasks that have to be executed again after the
+// given time interval expression elapsed, e.g. "1 minute".
+func Defer(exp string) Result {
+	return Result{Defer: exp}
+}
+
+// Requeue returns the Result for tasks that have to be processed again right
+// away, starting at the given paging pointer.
+func Requeue(pag string) Result {
+	return Result{Paging: pag}
+}
+
+type route struct {
+	han Handler
+	met *task.Meta
+}
@@ -0,0 +1,315 @@
+package worker
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/xh3b4sd/logger"
+	"github.com/xh3b4sd/rescue"
+	"github.com/xh3b4sd/rescue/engine"
+	"github.com/xh3b4sd/rescue/task"
+	"github.com/xh3b4sd/tracer"
+)
+
+const (
+	// Expire is the default interval at which Engine.Expire is called.
+	Expire = 10 * time.Second
+	// Heartbeat is the default interval at which Engine.Heartbeat is called.
+	Heartbeat = 10 * time.Second
+	// Search is the default interval after which Engine.SearchWait is called
+	// again, if it failed.
+	Search = time.Second
+	// Ticker is the default interval at which Engine.Ticker is called.
+	Ticker = 10 * time.Second
+)
+
+type Config struct {
+	Engine    rescue.Interface
+	Expire    time.Duration
+	Heartbeat time.Duration
+	Logger    logger.Interface
+	Parallel  int
+	Search    time.Duration
+	Ticker    time.Duration
+}
+
+// Worker runs the common loop of claiming tasks, executing them using the
+// handler registered for the respective task, and acknowledging them
+// afterwards. Tasks are leased using Engine.Lease while being processed. Next to
+// processing tasks, Worker calls Engine.Expire, Engine.Heartbeat and
+// Engine.Ticker on their respective intervals.
+type Worker struct {
+	eng rescue.Interface
+	exp time.Duration
+	hrt time.Duration
+	log logger.Interface
+	par int
+	rou []route
+	sea time.Duration
+	tic time.Duration
+}
+
+func New(config Config) (*Worker, error) {
+	if config.Engine == nil {
+		return nil, tracer.Maskf(invalidConfigError, "%T.Engine must not be empty", config)
+	}
+	if config.Expire == 0 {
+		config.Expire = Expire
+	}
+	if config.Heartbeat == 0 {
+		config.Heartbeat = Heartbeat
+	}
+	if config.Logger == nil {
+		config.Logger = logger.Default()
+	}
+	if config.Parallel == 0 {
+		config.Parallel = 1
+	}
+	if config.Search == 0 {
+		config.Search = Search
+	}
+	if config.Ticker == 0 {
+		config.Ticker = Ticker
+	}
+
+	w := &Worker{
+		eng: config.Engine,
+		exp: config.Expire,
+		hrt: config.Heartbeat,
+		log: config.Logger,
+		par: config.Parallel,
+		sea: config.Search,
+		tic: config.Ticker,
+	}
+
+	return w, nil
+}
+
+// Register adds the given handler for all tasks matching the given Task.Meta
+// selector. Selectors are matched the same way Task.Meta.Has matches labels,
+// so that the asterisk may be used as a wildcard for any key or value. Tasks
+// are processed by the first handler whose selector matches. Register must
+// only be called before Run.
+func (w *Worker) Register(met *task.Meta, han Handler) {
+	w.rou = append(w.rou, route{han: han, met: met})
+}
+
+// Run processes tasks until the given context is done. Run blocks until all
+// tasks in progress returned.
+func (w *Worker) Run(ctx context.Context) error {
+	var wai sync.WaitGroup
+
+	for i := 0; i < w.par; i++ {
+		wai.Add(1)
+
+		go func() {
+			defer wai.Done()
+			w.search(ctx)
+		}()
+	}
+
+	wai.Add(3)
+
+	go func() {
+		defer wai.Done()
+		w.repeat(ctx, w.exp, w.eng.Expire)
+	}()
+
+	// The very first heartbeat is sent right away, so that other workers
+	// account for this worker as soon as possible.
+	go func() {
+		defer wai.Done()
+
+		err := w.eng.Heartbeat()
+		if err != nil {
+			w.lerror(tracer.Mask(err))
+		}
+
+		w.repeat(ctx, w.hrt, w.eng.Heartbeat)
+	}()
+
+	go func() {
+		defer wai.Done()
+		w.repeat(ctx, w.tic, w.eng.Ticker)
+	}()
+
+	{
+		wai.Wait()
+	}
+
+	return nil
+}
+
+// ensure executes the handler registered for the given task, and acknowledges
+// the task according to the handler's result. The task is leased while the
+// handler is running.
+func (w *Worker) ensure(ctx context.Context, tas *task.Task) {
+	var han Handler
+	for _, x := range w.rou {
+		if tas.Meta.Has(*x.met) {
+			han = x.han
+			break
+		}
+	}
+
+	// Tasks for which no handler got registered are handed back to the queue
+	// right away, so that other workers do not have to wait for their expiry in
+	// order to process them.
+	if han == nil {
+		w.log.Log(
+			"level", "warning",
+			"message", "no handler registered for task",
+			"object", tas.Core.Map().Object(),
+		)
+
+		err := w.eng.Release(tas, engine.ReleaseOptions{Reason: "no handler registered for task"})
+		if err != nil {
+			w.lerror(tracer.Mask(err))
+		}
+
+		return
+	}
+
+	// Losing ownership of the task means that the handler must stop processing
+	// it, because the task may be processed by another worker already.
+	var lea context.Context
+	var can context.CancelFunc
+	{
+		lea, can = w.eng.Lease(ctx, tas)
+	}
+
+	res, err := han.Ensure(lea, tas)
+
+	// The lease got lost if its context got cancelled while the worker is not
+	// shutting down.
+	var out bool
+	{
+		out = lea.Err() != nil && ctx.Err() == nil
+		can()
+	}
+
+	if out {
+		w.lerror(tracer.Mask(context.Cause(lea)))
+		return
+	}
+
+	// Failed tasks are handed back to the queue right away, so that they do
+	// not have to wait for their expiry in order to be retried.
+	if err != nil {
+		w.lerror(tracer.Mask(err))
+
+		err = w.eng.Release(tas, engine.ReleaseOptions{Reason: err.Error()})
+		if err != nil {
+			w.lerror(tracer.Mask(err))
+		}
+
+		return
+	}
+
+	{
+		err = w.result(tas, res)
+		if err != nil {
+			w.lerror(tracer.Mask(err))
+			return
+		}
+	}
+
+	{
+		err = w.eng.Delete(tas)
+		if err != nil {
+			w.lerror(tracer.Mask(err))
+			return
+		}
+	}
+}
+
+func (w *Worker) lerror(err error) {
+	w.log.Log(
+		"level", "error",
+		"message", err.Error(),
+		"stack", tracer.Stack(err),
+	)
+}
+
+// repeat calls fun on the given interval until the given context is done.
+func (w *Worker) repeat(ctx context.Context, itv time.Duration, fun func() error) {
+	tic := time.NewTicker(itv)
+	defer tic.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-tic.C:
+		}
+
+		// Tasks that could not be revoked are not an issue, since any remaining
+		// imbalance is resolved during the next call.
+		err := fun()
+		if err != nil && !engine.IsTaskNotRevoked(err) {
+			w.lerror(tracer.Mask(err))
+		}
+	}
+}
+
+// result prepares the given task for being acknowledged using Engine.Delete.
+// Deleting a task that defines a paging pointer or @defer causes the task to
+// be requeued instead of being deleted for good.
+func (w *Worker) result(tas *task.Task, res Result) error {
+	if res.Defer != "" && res.Paging != "" {
+		return tracer.Maskf(resultInvalidError, "Result.Defer and Result.Paging must not be configured together")
+	}
+
+	// Tasks are only deferred if @defer is the only label in Task.Cron. Tasks
+	// that got deferred before define tick+1 already, which is why we remove
+	// tick+1 here.
+	if res.Defer != "" {
+		if tas.Cron == nil {
+			tas.Cron = &task.Cron{}
+		}
+
+		tas.Cron.Set().Adefer(res.Defer)
+		tas.Cron.Prg().TickP1()
+	}
+
+	if res.Paging != "" {
+		if tas.Sync == nil {
+			tas.Sync = &task.Sync{}
+		}
+
+		tas.Sync.Set(task.Paging, res.Paging)
+	}
+
+	// Tasks that got requeued before define a paging pointer already, which has
+	// to be reset in order to delete the task for good.
+	if res.Defer == "" && res.Paging == "" && tas.Pag() {
+		tas.Sync.Set(task.Paging, "0")
+	}
+
+	return nil
+}
+
+// search claims tasks one after another until the given context is done.
+func (w *Worker) search(ctx context.Context) {
+	for {
+		tas, err := w.eng.SearchWait(ctx)
+		if ctx.Err() != nil {
+			return
+		} else if err != nil {
+			w.lerror(tracer.Mask(err))
+
+			select {
+			case <-ctx.Done():
+				return
+			case <-time.After(w.sea):
+			}
+
+			continue
+		}
+
+		{
+			w.ensure(ctx, tas)
+		}
+	}
+}
@@ -0,0 +1,293 @@
+package worker
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+
+	"github.com/xh3b4sd/logger"
+	"github.com/xh3b4sd/rescue/engine"
+	"github.com/xh3b4sd/rescue/store"
+	"github.com/xh3b4sd/rescue/task"
+)
+
+func Test_Worker_Run(t *testing.T) {
+	var err error
+
+	var eng *engine.Engine
+	{
+		eng = engine.New(engine.Config{
+			Logger: logger.Fake(),
+			Store:  store.NewMemory(),
+		})
+	}
+
+	var wrk *Worker
+	{
+		wrk, err = New(Config{
+			Engine:   eng,
+			Logger:   logger.Fake(),
+			Parallel: 3,
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	var mut sync.Mutex
+	var pag []string
+	var wai sync.WaitGroup
+
+	// Tasks for deleting objects complete right away.
+	wrk.Register(&task.Meta{"test.api.io/action": "delete", "test.api.io/object": "*"}, HandlerFunc(func(ctx context.Context, tas *task.Task) (Result, error) {
+		defer wai.Done()
+		return Delete(), nil
+	}))
+
+	// Tasks for syncing objects get requeued once, using a paging pointer.
+	wrk.Register(&task.Meta{"test.api.io/action": "sync"}, HandlerFunc(func(ctx context.Context, tas *task.Task) (Result, error) {
+		defer wai.Done()
+
+		mut.Lock()
+		pag = append(pag, tas.Sync.Get(task.Paging))
+		mut.Unlock()
+
+		if tas.Sync.Get(task.Paging) == "" {
+			return Requeue("p1"), nil
+		}
+
+		return Delete(), nil
+	}))
+
+	var rea []string
+
+	// Tasks for retrying objects fail once, and get released right away.
+	wrk.Register(&task.Meta{"test.api.io/action": "retry"}, HandlerFunc(func(ctx context.Context, tas *task.Task) (Result, error) {
+		defer wai.Done()
+
+		mut.Lock()
+		rea = append(rea, tas.Core.Get().Reason())
+		mut.Unlock()
+
+		if tas.Core.Get().Cycles() == 0 {
+			return Result{}, errors.New("test error")
+		}
+
+		return Delete(), nil
+	}))
+
+	for _, x := range []string{"1", "2", "3", "4"} {
+		err = eng.Create(&task.Task{Meta: &task.Meta{"test.api.io/action": "delete", "test.api.io/object": x}})
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	{
+		err = eng.Create(&task.Task{Meta: &task.Meta{"test.api.io/action": "sync"}})
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	{
+		err = eng.Create(&task.Task{Meta: &task.Meta{"test.api.io/action": "retry"}})
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	{
+		wai.Add(8)
+	}
+
+	ctx, can := context.WithCancel(context.Background())
+
+	var don chan struct{}
+	{
+		don = make(chan struct{})
+	}
+
+	go func() {
+		defer close(don)
+
+		err := wrk.Run(ctx)
+		if err != nil {
+			panic(err)
+		}
+	}()
+
+	{
+		wai.Wait()
+	}
+
+	{
+		can()
+		<-don
+	}
+
+	{
+		if len(pag) != 2 || pag[0] != "" || pag[1] != "p1" {
+			t.Fatal("expected", []string{"", "p1"}, "got", pag)
+		}
+		if len(rea) != 2 || rea[0] != "" || rea[1] == "" {
+			t.Fatal("expected", "failure reason", "got", rea)
+		}
+	}
+
+	{
+		lis, err := eng.Lister(engine.All())
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if len(lis) != 0 {
+			t.Fatal("expected", 0, "got", len(lis))
+		}
+	}
+}
+
+func Test_Worker_Ensure_Unhandled(t *testing.T) {
+	var err error
+
+	var eng *engine.Engine
+	{
+		eng = engine.New(engine.Config{
+			Logger: logger.Fake(),
+			Store:  store.NewMemory(),
+		})
+	}
+
+	var wrk *Worker
+	{
+		wrk, err = New(Config{
+			Engine: eng,
+			Logger: logger.Fake(),
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	wrk.Register(&task.Meta{"test.api.io/action": "delete"}, HandlerFunc(func(ctx context.Context, tas *task.Task) (Result, error) {
+		return Delete(), nil
+	}))
+
+	{
+		err = eng.Create(&task.Task{Meta: &task.Meta{"test.api.io/action": "sync"}})
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	var tas *task.Task
+	{
+		tas, err = eng.Search()
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	// Tasks without handler are released right away, instead of being left to
+	// expire.
+	{
+		wrk.ensure(context.Background(), tas)
+	}
+
+	{
+		lis, err := eng.Lister(engine.All())
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if len(lis) != 1 {
+			t.Fatal("expected", 1, "got", len(lis))
+		}
+		if lis[0].Core.Exi().Worker() {
+			t.Fatal("expected", "released task", "got", lis[0].Core.Get().Worker())
+		}
+		if lis[0].Core.Get().Reason() == "" {
+			t.Fatal("expected", "failure reason", "got", "")
+		}
+	}
+}
+
+func Test_Worker_Result(t *testing.T) {
+	var w *Worker
+	{
+		w = &Worker{}
+	}
+
+	{
+		tas := &task.Task{Cron: &task.Cron{task.Adefer: "1 minute", task.TickP1: "2023-10-20T00:00:00Z"}}
+
+		err := w.result(tas, Defer("2 minutes"))
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if tas.Cron.Len() != 1 || tas.Cron.Get().Adefer() != "2 minutes" {
+			t.Fatal("expected", "2 minutes", "got", tas.Cron)
+		}
+	}
+
+	{
+		tas := &task.Task{Cron: &task.Cron{task.TickP1: "2023-10-20T00:00:00Z"}}
+
+		err := w.result(tas, Defer("2 minutes"))
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if tas.Cron.Len() != 1 || tas.Cron.Get().Adefer() != "2 minutes" {
+			t.Fatal("expected", "2 minutes", "got", tas.Cron)
+		}
+	}
+
+	{
+		tas := &task.Task{}
+
+		err := w.result(tas, Defer("2 minutes"))
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if tas.Cron.Len() != 1 || tas.Cron.Get().Adefer() != "2 minutes" {
+			t.Fatal("expected", "2 minutes", "got", tas.Cron)
+		}
+	}
+
+	{
+		tas := &task.Task{}
+
+		err := w.result(tas, Requeue("p2"))
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if tas.Sync.Get(task.Paging) != "p2" {
+			t.Fatal("expected", "p2", "got", tas.Sync.Get(task.Paging))
+		}
+	}
+
+	{
+		tas := &task.Task{Sync: &task.Sync{task.Paging: "p2"}}
+
+		err := w.result(tas, Delete())
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if tas.Pag() {
+			t.Fatal("expected", "0", "got", tas.Sync.Get(task.Paging))
+		}
+	}
+
+	{
+		err := w.result(&task.Task{}, Result{Defer: "1 minute", Paging: "p3"})
+		if !IsResultInvalid(err) {
+			t.Fatal("expected", resultInvalidError, "got", err)
+		}
+	}
+}